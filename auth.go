@@ -0,0 +1,238 @@
+package kmip
+
+import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/ansel1/merry"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Authenticator authenticates the client which sent a request.  It is called by the
+// StandardProtocolHandler before the request is passed to the MessageHandler.  Once a
+// client is authenticated, the Session remembers its credentials, and the Authenticator
+// is only called again when a request on the session carries different credentials, or
+// the session's principal is changed with SetPrincipal.
+//
+// Authenticate is passed the request, whose TLS field holds the TLS state of the connection,
+// and the credentials from the request header, with their values decoded into the types
+// in CredentialValueTypes.  It returns the principal, an identity of the client which is
+// meaningful to the server's handlers, like a user name.  The principal is stored
+// in the Request and the Session.
+//
+// If the client can't be authenticated, Authenticate should return an error.  The request
+// fails with ResultReasonAuthenticationNotSuccessful, unless the error has another result
+// reason (see WithResultReason).
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *Request, credentials []Credential) (principal interface{}, err error)
+}
+
+type AuthenticatorFunc func(ctx context.Context, req *Request, credentials []Credential) (interface{}, error)
+
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, req *Request, credentials []Credential) (interface{}, error) {
+	return f(ctx, req, credentials)
+}
+
+// ErrAuthenticationFailed is returned by the Authenticators in this package when
+// the client can't be authenticated.
+var ErrAuthenticationFailed = WithResultReason(merry.New("authentication failed"), kmip14.ResultReasonAuthenticationNotSuccessful)
+
+// CredentialValueTypes maps credential types to the types credential values are
+// decoded into, by ResolveType and before they are passed to an Authenticator.
+var CredentialValueTypes = map[kmip14.CredentialType]reflect.Type{
+	kmip14.CredentialTypeUsernameAndPassword: reflect.TypeFor[*UsernameAndPasswordCredentialValue](),
+	kmip14.CredentialTypeDevice:              reflect.TypeFor[*DeviceCredentialValue](),
+	kmip14.CredentialTypeAttestation:         reflect.TypeFor[*AttestationCredentialValue](),
+}
+
+// decodeCredentials returns copies of the credentials, whose TTLV values are decoded
+// into the types in CredentialValueTypes.  Values which are already decoded, or which
+// have no registered type, are copied as is.
+func decodeCredentials(auth *Authentication) ([]Credential, error) {
+	if auth == nil {
+		return nil, nil
+	}
+
+	creds := make([]Credential, len(auth.Credential))
+
+	for i, c := range auth.Credential {
+		creds[i] = c
+
+		v, ok := c.CredentialValue.(ttlv.TTLV)
+		if !ok {
+			continue
+		}
+
+		typ := CredentialValueTypes[c.CredentialType]
+		if typ == nil {
+			continue
+		}
+
+		ptr := reflect.New(typ.Elem())
+		if err := ttlv.Unmarshal(v, ptr.Interface()); err != nil {
+			return nil, WithResultReason(merry.Prependf(err, "invalid %v credential", c.CredentialType), kmip14.ResultReasonInvalidField)
+		}
+
+		creds[i].CredentialValue = ptr.Interface()
+	}
+
+	return creds, nil
+}
+
+// CertificateAuthenticator authenticates clients by the certificate they presented in the
+// TLS handshake.  The certificate must have been verified, so the server's tls.Config should
+// set ClientAuth to tls.RequireAndVerifyClientCert or tls.VerifyClientCertIfGiven.
+//
+// The names of a certificate are the DNS names, email addresses, and URIs in its subject
+// alternative names, followed by its subject common name.  The principal is a string.
+type CertificateAuthenticator struct {
+	// Names maps certificate names to principals.  The principal of the first of the
+	// certificate's names found in Names is returned.  If Names is nil, the first of
+	// the certificate's names is the principal.
+	Names map[string]string
+}
+
+func (a *CertificateAuthenticator) Authenticate(_ context.Context, req *Request, _ []Credential) (interface{}, error) {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return nil, merry.Append(ErrAuthenticationFailed, "no verified client certificate")
+	}
+
+	for _, name := range certificateNames(req.TLS.VerifiedChains[0][0]) {
+		if a.Names == nil {
+			return name, nil
+		}
+
+		if p, ok := a.Names[name]; ok {
+			return p, nil
+		}
+	}
+
+	return nil, merry.Append(ErrAuthenticationFailed, "unknown client certificate")
+}
+
+// certificateNames returns the SAN names of the certificate, then its common name.
+func certificateNames(cert *x509.Certificate) []string {
+	names := make([]string, 0, len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.URIs)+1)
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+
+	for _, u := range cert.URIs {
+		names = append(names, u.String())
+	}
+
+	if cn := cert.Subject.CommonName; cn != "" {
+		names = append(names, cn)
+	}
+
+	return names
+}
+
+// PasswordFileAuthenticator authenticates clients with the Username and Password credential,
+// checking the password against the bcrypt hashes in an htpasswd-style password file.  The
+// principal is the user name.
+//
+// Each line of the file holds a user name and a bcrypt hash, separated by a colon, as
+// written by "htpasswd -B".  Blank lines, and lines starting with "#", are ignored.
+//
+//	alice:$2y$10$...
+type PasswordFileAuthenticator struct {
+	mu     sync.RWMutex
+	hashes map[string][]byte
+}
+
+// LoadPasswordFile reads a password file.
+func LoadPasswordFile(name string) (*PasswordFileAuthenticator, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, merry.Wrap(err)
+	}
+	defer f.Close()
+
+	return ParsePasswordFile(f)
+}
+
+// ParsePasswordFile reads a password file from r.
+func ParsePasswordFile(r io.Reader) (*PasswordFileAuthenticator, error) {
+	a := &PasswordFileAuthenticator{}
+	if err := a.Load(r); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Load replaces the users of the authenticator with those read from r.  If the file
+// is invalid, the users are unchanged.
+func (a *PasswordFileAuthenticator) Load(r io.Reader) error {
+	hashes := map[string][]byte{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok || user == "" {
+			return merry.Errorf("line %d: expected user:hash", n)
+		}
+
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return merry.Prependf(err, "line %d: user %s: unsupported password hash, only bcrypt is supported", n, user)
+		}
+
+		hashes[user] = []byte(hash)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return merry.Wrap(err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.hashes = hashes
+
+	return nil
+}
+
+// dummyHash is compared against the passwords of unknown users, so failures take
+// as long for unknown users as for wrong passwords.
+var dummyHash = sync.OnceValue(func() []byte {
+	h, _ := bcrypt.GenerateFromPassword([]byte("dummy"), bcrypt.DefaultCost)
+	return h
+})
+
+func (a *PasswordFileAuthenticator) Authenticate(_ context.Context, _ *Request, credentials []Credential) (interface{}, error) {
+	for _, c := range credentials {
+		v, ok := c.CredentialValue.(*UsernameAndPasswordCredentialValue)
+		if !ok {
+			continue
+		}
+
+		a.mu.RLock()
+		hash, known := a.hashes[v.Username]
+		a.mu.RUnlock()
+
+		if !known {
+			hash = dummyHash()
+		}
+
+		if err := bcrypt.CompareHashAndPassword(hash, []byte(v.Password)); err != nil || !known {
+			return nil, merry.Append(ErrAuthenticationFailed, "invalid user name or password")
+		}
+
+		return v.Username, nil
+	}
+
+	return nil, merry.Append(ErrAuthenticationFailed, "no username and password credential")
+}
@@ -0,0 +1,252 @@
+package kmip
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testPasswordFile returns a password file with the users and passwords.
+func testPasswordFile(t *testing.T, users ...string) string {
+	t.Helper()
+
+	var sb strings.Builder
+
+	sb.WriteString("# test users\n\n")
+
+	for i := 0; i < len(users); i += 2 {
+		hash, err := bcrypt.GenerateFromPassword([]byte(users[i+1]), bcrypt.MinCost)
+		require.NoError(t, err)
+		fmt.Fprintf(&sb, "%s:%s\n", users[i], hash)
+	}
+
+	return sb.String()
+}
+
+// serveAuthenticated serves msg with an Authenticator, and returns the request and the decoded response.
+func serveAuthenticated(t *testing.T, a Authenticator, msg RequestMessage, tlsState *tls.ConnectionState) (*Request, ResponseMessage) {
+	t.Helper()
+
+	h := testProtocolHandler()
+	h.Authenticator = a
+
+	reqTTLV, err := ttlv.Marshal(msg)
+	require.NoError(t, err)
+
+	req := &Request{TTLV: reqTTLV, TLS: tlsState, Session: NewSession("remote", "local", tlsState)}
+
+	var buf bytes.Buffer
+
+	h.ServeKMIP(context.Background(), req, &buf)
+
+	var resp ResponseMessage
+	require.NoError(t, ttlv.Unmarshal(buf.Bytes(), &resp))
+
+	return req, resp
+}
+
+func withCredentials(msg RequestMessage, creds ...Credential) RequestMessage {
+	msg.RequestHeader.Authentication = &Authentication{Credential: creds}
+	return msg
+}
+
+func TestPasswordFileAuthenticator(t *testing.T) {
+	a, err := ParsePasswordFile(strings.NewReader(testPasswordFile(t, "alice", "secret", "bob", "hunter2")))
+	require.NoError(t, err)
+
+	usernameAndPassword := func(user, password string) Credential {
+		return Credential{
+			CredentialType:  kmip14.CredentialTypeUsernameAndPassword,
+			CredentialValue: UsernameAndPasswordCredentialValue{Username: user, Password: password},
+		}
+	}
+
+	req, resp := serveAuthenticated(t, a, withCredentials(testRequestMessage(), usernameAndPassword("alice", "secret")), nil)
+	require.Len(t, resp.BatchItem, 1)
+	assert.Equal(t, kmip14.ResultStatusSuccess, resp.BatchItem[0].ResultStatus, resp.BatchItem[0].ResultMessage)
+	assert.Equal(t, "alice", req.Principal)
+	assert.Equal(t, "alice", req.Session.Principal())
+
+	tests := map[string]RequestMessage{
+		"wrong password":   withCredentials(testRequestMessage(), usernameAndPassword("alice", "hunter2")),
+		"unknown user":     withCredentials(testRequestMessage(), usernameAndPassword("carol", "secret")),
+		"no credentials":   testRequestMessage(),
+		"wrong credential": withCredentials(testRequestMessage(), Credential{CredentialType: kmip14.CredentialTypeDevice, CredentialValue: DeviceCredentialValue{DeviceSerialNumber: "alice", Password: "secret"}}),
+	}
+
+	for name, msg := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, resp := serveAuthenticated(t, a, msg, nil)
+			require.Len(t, resp.BatchItem, 1)
+			assert.Equal(t, 1, resp.ResponseHeader.BatchCount)
+			assert.Equal(t, kmip14.ResultStatusOperationFailed, resp.BatchItem[0].ResultStatus)
+			assert.Equal(t, kmip14.ResultReasonAuthenticationNotSuccessful, resp.BatchItem[0].ResultReason)
+			// details of the failure aren't returned to the client
+			assert.Equal(t, kmip14.ResultReasonAuthenticationNotSuccessful.String(), resp.BatchItem[0].ResultMessage)
+			assert.Nil(t, req.Principal)
+			assert.Nil(t, req.Session.Principal())
+		})
+	}
+}
+
+func TestStandardProtocolHandler_Authenticator_session(t *testing.T) {
+	var calls int
+
+	h := testProtocolHandler()
+	h.Authenticator = AuthenticatorFunc(func(ctx context.Context, req *Request, credentials []Credential) (interface{}, error) {
+		calls++
+		return credentials[0].CredentialValue.(*UsernameAndPasswordCredentialValue).Username, nil //nolint:forcetypeassert
+	})
+
+	session := NewSession("remote", "local", nil)
+	timeStamp := time.Unix(0, 0)
+
+	serve := func(user string) *Request {
+		t.Helper()
+
+		msg := withCredentials(testRequestMessage(), Credential{
+			CredentialType:  kmip14.CredentialTypeUsernameAndPassword,
+			CredentialValue: UsernameAndPasswordCredentialValue{Username: user, Password: "secret"},
+		})
+
+		// the rest of the header changes with each request
+		timeStamp = timeStamp.Add(time.Second)
+		msg.RequestHeader.TimeStamp = &timeStamp
+
+		reqTTLV, err := ttlv.Marshal(msg)
+		require.NoError(t, err)
+
+		req := &Request{TTLV: reqTTLV, Session: session}
+
+		var buf bytes.Buffer
+
+		h.ServeKMIP(context.Background(), req, &buf)
+
+		return req
+	}
+
+	// the session remembers the credentials, so they're only checked once
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "alice", serve("alice").Principal)
+	}
+
+	assert.Equal(t, 1, calls)
+
+	// until they change
+	assert.Equal(t, "bob", serve("bob").Principal)
+	assert.Equal(t, "bob", session.Principal())
+	assert.Equal(t, 2, calls)
+
+	// or the principal is changed
+	session.SetPrincipal(nil)
+	assert.Equal(t, "bob", serve("bob").Principal)
+	assert.Equal(t, 3, calls)
+}
+
+func TestParsePasswordFile(t *testing.T) {
+	tests := map[string]string{
+		"missing hash":     "alice\n",
+		"missing user":     ":$2y$10$abc\n",
+		"unsupported hash": "alice:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n",
+	}
+
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParsePasswordFile(strings.NewReader(file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "line 1")
+		})
+	}
+
+	// a failed load leaves the users unchanged
+	a, err := ParsePasswordFile(strings.NewReader(testPasswordFile(t, "alice", "secret")))
+	require.NoError(t, err)
+	require.Error(t, a.Load(strings.NewReader("alice\n")))
+
+	p, err := a.Authenticate(context.Background(), nil, []Credential{{
+		CredentialType:  kmip14.CredentialTypeUsernameAndPassword,
+		CredentialValue: &UsernameAndPasswordCredentialValue{Username: "alice", Password: "secret"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", p)
+}
+
+func TestCertificateAuthenticator(t *testing.T) {
+	u, err := url.Parse("spiffe://example.com/kms-client")
+	require.NoError(t, err)
+
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "client1"},
+		DNSNames:       []string{"client1.example.com"},
+		EmailAddresses: []string{"ops@example.com"},
+		URIs:           []*url.URL{u},
+	}
+
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+
+	tests := []struct {
+		name      string
+		names     map[string]string
+		tlsState  *tls.ConnectionState
+		principal interface{}
+	}{
+		{name: "first name", tlsState: verified, principal: "client1.example.com"},
+		{name: "common name", names: map[string]string{"client1": "tenant1"}, tlsState: verified, principal: "tenant1"},
+		{name: "uri", names: map[string]string{"spiffe://example.com/kms-client": "tenant2", "client1": "tenant1"}, tlsState: verified, principal: "tenant2"},
+		{name: "unknown", names: map[string]string{"client2": "tenant1"}, tlsState: verified},
+		{name: "not verified", tlsState: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+		{name: "no tls"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, resp := serveAuthenticated(t, &CertificateAuthenticator{Names: tc.names}, testRequestMessage(), tc.tlsState)
+			require.Len(t, resp.BatchItem, 1)
+
+			if tc.principal == nil {
+				assert.Equal(t, kmip14.ResultReasonAuthenticationNotSuccessful, resp.BatchItem[0].ResultReason)
+				return
+			}
+
+			assert.Equal(t, kmip14.ResultStatusSuccess, resp.BatchItem[0].ResultStatus, resp.BatchItem[0].ResultMessage)
+			assert.Equal(t, tc.principal, req.Principal)
+		})
+	}
+}
+
+func TestStandardProtocolHandler_Authenticator_resultReason(t *testing.T) {
+	a := AuthenticatorFunc(func(ctx context.Context, req *Request, credentials []Credential) (interface{}, error) {
+		require.Len(t, credentials, 1)
+		assert.Equal(t, &AttestationCredentialValue{
+			Nonce:           Nonce{NonceID: []byte{1}, NonceValue: []byte{2}},
+			AttestationType: kmip14.AttestationTypeTPMQuote,
+		}, credentials[0].CredentialValue)
+
+		return nil, WithResultReason(fmt.Errorf("attestation required"), kmip14.ResultReasonAttestationRequired)
+	})
+
+	msg := withCredentials(testRequestMessage(), Credential{
+		CredentialType: kmip14.CredentialTypeAttestation,
+		CredentialValue: AttestationCredentialValue{
+			Nonce:           Nonce{NonceID: []byte{1}, NonceValue: []byte{2}},
+			AttestationType: kmip14.AttestationTypeTPMQuote,
+		},
+	})
+
+	_, resp := serveAuthenticated(t, a, msg, nil)
+	require.Len(t, resp.BatchItem, 1)
+	assert.Equal(t, kmip14.ResultReasonAttestationRequired, resp.BatchItem[0].ResultReason)
+}
@@ -0,0 +1,594 @@
+package kmip
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/ansel1/merry"
+)
+
+// Authorizer decides whether a principal may perform an operation.  It is called by the
+// OperationMux for each batch item, before the item is passed to its ItemHandler.
+//
+// If the operation isn't allowed, Authorize should return an error.  The batch item fails
+// with ResultReasonPermissionDenied, unless the error has another result reason (see
+// WithResultReason).
+type Authorizer interface {
+	Authorize(ctx context.Context, req *Request, access *Access) error
+}
+
+type AuthorizerFunc func(ctx context.Context, req *Request, access *Access) error
+
+func (f AuthorizerFunc) Authorize(ctx context.Context, req *Request, access *Access) error {
+	return f(ctx, req, access)
+}
+
+// ErrPermissionDenied is returned by the Authorizers in this package when an operation
+// isn't allowed.
+var ErrPermissionDenied = WithResultReason(merry.New("permission denied"), kmip14.ResultReasonPermissionDenied)
+
+// Access describes a batch item to an Authorizer.
+type Access struct {
+	// Principal is the identity of the client, from Request.Principal.
+	Principal interface{}
+	Operation kmip14.Operation
+	// UniqueIdentifier is a target of the operation.  Operations may have several targets,
+	// like the Unique Identifiers of a Derive Key request, or the Private Key Unique Identifier
+	// of a Re-key Key Pair request.  The Authorizer is called once for each target, and the
+	// operation is only allowed if all are.  Values with a tag in TargetIdentifierTags are
+	// targets.  If the payload has none, the ID placeholder is the target.  UniqueIdentifier
+	// is empty for operations which don't act on an existing object, like Create and Locate.
+	UniqueIdentifier string
+	// Attributes are the attributes of the target object, from OperationMux.ObjectAttributes.
+	// For operations without a target, they are the attributes in the request payload, like
+	// the template attributes of a Create request, or the search attributes of a Locate request.
+	Attributes []Attribute
+}
+
+// TargetIdentifierTags are the tags of the values in request payloads which identify the
+// objects an operation acts on.
+var TargetIdentifierTags = map[ttlv.Tag]bool{
+	kmip14.TagUniqueIdentifier:           true,
+	kmip14.TagPrivateKeyUniqueIdentifier: true,
+	kmip14.TagPublicKeyUniqueIdentifier:  true,
+	kmip14.TagReplacedUniqueIdentifier:   true,
+}
+
+// ErrNoTarget is returned for operations which act on an existing object, when the
+// request doesn't identify the object.
+var ErrNoTarget = WithResultReason(merry.New("no target object"), kmip14.ResultReasonPermissionDenied)
+
+// operationsWithoutTarget are the operations which don't act on an existing object,
+// so aren't given the ID placeholder as their target.
+var operationsWithoutTarget = map[kmip14.Operation]bool{
+	kmip14.OperationCreate:           true,
+	kmip14.OperationCreateKeyPair:    true,
+	kmip14.OperationRegister:         true,
+	kmip14.OperationLocate:           true,
+	kmip14.OperationQuery:            true,
+	kmip14.OperationDiscoverVersions: true,
+	kmip14.OperationPoll:             true,
+	kmip14.OperationCancel:           true,
+	kmip14.OperationNotify:           true,
+	kmip14.OperationPut:              true,
+	kmip14.OperationRNGRetrieve:      true,
+	kmip14.OperationRNGSeed:          true,
+}
+
+// accesses returns an Access for each target of the current item of the request, or a
+// single Access for operations without a target.
+func (m *OperationMux) accesses(ctx context.Context, req *Request) ([]*Access, error) {
+	item := req.CurrentItem
+
+	payload, err := payloadTTLV(item.RequestPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	if operationsWithoutTarget[item.Operation] {
+		attrs, err := payloadAttributes(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*Access{{Principal: req.Principal, Operation: item.Operation, Attributes: attrs}}, nil
+	}
+
+	targets := targetIdentifiers(payload, req.IDPlaceholder, nil)
+	if len(targets) == 0 {
+		targets = append(targets, req.IDPlaceholder)
+	}
+
+	accesses := make([]*Access, 0, len(targets))
+
+	for _, id := range targets {
+		if id == "" {
+			// never fall back on the attributes in the payload, which the client controls
+			return nil, merry.Appendf(ErrNoTarget, "%v request has no target", item.Operation)
+		}
+
+		a := &Access{Principal: req.Principal, Operation: item.Operation, UniqueIdentifier: id}
+
+		if m.ObjectAttributes != nil {
+			a.Attributes, err = m.ObjectAttributes(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		accesses = append(accesses, a)
+	}
+
+	return accesses, nil
+}
+
+// targetIdentifiers appends the values of v, and the values nested in v, with tags in
+// TargetIdentifierTags to ids.  Identifiers which aren't text strings, like the KMIP 2.0
+// ID placeholder enumeration, refer to the ID placeholder.
+func targetIdentifiers(v ttlv.TTLV, placeholder string, ids []string) []string {
+	for n := v.ValueStructure(); n != nil; n = n.Next() {
+		switch {
+		case TargetIdentifierTags[n.Tag()]:
+			if n.Type() == ttlv.TypeTextString {
+				ids = append(ids, n.ValueTextString())
+			} else {
+				ids = append(ids, placeholder)
+			}
+		case n.Type() == ttlv.TypeStructure:
+			ids = targetIdentifiers(n, placeholder, ids)
+		}
+	}
+
+	return ids
+}
+
+// payloadTTLV returns the request payload as TTLV, encoding it if it was decoded
+// by a TypeResolver.
+func payloadTTLV(payload interface{}) (ttlv.TTLV, error) {
+	switch t := payload.(type) {
+	case nil:
+		return nil, nil
+	case ttlv.TTLV:
+		return t, nil
+	}
+
+	var buf bytes.Buffer
+	if err := ttlv.NewEncoder(&buf).EncodeValue(kmip14.TagRequestPayload, payload); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// attributesStructures are the KMIP 2.0 structures, like Attributes, which hold attributes
+// by their tags, rather than in Attribute structures.  They are matched by name, so they
+// are only recognized once the 2.0 names are registered, e.g. by the kmip20 package.
+var attributesStructures = map[string]bool{
+	"Attributes":             true,
+	"Common Attributes":      true,
+	"Private Key Attributes": true,
+	"Public Key Attributes":  true,
+}
+
+// payloadAttributes returns the attributes in a request payload, and in the template
+// attributes of the payload.  The attributes of KMIP 2.0 Attributes structures are
+// named by their tags, and their values are TTLV.
+func payloadAttributes(payload ttlv.TTLV) ([]Attribute, error) {
+	var attrs []Attribute
+
+	decode := func(v ttlv.TTLV) error {
+		var attr Attribute
+		if err := ttlv.Unmarshal(v, &attr); err != nil {
+			return err
+		}
+
+		attrs = append(attrs, attr)
+
+		return nil
+	}
+
+	for n := payload.ValueStructure(); n != nil; n = n.Next() {
+		switch {
+		case n.Tag() == kmip14.TagAttribute:
+			if err := decode(n); err != nil {
+				return nil, err
+			}
+		case n.Type() == ttlv.TypeStructure:
+			byTag := attributesStructures[n.Tag().CanonicalName()]
+
+			for c := n.ValueStructure(); c != nil; c = c.Next() {
+				switch {
+				case c.Tag() == kmip14.TagAttribute:
+					if err := decode(c); err != nil {
+						return nil, err
+					}
+				case byTag:
+					attrs = append(attrs, Attribute{
+						AttributeName:  c.Tag().CanonicalName(),
+						AttributeValue: append(ttlv.TTLV(nil), c[:c.FullLen()]...),
+					})
+				}
+			}
+		}
+	}
+
+	return attrs, nil
+}
+
+// attributeText returns the value of a Text String attribute.
+func attributeText(v interface{}) (string, bool) {
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case ttlv.TTLV:
+		if t.Type() == ttlv.TypeTextString {
+			return t.ValueTextString(), true
+		}
+	}
+
+	return "", false
+}
+
+// Policy is an authorization policy for a PolicyAuthorizer.  Principals are granted roles,
+// and roles are granted rules, which allow operations on objects.  Policies are usually read
+// from JSON files:
+//
+//	{
+//	  "principals": {
+//	    "alice": ["tenant-a-admin"],
+//	    "*": ["discovery"]
+//	  },
+//	  "roles": {
+//	    "tenant-a-admin": [
+//	      {"operations": ["Create", "Register", "Locate", "Get", "Destroy"], "objectGroups": ["tenant-a"]},
+//	      {"operations": ["*"], "owners": ["$principal"]}
+//	    ],
+//	    "discovery": [
+//	      {"operations": ["Query", "Discover Versions"]}
+//	    ]
+//	  }
+//	}
+type Policy struct {
+	// Principals maps principals to their roles.  The roles of "*" are granted to
+	// all authenticated principals.  Principals which aren't strings are converted with
+	// fmt.Sprint.
+	Principals map[string][]string `json:"principals"`
+	// Roles maps roles to the rules they grant.
+	Roles map[string][]PolicyRule `json:"roles"`
+	// OwnerAttribute is the name of the attribute holding the owner of an object.
+	// Defaults to "x-Owner".
+	OwnerAttribute string `json:"ownerAttribute,omitempty"`
+}
+
+// PolicyRule allows operations on a set of objects.
+type PolicyRule struct {
+	// Operations are the names of the operations the rule allows, or "*" for all operations.
+	Operations []string `json:"operations"`
+	// ObjectGroups and Owners limit the objects the rule allows operations on.  If neither is
+	// set, the rule allows operations on any object.  Otherwise, the object's Object Group
+	// attribute must be one of ObjectGroups, or its owner attribute must be one of Owners.
+	// The owner "$principal" matches the principal making the request.
+	//
+	// For operations without a target object, the attributes in the request are checked
+	// instead.  For example, a Create request must set the Object Group attribute to one of
+	// ObjectGroups, and a Locate request must search for one of ObjectGroups.  As the client
+	// controls them, every Object Group and owner in the request must be allowed by one of
+	// the principal's rules for the operation, so a Create request can't also put the object
+	// in a group the principal isn't allowed.
+	ObjectGroups []string `json:"objectGroups,omitempty"`
+	Owners       []string `json:"owners,omitempty"`
+}
+
+// ParsePolicy reads a JSON policy from r.
+func ParsePolicy(r io.Reader) (*Policy, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var p Policy
+	if err := dec.Decode(&p); err != nil {
+		return nil, merry.Prepend(err, "invalid policy")
+	}
+
+	if _, err := compilePolicy(&p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// LoadPolicyFile reads a JSON policy file.
+func LoadPolicyFile(name string) (*Policy, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, merry.Wrap(err)
+	}
+	defer f.Close()
+
+	return ParsePolicy(f)
+}
+
+// PolicyAuthorizer is an Authorizer which enforces a Policy.  The policy can be replaced
+// while the authorizer is in use, with SetPolicy or WatchPolicyFile.
+type PolicyAuthorizer struct {
+	policy atomic.Pointer[compiledPolicy]
+}
+
+// NewPolicyAuthorizer returns a PolicyAuthorizer which enforces p.
+func NewPolicyAuthorizer(p *Policy) (*PolicyAuthorizer, error) {
+	a := &PolicyAuthorizer{}
+	if err := a.SetPolicy(p); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// SetPolicy replaces the policy.  If p is invalid, the policy is unchanged.
+func (a *PolicyAuthorizer) SetPolicy(p *Policy) error {
+	cp, err := compilePolicy(p)
+	if err != nil {
+		return err
+	}
+
+	a.policy.Store(cp)
+
+	return nil
+}
+
+// WatchPolicyFile loads the policy from a file, then reloads it whenever the file changes,
+// checking every interval, until ctx is done.  If the file can't be loaded initially, the
+// error is returned.  Later errors are logged, and the previous policy is kept.
+func (a *PolicyAuthorizer) WatchPolicyFile(ctx context.Context, name string, interval time.Duration) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return merry.Wrap(err)
+	}
+
+	load := func() error {
+		p, err := LoadPolicyFile(name)
+		if err != nil {
+			return err
+		}
+
+		return a.SetPolicy(p)
+	}
+
+	if err := load(); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			newInfo, err := os.Stat(name)
+			if err != nil {
+				serverLog.Error("failed to check policy file", "file", name, "err", err)
+				continue
+			}
+
+			if newInfo.ModTime().Equal(info.ModTime()) && newInfo.Size() == info.Size() {
+				continue
+			}
+
+			info = newInfo
+
+			if err := load(); err != nil {
+				serverLog.Error("failed to reload policy file, keeping the previous policy", "file", name, "err", err)
+				continue
+			}
+
+			serverLog.Info("reloaded policy file", "file", name)
+		}
+	}()
+
+	return nil
+}
+
+func (a *PolicyAuthorizer) Authorize(_ context.Context, _ *Request, access *Access) error {
+	p := a.policy.Load()
+	if p == nil || !p.allows(access) {
+		return merry.Appendf(ErrPermissionDenied, "%v is not allowed to %v %s", access.Principal, access.Operation, access.UniqueIdentifier)
+	}
+
+	return nil
+}
+
+type compiledPolicy struct {
+	principals     map[string][]string
+	roles          map[string][]compiledRule
+	ownerAttribute string
+}
+
+type compiledRule struct {
+	allOperations bool
+	operations    map[kmip14.Operation]bool
+	objectGroups  map[string]bool
+	owners        map[string]bool
+}
+
+func compilePolicy(p *Policy) (*compiledPolicy, error) {
+	cp := &compiledPolicy{
+		principals:     p.Principals,
+		roles:          map[string][]compiledRule{},
+		ownerAttribute: p.OwnerAttribute,
+	}
+
+	if cp.ownerAttribute == "" {
+		cp.ownerAttribute = "x-Owner"
+	}
+
+	for principal, roles := range p.Principals {
+		for _, role := range roles {
+			if _, ok := p.Roles[role]; !ok {
+				return nil, merry.Errorf("principal %s: undefined role %s", principal, role)
+			}
+		}
+	}
+
+	for role, rules := range p.Roles {
+		for i, rule := range rules {
+			cr := compiledRule{
+				operations:   map[kmip14.Operation]bool{},
+				objectGroups: set(rule.ObjectGroups),
+				owners:       set(rule.Owners),
+			}
+
+			if len(rule.Operations) == 0 {
+				return nil, merry.Errorf("role %s: rule %d: no operations", role, i)
+			}
+
+			for _, op := range rule.Operations {
+				if op == "*" {
+					cr.allOperations = true
+					continue
+				}
+
+				v, err := ttlv.DefaultRegistry.ParseEnum(kmip14.TagOperation, op)
+				if err != nil {
+					return nil, merry.Prependf(err, "role %s: rule %d: invalid operation %s", role, i, op)
+				}
+
+				cr.operations[kmip14.Operation(v)] = true
+			}
+
+			cp.roles[role] = append(cp.roles[role], cr)
+		}
+	}
+
+	return cp, nil
+}
+
+func set(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+
+	m := make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+
+	return m
+}
+
+func (p *compiledPolicy) allows(access *Access) bool {
+	if access.Principal == nil {
+		return false
+	}
+
+	principal, ok := access.Principal.(string)
+	if !ok {
+		principal = fmt.Sprint(access.Principal)
+	}
+
+	// the rules which allow the operation
+	var rules []compiledRule
+
+	for _, roles := range [][]string{p.principals[principal], p.principals["*"]} {
+		for _, role := range roles {
+			for _, rule := range p.roles[role] {
+				if rule.allOperations || rule.operations[access.Operation] {
+					rules = append(rules, rule)
+				}
+			}
+		}
+	}
+
+	if access.UniqueIdentifier == "" {
+		return p.requestAllowed(rules, principal, access.Attributes)
+	}
+
+	for _, rule := range rules {
+		if p.objectAllowed(rule, principal, access.Attributes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// objectAllowed returns true if the rule allows operations on an object with the attributes.
+func (p *compiledPolicy) objectAllowed(rule compiledRule, principal string, attrs []Attribute) bool {
+	if rule.objectGroups == nil && rule.owners == nil {
+		return true
+	}
+
+	for _, attr := range attrs {
+		v, ok := attributeText(attr.AttributeValue)
+		if ok && p.valueAllowed(rule, principal, attr.AttributeName, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requestAllowed returns true if the rules allow an operation without a target, whose
+// attributes are from the request.  The client controls them, so rather than one, every
+// Object Group and owner in the attributes must be allowed by one of the rules.
+func (p *compiledPolicy) requestAllowed(rules []compiledRule, principal string, attrs []Attribute) bool {
+	for _, rule := range rules {
+		if rule.objectGroups == nil && rule.owners == nil {
+			return true
+		}
+	}
+
+	matched := false
+
+	for _, attr := range attrs {
+		if attr.AttributeName != kmip14.TagObjectGroup.CanonicalName() && attr.AttributeName != p.ownerAttribute {
+			continue
+		}
+
+		v, ok := attributeText(attr.AttributeValue)
+		if !ok {
+			return false
+		}
+
+		allowed := false
+
+		for _, rule := range rules {
+			if p.valueAllowed(rule, principal, attr.AttributeName, v) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return false
+		}
+
+		matched = true
+	}
+
+	return matched
+}
+
+// valueAllowed returns true if the value of the Object Group or owner attribute is one
+// of the rule's.
+func (p *compiledPolicy) valueAllowed(rule compiledRule, principal, name, v string) bool {
+	switch name {
+	case kmip14.TagObjectGroup.CanonicalName():
+		return rule.objectGroups[v]
+	case p.ownerAttribute:
+		return rule.owners[v] || (v == principal && rule.owners["$principal"])
+	default:
+		return false
+	}
+}
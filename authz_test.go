@@ -0,0 +1,297 @@
+package kmip
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/kmip20/names"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `{
+  "principals": {
+    "alice": ["tenant-a"],
+    "bob": ["tenant-b"],
+    "*": ["discovery"]
+  },
+  "roles": {
+    "tenant-a": [
+      {"operations": ["Create", "Locate", "Get"], "objectGroups": ["tenant-a"]},
+      {"operations": ["*"], "owners": ["$principal"]}
+    ],
+    "tenant-b": [
+      {"operations": ["Get"], "objectGroups": ["tenant-b"]}
+    ],
+    "discovery": [
+      {"operations": ["Query", "DiscoverVersions"]}
+    ]
+  }
+}`
+
+// testAuthzMux returns a mux which authorizes operations with the policy.  Its objects
+// are: key1, in group tenant-a, key2, in group tenant-b, and key3 and key5, owned by alice.
+// Create sets the ID placeholder to key1.
+func testAuthzMux(t *testing.T, a Authorizer) *OperationMux {
+	t.Helper()
+
+	objects := map[string][]Attribute{
+		"key1": {{AttributeName: "Object Group", AttributeValue: "tenant-a"}},
+		"key2": {{AttributeName: "Object Group", AttributeValue: "tenant-b"}},
+		"key3": {{AttributeName: "x-Owner", AttributeValue: "alice"}},
+		"key5": {{AttributeName: "x-Owner", AttributeValue: "alice"}},
+	}
+
+	mux := &OperationMux{
+		Authorizer: a,
+		ObjectAttributes: func(ctx context.Context, uniqueIdentifier string) ([]Attribute, error) {
+			attrs, ok := objects[uniqueIdentifier]
+			if !ok {
+				return nil, WithResultReason(ErrPermissionDenied, kmip14.ResultReasonItemNotFound)
+			}
+
+			return attrs, nil
+		},
+	}
+
+	ok := ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+		if req.CurrentItem.Operation == kmip14.OperationCreate {
+			req.IDPlaceholder = "key1"
+		}
+
+		return &ResponseBatchItem{ResultStatus: kmip14.ResultStatusSuccess}, nil
+	})
+
+	for _, op := range []kmip14.Operation{
+		kmip14.OperationCreate, kmip14.OperationLocate, kmip14.OperationGet, kmip14.OperationDestroy,
+		kmip14.OperationQuery, kmip14.OperationDeriveKey, kmip14.OperationReKeyKeyPair,
+	} {
+		mux.Handle(op, ok)
+	}
+
+	return mux
+}
+
+// handleItems handles the items with the mux, and returns the result reasons of the responses.
+func handleItems(mux *OperationMux, principal interface{}, items ...RequestBatchItem) []kmip14.ResultReason {
+	req := &Request{Message: &RequestMessage{BatchItem: items}, Principal: principal}
+	resp := &Response{}
+
+	mux.HandleMessage(context.Background(), req, resp)
+
+	reasons := make([]kmip14.ResultReason, len(resp.BatchItem))
+	for i, bi := range resp.BatchItem {
+		reasons[i] = bi.ResultReason
+	}
+
+	return reasons
+}
+
+func getItem(id string) RequestBatchItem {
+	return RequestBatchItem{Operation: kmip14.OperationGet, RequestPayload: GetRequestPayload{UniqueIdentifier: id}}
+}
+
+// createItem returns a Create item for an object in the groups.
+func createItem(groups ...string) RequestBatchItem {
+	attrs := make([]Attribute, len(groups))
+	for i, g := range groups {
+		attrs[i] = NewAttributeFromTag(kmip14.TagObjectGroup, i, g)
+	}
+
+	return createItemWithAttributes(attrs...)
+}
+
+func createItemWithAttributes(attrs ...Attribute) RequestBatchItem {
+	return RequestBatchItem{
+		Operation: kmip14.OperationCreate,
+		RequestPayload: CreateRequestPayload{
+			ObjectType:        kmip14.ObjectTypeSymmetricKey,
+			TemplateAttribute: TemplateAttribute{Attribute: attrs},
+		},
+	}
+}
+
+// createItem20 returns a KMIP 2.0 Create item for an object in the groups, whose
+// Attributes structure holds the attributes by their tags.
+func createItem20(groups ...string) RequestBatchItem {
+	attrs := ttlv.Values{{Tag: kmip14.TagCryptographicAlgorithm, Value: kmip14.CryptographicAlgorithmAES}}
+	for _, g := range groups {
+		attrs = append(attrs, ttlv.Value{Tag: kmip14.TagObjectGroup, Value: g})
+	}
+
+	return RequestBatchItem{
+		Operation: kmip14.OperationCreate,
+		RequestPayload: ttlv.Value{Tag: kmip14.TagRequestPayload, Value: ttlv.Values{
+			{Tag: kmip14.TagObjectType, Value: kmip14.ObjectTypeSymmetricKey},
+			{Tag: names.TagAttributes, Value: attrs},
+		}},
+	}
+}
+
+// deriveKeyItem returns a Derive Key item with the unique identifiers.
+func deriveKeyItem(ids ...string) RequestBatchItem {
+	return RequestBatchItem{
+		Operation: kmip14.OperationDeriveKey,
+		RequestPayload: struct {
+			ObjectType       kmip14.ObjectType
+			UniqueIdentifier []string
+		}{kmip14.ObjectTypeSymmetricKey, ids},
+	}
+}
+
+// reKeyKeyPairItem returns a Re-key Key Pair item for the private key, whose template
+// claims the new keys are owned by owner.
+func reKeyKeyPairItem(privateKeyID, owner string) RequestBatchItem {
+	return RequestBatchItem{
+		Operation: kmip14.OperationReKeyKeyPair,
+		RequestPayload: struct {
+			PrivateKeyUniqueIdentifier string
+			CommonTemplateAttribute    TemplateAttribute
+		}{privateKeyID, TemplateAttribute{Attribute: []Attribute{{AttributeName: "x-Owner", AttributeValue: owner}}}},
+	}
+}
+
+func TestPolicyAuthorizer(t *testing.T) {
+	p, err := ParsePolicy(strings.NewReader(testPolicy))
+	require.NoError(t, err)
+
+	a, err := NewPolicyAuthorizer(p)
+	require.NoError(t, err)
+
+	mux := testAuthzMux(t, a)
+
+	const (
+		allowed = kmip14.ResultReason(0)
+		denied  = kmip14.ResultReasonPermissionDenied
+	)
+
+	tests := []struct {
+		name      string
+		principal interface{}
+		items     []RequestBatchItem
+		expected  []kmip14.ResultReason
+	}{
+		{name: "object group", principal: "alice", items: []RequestBatchItem{getItem("key1")}, expected: []kmip14.ResultReason{allowed}},
+		{name: "other group", principal: "alice", items: []RequestBatchItem{getItem("key2")}, expected: []kmip14.ResultReason{denied}},
+		{name: "other tenant", principal: "bob", items: []RequestBatchItem{getItem("key2"), getItem("key1")}, expected: []kmip14.ResultReason{allowed, denied}},
+		{name: "owner", principal: "alice", items: []RequestBatchItem{{Operation: kmip14.OperationDestroy, RequestPayload: DestroyRequestPayload{UniqueIdentifier: "key3"}}}, expected: []kmip14.ResultReason{allowed}},
+		{name: "not owner", principal: "bob", items: []RequestBatchItem{getItem("key3")}, expected: []kmip14.ResultReason{denied}},
+		{name: "operation not allowed", principal: "alice", items: []RequestBatchItem{{Operation: kmip14.OperationDestroy, RequestPayload: DestroyRequestPayload{UniqueIdentifier: "key1"}}}, expected: []kmip14.ResultReason{denied}},
+		{name: "create in group", principal: "alice", items: []RequestBatchItem{createItem("tenant-a")}, expected: []kmip14.ResultReason{allowed}},
+		{name: "create in other group", principal: "alice", items: []RequestBatchItem{createItem("tenant-b")}, expected: []kmip14.ResultReason{denied}},
+		{name: "create in several groups", principal: "alice", items: []RequestBatchItem{createItem("tenant-a", "tenant-b")}, expected: []kmip14.ResultReason{denied}},
+		{name: "create owned in group", principal: "alice", items: []RequestBatchItem{createItemWithAttributes(
+			NewAttributeFromTag(kmip14.TagObjectGroup, 0, "tenant-a"),
+			Attribute{AttributeName: "x-Owner", AttributeValue: "alice"},
+		)}, expected: []kmip14.ResultReason{allowed}},
+		{name: "create owned in other group", principal: "alice", items: []RequestBatchItem{createItemWithAttributes(
+			NewAttributeFromTag(kmip14.TagObjectGroup, 0, "tenant-b"),
+			Attribute{AttributeName: "x-Owner", AttributeValue: "alice"},
+		)}, expected: []kmip14.ResultReason{denied}},
+		{name: "create owned by other", principal: "alice", items: []RequestBatchItem{createItemWithAttributes(
+			NewAttributeFromTag(kmip14.TagObjectGroup, 0, "tenant-a"),
+			Attribute{AttributeName: "x-Owner", AttributeValue: "bob"},
+		)}, expected: []kmip14.ResultReason{denied}},
+		{name: "create 2.0 in group", principal: "alice", items: []RequestBatchItem{createItem20("tenant-a")}, expected: []kmip14.ResultReason{allowed}},
+		{name: "create 2.0 in other group", principal: "alice", items: []RequestBatchItem{createItem20("tenant-b")}, expected: []kmip14.ResultReason{denied}},
+		{name: "create 2.0 in several groups", principal: "alice", items: []RequestBatchItem{createItem20("tenant-a", "tenant-b")}, expected: []kmip14.ResultReason{denied}},
+		{name: "create 2.0 without group", principal: "alice", items: []RequestBatchItem{createItem20()}, expected: []kmip14.ResultReason{denied}},
+		{name: "locate in several groups", principal: "alice", items: []RequestBatchItem{{Operation: kmip14.OperationLocate, RequestPayload: LocateRequestPayload{Attribute: []Attribute{
+			NewAttributeFromTag(kmip14.TagObjectGroup, 0, "tenant-a"),
+			NewAttributeFromTag(kmip14.TagObjectGroup, 1, "tenant-b"),
+		}}}}, expected: []kmip14.ResultReason{denied}},
+		{name: "id placeholder", principal: "alice", items: []RequestBatchItem{createItem("tenant-a"), {Operation: kmip14.OperationGet}}, expected: []kmip14.ResultReason{allowed, allowed}},
+		{name: "id placeholder other tenant", principal: "bob", items: []RequestBatchItem{{Operation: kmip14.OperationGet}}, expected: []kmip14.ResultReason{denied}},
+		{name: "locate", principal: "alice", items: []RequestBatchItem{{Operation: kmip14.OperationLocate, RequestPayload: LocateRequestPayload{Attribute: []Attribute{NewAttributeFromTag(kmip14.TagObjectGroup, 0, "tenant-a")}}}}, expected: []kmip14.ResultReason{allowed}},
+		{name: "locate everything", principal: "alice", items: []RequestBatchItem{{Operation: kmip14.OperationLocate, RequestPayload: LocateRequestPayload{}}}, expected: []kmip14.ResultReason{denied}},
+		{name: "all principals", principal: "carol", items: []RequestBatchItem{{Operation: kmip14.OperationQuery}}, expected: []kmip14.ResultReason{allowed}},
+		{name: "unauthenticated", items: []RequestBatchItem{{Operation: kmip14.OperationQuery}}, expected: []kmip14.ResultReason{denied}},
+		{name: "all targets", principal: "alice", items: []RequestBatchItem{deriveKeyItem("key3", "key5")}, expected: []kmip14.ResultReason{allowed}},
+		{name: "one target of several denied", principal: "alice", items: []RequestBatchItem{deriveKeyItem("key3", "key2")}, expected: []kmip14.ResultReason{denied}},
+		{name: "other target tags", principal: "alice", items: []RequestBatchItem{reKeyKeyPairItem("key3", "alice")}, expected: []kmip14.ResultReason{allowed}},
+		{name: "payload attributes of targeted operation ignored", principal: "alice", items: []RequestBatchItem{reKeyKeyPairItem("key2", "alice")}, expected: []kmip14.ResultReason{denied}},
+		{name: "no target", principal: "alice", items: []RequestBatchItem{{Operation: kmip14.OperationDestroy, RequestPayload: struct {
+			CommonTemplateAttribute TemplateAttribute
+		}{TemplateAttribute{Attribute: []Attribute{{AttributeName: "x-Owner", AttributeValue: "alice"}}}}}}, expected: []kmip14.ResultReason{denied}},
+		{name: "unknown object", principal: "alice", items: []RequestBatchItem{getItem("key4")}, expected: []kmip14.ResultReason{kmip14.ResultReasonItemNotFound}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, handleItems(mux, tc.principal, tc.items...))
+		})
+	}
+}
+
+func TestPolicyAuthorizer_access(t *testing.T) {
+	var accesses []*Access
+
+	mux := testAuthzMux(t, AuthorizerFunc(func(ctx context.Context, req *Request, access *Access) error {
+		accesses = append(accesses, access)
+		return nil
+	}))
+
+	handleItems(mux, "alice", createItem("tenant-a"), RequestBatchItem{Operation: kmip14.OperationGet})
+
+	require.Len(t, accesses, 2)
+	assert.Equal(t, &Access{
+		Principal:  "alice",
+		Operation:  kmip14.OperationCreate,
+		Attributes: []Attribute{{AttributeName: "Object Group", AttributeValue: accesses[0].Attributes[0].AttributeValue}},
+	}, accesses[0])
+	v, _ := attributeText(accesses[0].Attributes[0].AttributeValue)
+	assert.Equal(t, "tenant-a", v)
+
+	assert.Equal(t, &Access{
+		Principal:        "alice",
+		Operation:        kmip14.OperationGet,
+		UniqueIdentifier: "key1",
+		Attributes:       []Attribute{{AttributeName: "Object Group", AttributeValue: "tenant-a"}},
+	}, accesses[1])
+}
+
+func TestParsePolicy(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":     `{"principals": {}, "groups": {}}`,
+		"undefined role":    `{"principals": {"alice": ["admin"]}}`,
+		"invalid operation": `{"roles": {"admin": [{"operations": ["Frobnicate"]}]}}`,
+		"no operations":     `{"roles": {"admin": [{"objectGroups": ["a"]}]}}`,
+	}
+
+	for name, policy := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParsePolicy(strings.NewReader(policy))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPolicyAuthorizer_WatchPolicyFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(name, []byte(testPolicy), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := &PolicyAuthorizer{}
+	require.NoError(t, a.WatchPolicyFile(ctx, name, 10*time.Millisecond))
+
+	mux := testAuthzMux(t, a)
+	assert.Equal(t, []kmip14.ResultReason{kmip14.ResultReasonPermissionDenied}, handleItems(mux, "bob", getItem("key1")))
+
+	// an invalid policy is ignored
+	require.NoError(t, os.WriteFile(name, []byte(`{"principals": {"bob": ["admin"]}}`), 0o600))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, []kmip14.ResultReason{kmip14.ResultReasonPermissionDenied}, handleItems(mux, "bob", getItem("key1")))
+
+	require.NoError(t, os.WriteFile(name, []byte(strings.Replace(testPolicy, `"bob": ["tenant-b"]`, `"bob": ["tenant-a", "tenant-b"]`, 1)), 0o600))
+	assert.Eventually(t, func() bool {
+		return handleItems(mux, "bob", getItem("key1"))[0] == kmip14.ResultReason(0)
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -125,6 +125,8 @@ type AttestationCredentialValue struct {
 //
 // The Key Block SHALL contain a Key Wrapping Data structure if the key in the Key Value field is
 // wrapped (i.e., encrypted, or MACed/signed, or both).
+//
+//ttlvgen:generate
 type KeyBlock struct {
 	KeyFormatType          kmip14.KeyFormatType
 	KeyCompressionType     kmip14.KeyCompressionType     `ttlv:",omitempty"`
@@ -147,6 +149,8 @@ type KeyBlock struct {
 //     the wrapped un-encoded value of the Byte String Key Material field.
 //
 // TODO: Unmarshaler impl which unmarshals correct KeyMaterial type.
+//
+//ttlvgen:generate
 type KeyValue struct {
 	// KeyMaterial should be []byte, one of the Transparent*Key structs, or a custom struct if KeyFormatType is
 	// an extension.
@@ -415,6 +415,23 @@ func TestGetResponsePayload_unmarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			// object types without a field are valid, and skipped
+			name: "PGPKey",
+
+			input: s(kmip14.TagResponsePayload,
+				v(kmip14.TagObjectType, kmip14.ObjectTypePGPKey),
+				v(kmip14.TagUniqueIdentifier, uniqueIdentifier),
+				s(kmip14.TagPGPKey,
+					v(kmip14.TagPGPKeyVersion, 4),
+				),
+			),
+
+			expect: GetResponsePayload{
+				ObjectType:       kmip14.ObjectTypePGPKey,
+				UniqueIdentifier: uniqueIdentifier,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -9,6 +9,7 @@ import (
 	"go/format"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -34,6 +35,10 @@ type Specifications struct {
 	// containing a hex encoded number, e.g. "0x42015E"
 	Tags    map[string]interface{} `json:"tags"`
 	Package string                 `json:"-"`
+	// Aliases is the import path of a package which was generated from the same
+	// specifications.  If set, the generated code just aliases that package's
+	// constants and types.
+	Aliases string `json:"-"`
 }
 
 // EnumDef describes a single enum or mask value.
@@ -67,11 +72,13 @@ func main() {
 	var inputFilename string
 	var outputFilename string
 	var packageName string
+	var aliases string
 	var usage bool
 
 	flag.StringVar(&inputFilename, "i", "", "Input `filename` of specifications.  Required.")
 	flag.StringVar(&outputFilename, "o", "", "Output `filename`.  Defaults to standard out.")
 	flag.StringVar(&packageName, "p", "ttlv", "Go `package` name in generated code.")
+	flag.StringVar(&aliases, "a", "", "Import `path` of a package generated from the same specifications.  If set, generates aliases of its constants and types.")
 	flag.BoolVar(&usage, "h", false, "Show this usage message.")
 	flag.Parse()
 
@@ -86,14 +93,14 @@ func main() {
 		os.Exit(1)
 	}
 
-	err := run(inputFilename, outputFilename, packageName)
+	err := run(inputFilename, outputFilename, packageName, aliases)
 	if err != nil {
 		fmt.Println(merry.Details(err))
 		os.Exit(1)
 	}
 }
 
-func run(inFilename, outFilename, packageName string) error {
+func run(inFilename, outFilename, packageName, aliases string) error {
 	inputFile, err := os.Open(inFilename)
 	if err != nil {
 		fmt.Println("error opening input file: ", err.Error())
@@ -103,6 +110,7 @@ func run(inFilename, outFilename, packageName string) error {
 
 	specs := Specifications{
 		Package: packageName,
+		Aliases: aliases,
 	}
 
 	err = json.NewDecoder(bufio.NewReader(inputFile)).Decode(&specs)
@@ -175,8 +183,10 @@ type inputs struct {
 	Package     string
 	Imports     []string
 	TTLVPackage string
-	Enums       []enumVal
-	Masks       []enumVal
+	// AliasPackage is the qualifier of the package whose constants and types are aliased
+	AliasPackage string
+	Enums        []enumVal
+	Masks        []enumVal
 }
 
 func parseUint32(v interface{}) (uint32, error) {
@@ -215,6 +225,11 @@ func prepareInput(s *Specifications) (*inputs, error) {
 		in.TTLVPackage = "ttlv."
 	}
 
+	if s.Aliases != "" {
+		in.Imports = append([]string{s.Aliases}, in.Imports...)
+		in.AliasPackage = path.Base(s.Aliases) + "."
+	}
+
 	// prepare tag inputs
 	// normalize all the value names
 	for key, value := range s.Tags {
@@ -298,15 +313,22 @@ func genCode(s *Specifications) (string, error) {
 
 	tmpl := template.New("root")
 	tmpl.Funcs(template.FuncMap{
-		"ttlvPackage": func() string { return in.TTLVPackage },
+		"ttlvPackage":  func() string { return in.TTLVPackage },
+		"aliasPackage": func() string { return in.AliasPackage },
 	})
 	template.Must(tmpl.Parse(global))
 	template.Must(tmpl.New("tags").Parse(tags))
 	template.Must(tmpl.New("base").Parse(baseTmpl))
 	template.Must(tmpl.New("enumeration").Parse(enumerationTmpl))
 	template.Must(tmpl.New("mask").Parse(maskTmpl))
+	template.Must(tmpl.New("aliases").Parse(aliasesTmpl))
+
+	if s.Aliases != "" {
+		err = tmpl.ExecuteTemplate(buf, "aliases", in)
+	} else {
+		err = tmpl.Execute(buf, in)
+	}
 
-	err = tmpl.Execute(buf, in)
 	if err != nil {
 		return "", merry.Prepend(err, "executing template")
 	}
@@ -369,6 +391,46 @@ func RegisterGeneratedDefinitions(r *{{ttlvPackage}}Registry) {
 }
 `
 
+// aliasesTmpl aliases the constants and types generated by global in another package, so
+// the definitions are only generated, and registered, once.
+const aliasesTmpl = `// Code generated by kmipgen; DO NOT EDIT.
+package {{.Package}}
+
+{{with .Imports}}
+import (
+{{range .}} "{{.}}"
+{{end}})
+{{end}}
+
+{{with .Tags}}
+const (
+{{range .}}	Tag{{.Name}} = {{aliasPackage}}Tag{{.Name}}
+{{end}})
+{{end}}
+
+{{range .Enums}}{{template "alias" .}}{{end}}
+
+{{range .Masks}}{{template "alias" .}}{{end}}
+
+func RegisterGeneratedDefinitions(r *{{ttlvPackage}}Registry) {
+	{{aliasPackage}}RegisterGeneratedDefinitions(r)
+}
+
+{{define "alias"}}{{ $typeName := .TypeName }}// {{.Comment}}
+type {{.TypeName}} = {{aliasPackage}}{{.TypeName}}
+
+const ({{range .Vals}}
+	{{$typeName}}{{.Name}} = {{aliasPackage}}{{$typeName}}{{.Name}}{{end}}
+)
+
+var {{.TypeName}}Enum = {{aliasPackage}}{{.TypeName}}Enum
+
+func New{{.TypeName}}Enum() {{ttlvPackage}}Enum {
+	return {{aliasPackage}}New{{.TypeName}}Enum()
+}
+
+{{end}}`
+
 const tags = `
 const (
 {{range .}}	Tag{{.Name}} {{ttlvPackage}}Tag = {{.Value | printf "%#06x"}}
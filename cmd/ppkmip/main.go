@@ -13,6 +13,11 @@ import (
 	"os"
 	"strings"
 
+	// the kmip14 and names packages register the KMIP 1.4 and 2.0 tags and enums, so
+	// values are printed, and queried, by name.  The kmip20 package would register them
+	// too, but links the kmip package's client and server.
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/kmip20/names"
 	"github.com/Seagate/kmip-go/ttlv"
 )
 
@@ -20,6 +25,7 @@ const (
 	FormatJSON = "json"
 	FormatXML  = "xml"
 	FormatHex  = "hex"
+	FormatText = "text"
 )
 
 func main() {
@@ -28,7 +34,7 @@ func main() {
 
 Usage:  ppkmip [options] [input] 
 
-Pretty prints KMIP.  Can read KMIP in hex, json, or xml formats, 
+Pretty prints KMIP.  Can read KMIP in hex, json, xml, or text formats, 
 and print it out in pretty-printed json, xml, text, raw hex, or
 pretty printed hex.
 		
@@ -40,11 +46,24 @@ embedded formatting characters, will be ignored.  The 'hexpretty'
 output format embeds such characters, but because they are ignored,
 'hexpretty' output is still valid 'hex' input.
 		
-The default output format is "text", which is optimized for human readability,
-but not for machine parsing.  It can't be used as input.
+The default output format is "text", which is optimized for human readability.
+It can also be used as input, so messages can be edited by hand.  When reading
+text, the lengths are recalculated, so they can be left stale, or omitted, e.g.
+"ProtocolVersionMajor (Integer): 1".  Enumerations and integers can be names or
+hex.  Redacted values can't be read, so use -reveal when printing text which
+will be used as input.
 		
 The json and xml input/output formats are compliant with the KMIP spec, and
 should be compatible with other KMIP tooling.
+
+Vendor extensions described by the Extension Information in a Query response
+are registered as they are read, so they are printed by name in the values
+which follow.
+
+The -q option prints only the values selected by a path expression, like
+"BatchItem/ResponsePayload/UniqueIdentifier", or 
+'Attribute[AttributeName="Name"]/AttributeValue'.  See ttlv.Path for the 
+path syntax.
 		
 Examples:
 		
@@ -99,13 +118,30 @@ xml format:
 	var inFormat string
 	var outFormat string
 	var inFile string
+	var query string
+	var reveal bool
 
-	flag.StringVar(&inFormat, "i", "", "input format: hex|json|xml, defaults to auto detect")
+	flag.StringVar(&inFormat, "i", "", "input format: hex|json|xml|text, defaults to auto detect")
 	flag.StringVar(&outFormat, "o", "", "output format: text|hex|prettyhex|json|xml, defaults to text")
 	flag.StringVar(&inFile, "f", "", "input file name, defaults to stdin")
+	flag.StringVar(&query, "q", "", "path expression selecting the values to print, defaults to printing whole values")
+	flag.BoolVar(&reveal, "reveal", false, "print the values of sensitive tags, like key material, instead of redacting them in text and prettyhex output")
 
 	flag.Parse()
 
+	opts := ttlv.PrintOptions{Reveal: reveal}
+
+	var path *ttlv.Path
+
+	if query != "" {
+		var err error
+
+		path, err = ttlv.ParsePath(query)
+		if err != nil {
+			fail("invalid query", err)
+		}
+	}
+
 	buf := bytes.NewBuffer(nil)
 
 	if inFile != "" {
@@ -122,6 +158,7 @@ xml format:
 
 		for scanner.Scan() {
 			buf.Write(scanner.Bytes())
+			buf.WriteByte('\n')
 		}
 
 		if err := scanner.Err(); err != nil {
@@ -138,6 +175,11 @@ xml format:
 			inFormat = FormatXML
 		default:
 			inFormat = FormatHex
+
+			firstLine, _, _ := strings.Cut(buf.String(), "\n")
+			if strings.Contains(firstLine, "):") {
+				inFormat = FormatText
+			}
 		}
 	}
 
@@ -165,8 +207,7 @@ xml format:
 				fail("error parsing JSON", err)
 			}
 
-			printTTLV(outFormat, raw, count)
-			count++
+			count = printValues(outFormat, opts, path, raw, count)
 		}
 
 	case FormatXML:
@@ -184,16 +225,23 @@ xml format:
 				fail("error parsing XML", err)
 			}
 
-			printTTLV(outFormat, raw, count)
-
-			count++
+			count = printValues(outFormat, opts, path, raw, count)
 		}
 	case FormatHex:
 		raw := ttlv.TTLV(ttlv.Hex2bytes(buf.String()))
 
 		for len(raw) > 0 {
-			printTTLV(outFormat, raw, count)
-			count++
+			count = printValues(outFormat, opts, path, raw, count)
+			raw = raw.Next()
+		}
+	case FormatText:
+		raw, err := ttlv.ParseText(buf)
+		if err != nil {
+			fail("error parsing text", err)
+		}
+
+		for len(raw) > 0 {
+			count = printValues(outFormat, opts, path, raw, count)
 			raw = raw.Next()
 		}
 	default:
@@ -201,14 +249,66 @@ xml format:
 	}
 }
 
-func printTTLV(outFormat string, raw ttlv.TTLV, count int) {
+// printValues prints raw, or the values in raw selected by path, if not nil.  Returns
+// the count of values printed so far.
+func printValues(outFormat string, opts ttlv.PrintOptions, path *ttlv.Path, raw ttlv.TTLV, count int) int {
+	registerExtensions(raw)
+
+	if path == nil {
+		printTTLV(outFormat, opts, raw, count)
+		return count + 1
+	}
+
+	for _, v := range path.FindAll(raw) {
+		printTTLV(outFormat, opts, v, count)
+		count++
+	}
+
+	return count
+}
+
+// registerExtensions registers the names of the vendor extensions described by
+// the Extension Information found in raw.
+func registerExtensions(raw ttlv.TTLV) {
+	exts := collectExtensions(raw, nil)
+	if len(exts) == 0 {
+		return
+	}
+
+	if err := names.RegisterExtensions(&ttlv.DefaultRegistry, exts); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "ignoring extension information:", err)
+	}
+}
+
+func collectExtensions(t ttlv.TTLV, exts []names.ExtensionInformation) []names.ExtensionInformation {
+	if t.Valid() != nil || t.Type() != ttlv.TypeStructure {
+		return exts
+	}
+
+	if t.Tag() == kmip14.TagExtensionInformation {
+		var ext names.ExtensionInformation
+		if err := ttlv.Unmarshal(t, &ext); err == nil {
+			exts = append(exts, ext)
+		}
+
+		return exts
+	}
+
+	for c := t.ValueStructure(); len(c) > 0; c = c.Next() {
+		exts = collectExtensions(c, exts)
+	}
+
+	return exts
+}
+
+func printTTLV(outFormat string, opts ttlv.PrintOptions, raw ttlv.TTLV, count int) {
 	if count > 0 {
 		fmt.Println("")
 	}
 
 	switch outFormat {
 	case "text":
-		if err := ttlv.Print(os.Stdout, "", "  ", raw); err != nil {
+		if err := opts.Print(os.Stdout, "", "  ", raw); err != nil {
 			fail("error printing", err)
 		}
 	case "json":
@@ -228,7 +328,7 @@ func printTTLV(outFormat string, raw ttlv.TTLV, count int) {
 	case "hex":
 		fmt.Print(hex.EncodeToString(raw))
 	case "prettyhex":
-		if err := ttlv.PrintPrettyHex(os.Stdout, "", "  ", raw); err != nil {
+		if err := opts.PrintPrettyHex(os.Stdout, "", "  ", raw); err != nil {
 			fail("error printing", err)
 		}
 	}
@@ -0,0 +1,1340 @@
+// Command ttlvgen generates MarshalTTLV and UnmarshalTTLV methods for structs, so they can
+// be encoded and decoded without the reflection used by ttlv.Marshal and ttlv.Unmarshal.
+//
+// Structs are selected by annotating them with a ttlvgen:generate comment directive:
+//
+//	//ttlvgen:generate
+//	type GetResponsePayload struct {
+//	    ...
+//	}
+//
+// The generated methods produce exactly the same output as the reflection based encoder
+// and decoder, and follow the same struct tag rules.  Values which have no fast path, like
+// interface{} fields or structs which aren't annotated, are passed to the reflection based
+// encoder and decoder.  Tags, and whether tags are enums or bitmasks, are resolved when the
+// code is generated, using ttlv.DefaultRegistry with the KMIP 1.4 and 2.0 definitions
+// registered.
+//
+// Each type also gets a TTLVGenerated method, which returns the type.  The methods are
+// promoted to structs embedding one of the types, so the encoder and decoder use it to
+// ignore them there.  The embedded fields are flattened into the struct instead, as they
+// are by the reflection based encoder.  The methods encode and decode with the reflection
+// based encoder and decoder when they have a Registry other than ttlv.DefaultRegistry.
+//
+// Generated files have the build constraint "!ttlvreflect", so building with
+// "-tags ttlvreflect" disables the generated methods.  Run ttlvgen with that tag too, so
+// it still builds if previously generated code is stale:
+//
+//	//go:generate go run -tags ttlvreflect ../cmd/ttlvgen -o marshal_generated.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/ansel1/merry"
+
+	// register the KMIP 1.4 and 2.0 tags and enums with ttlv.DefaultRegistry
+	_ "github.com/Seagate/kmip-go/kmip20"
+)
+
+const (
+	directive       = "//ttlvgen:generate"
+	ttlvPkgPath     = "github.com/Seagate/kmip-go/ttlv"
+	buildTag        = "ttlvreflect"
+	defaultOut      = "marshal_generated.go"
+	marshalMethod   = "MarshalTTLV"
+	unmarshalMethod = "UnmarshalTTLV"
+)
+
+func main() {
+	flag.Usage = func() {
+		_, _ = fmt.Fprintln(flag.CommandLine.Output(), "Usage of ttlvgen:")
+		_, _ = fmt.Fprintln(flag.CommandLine.Output(), "")
+		_, _ = fmt.Fprintln(flag.CommandLine.Output(), "  ttlvgen [options] [directory]")
+		_, _ = fmt.Fprintln(flag.CommandLine.Output(), "")
+		_, _ = fmt.Fprintln(flag.CommandLine.Output(), "Generates MarshalTTLV and UnmarshalTTLV methods for the structs in a package")
+		_, _ = fmt.Fprintln(flag.CommandLine.Output(), "annotated with "+directive+".  The directory defaults to the current directory.")
+		_, _ = fmt.Fprintln(flag.CommandLine.Output(), "")
+		flag.PrintDefaults()
+	}
+
+	var outputFilename string
+	var usage bool
+
+	flag.StringVar(&outputFilename, "o", defaultOut, "Output `filename`, relative to the package directory.")
+	flag.BoolVar(&usage, "h", false, "Show this usage message.")
+	flag.Parse()
+
+	if usage {
+		flag.Usage()
+		os.Exit(0)
+	}
+
+	dir := flag.Arg(0)
+	if dir == "" {
+		dir = "."
+	}
+
+	err := run(dir, outputFilename)
+	if err != nil {
+		fmt.Println(merry.Details(err))
+		os.Exit(1)
+	}
+}
+
+func run(dir, outFilename string) error {
+	pkg, err := loadPackage(dir, outFilename)
+	if err != nil {
+		return err
+	}
+
+	names := annotatedTypes(pkg)
+	if len(names) == 0 {
+		return merry.Errorf("no types in %s are annotated with %s", dir, directive)
+	}
+
+	g := newGenerator(pkg)
+
+	for _, name := range names {
+		if err := g.addType(name); err != nil {
+			return err
+		}
+	}
+
+	src, err := g.source()
+	if err != nil {
+		return err
+	}
+
+	p := filepath.Join(dir, outFilename)
+	fmt.Println("writing to", p)
+
+	return merry.Prepend(os.WriteFile(p, src, 0o600), "error writing output file")
+}
+
+type loadedPackage struct {
+	fset  *token.FileSet
+	files []*ast.File
+	types *types.Package
+}
+
+// loadPackage parses and type checks the package in dir, skipping the output
+// file from a previous run.
+func loadPackage(dir, outFilename string) (*loadedPackage, error) {
+	// exclude generated code from the package and its imports, so the result
+	// doesn't depend on code generated by previous runs
+	build.Default.BuildTags = append(build.Default.BuildTags, buildTag)
+
+	bpkg, err := build.Import(".", dir, 0)
+	if err != nil {
+		return nil, merry.Prepend(err, "error loading package")
+	}
+
+	lp := loadedPackage{fset: token.NewFileSet()}
+
+	for _, name := range bpkg.GoFiles {
+		if name == filepath.Base(outFilename) {
+			continue
+		}
+
+		f, err := parser.ParseFile(lp.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, merry.Prepend(err, "error parsing package")
+		}
+
+		lp.files = append(lp.files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(lp.fset, "source", nil)}
+
+	lp.types, err = conf.Check(bpkg.ImportPath, lp.fset, lp.files, nil)
+	if err != nil {
+		return nil, merry.Prepend(err, "error type checking package")
+	}
+
+	return &lp, nil
+}
+
+// annotatedTypes returns the names of the types annotated with the ttlvgen:generate
+// directive, in source order.
+func annotatedTypes(pkg *loadedPackage) []string {
+	var names []string
+
+	for _, f := range pkg.files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec) //nolint:forcetypeassert
+
+				doc := ts.Doc
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+
+				if hasDirective(doc) {
+					names = append(names, ts.Name.Name)
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == directive {
+			return true
+		}
+	}
+
+	return false
+}
+
+// structInfo is the go/types equivalent of the ttlv package's typeInfo.
+type structInfo struct {
+	name  string
+	typ   *types.Named
+	st    *types.Struct
+	field string // name of the generated ttlv.Field array
+
+	// tag is the default tag of the struct, used when the encoder passes TagNone
+	tag ttlv.Tag
+	// tagField is the name of a TTLVTag field of type ttlv.Tag
+	tagField string
+	fields   []*fieldInfo
+	validate bool
+}
+
+type fieldInfo struct {
+	index int
+	name  string
+	typ   types.Type
+	flags string
+	// tag is the tag the decoder matches the field with, and the encoder
+	// uses for the field unless the value has an intrinsic tag
+	tag ttlv.Tag
+	// encodeTag is the tag the encoder uses for the field.  If dynamic is true, it
+	// can't be determined until runtime.
+	encodeTag ttlv.Tag
+	dynamic   bool
+
+	omitEmpty, enum, bitmask, dateTimeExt, any bool
+
+	required, hasMin, hasMax bool
+	min, max                 int64
+	oneOf                    string
+	isOneOf                  bool
+}
+
+type generator struct {
+	pkg     *loadedPackage
+	structs []*structInfo
+	// annotated is the set of types which will have generated methods
+	annotated map[*types.Named]bool
+	imports   map[string]string
+	buf       bytes.Buffer
+}
+
+func newGenerator(pkg *loadedPackage) *generator {
+	return &generator{
+		pkg:       pkg,
+		annotated: map[*types.Named]bool{},
+		imports:   map[string]string{},
+	}
+}
+
+func (g *generator) addType(name string) error {
+	obj := g.pkg.types.Scope().Lookup(name)
+
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return merry.Errorf("%s is not a named type", name)
+	}
+
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return merry.Errorf("%s is not a struct", name)
+	}
+
+	for _, m := range []string{marshalMethod, unmarshalMethod} {
+		if hasMethod(types.NewPointer(named), m) {
+			return merry.Errorf("%s already has a %s method", name, m)
+		}
+	}
+
+	g.annotated[named] = true
+	g.structs = append(g.structs, &structInfo{
+		name:  name,
+		typ:   named,
+		st:    st,
+		field: "ttlv" + name + "Fields",
+	})
+
+	return nil
+}
+
+// resolve computes the struct info of a struct, following the same rules as
+// the ttlv package's getTypeInfo().
+func (g *generator) resolve(si *structInfo) error {
+	si.tag, _ = ttlv.DefaultRegistry.ParseTag(si.name)
+
+	tags := map[ttlv.Tag]string{}
+
+	for i := 0; i < si.st.NumFields(); i++ {
+		f := si.st.Field(i)
+		if !f.Exported() {
+			continue
+		}
+
+		structTag := reflectTag(si.st.Tag(i))
+		if structTag == "-" {
+			continue
+		}
+
+		if f.Embedded() {
+			return merry.Errorf("%s.%s: embedded fields are not supported", si.name, f.Name())
+		}
+
+		parts := strings.Split(structTag, ",")
+
+		var explicitTag ttlv.Tag
+
+		if parts[0] != "" {
+			var err error
+
+			explicitTag, err = ttlv.DefaultRegistry.ParseTag(parts[0])
+			if err != nil {
+				return merry.Prependf(err, "%s.%s", si.name, f.Name())
+			}
+		}
+
+		if f.Name() == "TTLVTag" {
+			switch {
+			case explicitTag != ttlv.TagNone:
+				si.tag = explicitTag
+			case isTTLVType(f.Type(), "Tag"):
+				si.tagField = f.Name()
+			}
+
+			continue
+		}
+
+		fi := &fieldInfo{
+			index: len(si.fields),
+			name:  f.Name(),
+			typ:   f.Type(),
+			flags: strings.Join(parts[1:], ","),
+		}
+
+		if err := fi.parseFlags(parts[1:]); err != nil {
+			return merry.Prependf(err, "%s.%s", si.name, f.Name())
+		}
+
+		if fi.any && explicitTag != ttlv.TagNone {
+			return merry.Errorf(`%s.%s may not specify a TTLV tag and the "any" flag`, si.name, f.Name())
+		}
+
+		if (fi.required || fi.isOneOf) && g.zeroIsValid(fi) {
+			return merry.Errorf(`%s.%s may not use the "required" or "oneof" flags: its zero value is a valid value, so use a pointer instead`, si.name, f.Name())
+		}
+
+		// the tag of a struct's TTLVTag field overrides the field's tag
+		intrinsic, dynamic := intrinsicTag(f.Type())
+		fi.tag = intrinsic
+
+		if fi.tag != ttlv.TagNone && explicitTag != ttlv.TagNone && fi.tag != explicitTag {
+			return merry.Errorf("%s.%s: tag %s conflicts with the intrinsic tag %s of its type", si.name, f.Name(), explicitTag, fi.tag)
+		}
+
+		if fi.tag == ttlv.TagNone {
+			fi.tag = explicitTag
+		}
+
+		if fi.tag == ttlv.TagNone {
+			fi.tag, _ = ttlv.DefaultRegistry.ParseTag(fi.name)
+		}
+
+		// when encoding, the tag is determined by the value.  Pointers to
+		// structs with intrinsic tags, interfaces, and structs with TTLVTag fields
+		// of type ttlv.Tag can override the field's tag.
+		fi.encodeTag = fi.tag
+
+		elem := deref(f.Type())
+		if elemTag, elemDynamic := intrinsicTag(elem); elemTag != ttlv.TagNone {
+			fi.encodeTag = elemTag
+		} else if elemDynamic || dynamic {
+			fi.dynamic = true
+		}
+
+		if _, ok := elem.Underlying().(*types.Interface); ok {
+			fi.dynamic = true
+		}
+
+		if !fi.any && fi.tag != ttlv.TagNone {
+			if other, ok := tags[fi.tag]; ok {
+				return merry.Errorf("%s.%s resolves to the same tag (%s) as %s", si.name, f.Name(), fi.tag, other)
+			}
+
+			tags[fi.tag] = f.Name()
+		}
+
+		if fi.required || fi.isOneOf || fi.hasMin || fi.hasMax {
+			si.validate = true
+		}
+
+		// like the ttlv package, omit the fields of a oneof group which
+		// aren't set, unless they are pointers
+		if fi.isOneOf {
+			switch fi.typ.Underlying().(type) {
+			case *types.Pointer, *types.Interface:
+			default:
+				fi.omitEmpty = true
+			}
+		}
+
+		si.fields = append(si.fields, fi)
+	}
+
+	return nil
+}
+
+func (fi *fieldInfo) parseFlags(flags []string) error {
+	for _, flag := range flags {
+		key, arg, _ := strings.Cut(flag, "=")
+
+		switch strings.ToLower(key) {
+		case "enum":
+			fi.enum = true
+		case "omitempty":
+			fi.omitEmpty = true
+		case "datetimeextended":
+			fi.dateTimeExt = true
+		case "bitmask":
+			fi.bitmask = true
+		case "any":
+			fi.any = true
+		case "required":
+			fi.required = true
+		case "oneof":
+			fi.isOneOf = true
+			fi.oneOf = arg
+		case "min", "max":
+			n, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return merry.Prependf(err, "invalid %s value", key)
+			}
+
+			if strings.EqualFold(key, "min") {
+				fi.hasMin, fi.min = true, n
+			} else {
+				fi.hasMax, fi.max = true, n
+			}
+		}
+	}
+
+	return nil
+}
+
+func reflectTag(tag string) string {
+	return reflect.StructTag(tag).Get("ttlv")
+}
+
+// intrinsicTag returns the tag of the TTLVTag field of a struct type, or
+// true if the struct has a TTLVTag field of type ttlv.Tag.
+func intrinsicTag(t types.Type) (ttlv.Tag, bool) {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return ttlv.TagNone, false
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if f.Name() != "TTLVTag" || f.Embedded() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(reflectTag(st.Tag(i)), ",")
+
+		switch name {
+		case "-":
+			return ttlv.TagNone, false
+		case "":
+		default:
+			tag, _ := ttlv.DefaultRegistry.ParseTag(name)
+			return tag, false
+		}
+
+		return ttlv.TagNone, isTTLVType(f.Type(), "Tag")
+	}
+
+	return ttlv.TagNone, false
+}
+
+func deref(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+
+	return t
+}
+
+func isNamed(t types.Type, pkgPath, name string) bool {
+	n, ok := t.(*types.Named)
+
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == pkgPath && n.Obj().Name() == name
+}
+
+func isTTLVType(t types.Type, name string) bool {
+	return isNamed(t, ttlvPkgPath, name)
+}
+
+func hasMethod(t types.Type, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, name)
+	_, ok := obj.(*types.Func)
+
+	return ok
+}
+
+func isByteSlice(t types.Type) bool {
+	s, ok := t.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+
+	b, ok := s.Elem().(*types.Basic)
+
+	return ok && b.Kind() == types.Byte
+}
+
+// marshaler returns true if values of type t (not pointers to t) implement ttlv.Marshaler,
+// or will once the generated code is added.
+func (g *generator) marshaler(t types.Type) bool {
+	if n, ok := t.(*types.Named); ok && g.annotated[n] {
+		return true
+	}
+
+	return hasMethod(t, marshalMethod)
+}
+
+// ptrMarshaler returns true if only pointers to t implement ttlv.Marshaler.
+func (g *generator) ptrMarshaler(t types.Type) bool {
+	return !g.marshaler(t) && hasMethod(types.NewPointer(t), marshalMethod)
+}
+
+// zeroIsValid returns true if the zero value of the field marshals to a valid KMIP value,
+// following the ttlv package's zeroIsValid().
+func (g *generator) zeroIsValid(fi *fieldInfo) bool {
+	if fi.enum || g.marshaler(fi.typ) || hasMethod(types.NewPointer(fi.typ), marshalMethod) {
+		return false
+	}
+
+	if isNamed(fi.typ, "math/big", "Int") {
+		return true
+	}
+
+	basic, ok := fi.typ.Underlying().(*types.Basic)
+
+	return ok && basic.Info()&(types.IsBoolean|types.IsNumeric) != 0
+}
+
+func (g *generator) annotatedStruct(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && g.annotated[n]
+}
+
+func (g *generator) unmarshaler(t types.Type) bool {
+	return hasMethod(types.NewPointer(t), unmarshalMethod) || g.annotatedStruct(t)
+}
+
+// qualifier records the packages referenced by the generated code.
+func (g *generator) qualifier(p *types.Package) string {
+	if p == g.pkg.types {
+		return ""
+	}
+
+	g.imports[p.Path()] = p.Name()
+
+	return p.Name()
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+func (g *generator) use(path string) {
+	g.imports[path] = filepath.Base(path)
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(&g.buf, format, args...)
+}
+
+func tagLit(t ttlv.Tag) string {
+	if t == ttlv.TagNone {
+		return "ttlv.TagNone"
+	}
+
+	return fmt.Sprintf("0x%06x", uint32(t))
+}
+
+// tagComment is appended to lines containing tag literals.
+func tagComment(t ttlv.Tag) string {
+	if t == ttlv.TagNone {
+		return ""
+	}
+
+	return " // " + t.String()
+}
+
+func (g *generator) source() ([]byte, error) {
+	for _, si := range g.structs {
+		if err := g.resolve(si); err != nil {
+			return nil, err
+		}
+	}
+
+	g.use(ttlvPkgPath)
+	g.use("reflect")
+
+	for _, si := range g.structs {
+		g.genFields(si)
+		g.genMarker(si)
+		g.genMarshal(si)
+		g.genUnmarshal(si)
+	}
+
+	body := g.buf.Bytes()
+
+	var out bytes.Buffer
+
+	out.WriteString("// Code generated by ttlvgen; DO NOT EDIT.\n\n")
+	out.WriteString("//go:build !" + buildTag + "\n\n")
+	out.WriteString("package " + g.pkg.types.Name() + "\n\n")
+	out.WriteString("import (\n")
+
+	paths := make([]string, 0, len(g.imports))
+	for p := range g.imports {
+		paths = append(paths, p)
+	}
+
+	sort.Strings(paths)
+
+	// standard library packages first
+	sort.SliceStable(paths, func(i, j int) bool {
+		return !strings.Contains(paths[i], ".") && strings.Contains(paths[j], ".")
+	})
+
+	for i, p := range paths {
+		if i > 0 && strings.Contains(p, ".") && !strings.Contains(paths[i-1], ".") {
+			out.WriteString("\n")
+		}
+
+		out.WriteString(strconv.Quote(p) + "\n")
+	}
+
+	out.WriteString(")\n\n")
+	out.Write(body)
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return out.Bytes(), merry.Prepend(err, "error formatting generated code")
+	}
+
+	return src, nil
+}
+
+// genMarker generates the TTLVGenerated method, which tells the encoder and decoder
+// which type the methods were generated for, so they aren't used when they are promoted
+// to structs embedding the type.
+func (g *generator) genMarker(si *structInfo) {
+	g.printf("// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.\n")
+	g.printf("func (*%s) TTLVGenerated() reflect.Type {\nreturn reflect.TypeFor[%s]()\n}\n\n", si.name, si.name)
+}
+
+func (g *generator) genFields(si *structInfo) {
+	g.printf("var %s = [...]ttlv.Field{\n", si.field)
+
+	for _, f := range si.fields {
+		g.printf("{Struct: reflect.TypeFor[%s](), Name: %q, Tag: %s, Flags: %q},%s\n", si.name, f.name, tagLit(f.tag), f.flags, tagComment(f.tag))
+	}
+
+	g.printf("}\n\n")
+}
+
+func (g *generator) fieldRef(si *structInfo, f *fieldInfo) string {
+	return fmt.Sprintf("%s[%d]", si.field, f.index)
+}
+
+// fieldPtr returns the expression passed to EncodeField and DecodeField for
+// the field.
+func fieldPtr(f *fieldInfo, pointer bool) string {
+	if pointer {
+		switch f.typ.Underlying().(type) {
+		case *types.Pointer, *types.Interface:
+			return "v." + f.name
+		}
+	}
+
+	return "&v." + f.name
+}
+
+func (g *generator) genMarshal(si *structInfo) {
+	g.printf("// MarshalTTLV implements ttlv.Marshaler.\n")
+	g.printf("func (v %s) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {\n", si.name)
+	g.printf("if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {\n")
+	g.printf("// the generated code encodes the tags of ttlv.DefaultRegistry\nreturn e.EncodeValue(tag, v)\n}\n\n")
+	g.printf("if tag == ttlv.TagNone {\n")
+
+	if si.tagField != "" {
+		g.printf("tag = v.%s\n", si.tagField)
+		g.printf("}\n\nif tag == ttlv.TagNone {\n")
+	}
+
+	if si.tag != ttlv.TagNone {
+		g.printf("tag = %s%s\n", tagLit(si.tag), tagComment(si.tag))
+	} else {
+		g.printf("return e.MarshalingError(tag, reflect.TypeFor[%s](), ttlv.ErrNoTag)\n", si.name)
+	}
+
+	g.printf("}\n\n")
+
+	if si.validate {
+		encodes := func(f *fieldInfo) string {
+			return g.encodesExpr("v."+f.name, f.typ, f.omitEmpty)
+		}
+
+		// a field is present if it will be encoded, and isn't empty
+		g.genValidation(si, func(f *fieldInfo, present bool) string {
+			e := encodes(f)
+			switch {
+			case e == "true" || e == g.emptyExpr("v."+f.name, f.typ, true):
+				return g.emptyExpr("v."+f.name, f.typ, present)
+			case present:
+				return e
+			default:
+				return "!(" + e + ")"
+			}
+		}, encodes)
+	}
+
+	g.printf("return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {\n")
+
+	for _, f := range si.fields {
+		g.genEncodeField(si, f)
+	}
+
+	g.printf("return nil\n")
+	g.printf("})\n")
+	g.printf("}\n\n")
+}
+
+func (g *generator) genEncodeField(si *structInfo, f *fieldInfo) {
+	fallback := fmt.Sprintf("if err := e.EncodeField(%s, %s); err != nil {\nreturn err\n}\n", g.fieldRef(si, f), fieldPtr(f, true))
+
+	if f.dynamic || f.encodeTag == ttlv.TagNone || isTTLVType(f.typ, "TTLV") {
+		g.printf("%s\n", fallback)
+		return
+	}
+
+	x := "v." + f.name
+	t := f.typ
+
+	// slices (other than byte slices) and arrays encode each element with
+	// the field's tag.  omitempty applies to the slice, not the elements.
+	if elem, ok := sequenceElem(t); ok && !g.marshaler(t) && !g.ptrMarshaler(t) {
+		code, ok := g.encodeValue(si, f, x+"[i]", elem, false)
+		if !ok {
+			g.printf("%s\n", fallback)
+			return
+		}
+
+		g.printf("for i := range %s {\n%s}\n\n", x, code)
+
+		return
+	}
+
+	code, ok := g.encodeValue(si, f, x, t, f.omitEmpty)
+	if !ok {
+		g.printf("%s\n", fallback)
+		return
+	}
+
+	g.printf("%s\n", code)
+}
+
+// sequenceElem returns the element type of slices and arrays which encode
+// as a sequence of values.
+func sequenceElem(t types.Type) (types.Type, bool) {
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		if isByteSlice(t) {
+			return nil, false
+		}
+
+		return u.Elem(), true
+	case *types.Array:
+		return u.Elem(), true
+	default:
+		return nil, false
+	}
+}
+
+// encodeValue returns code which encodes x, a value of type t, the same way
+// as the reflection based encoder, or false if there is no fast path.
+func (g *generator) encodeValue(si *structInfo, f *fieldInfo, x string, t types.Type, omitEmpty bool) (string, bool) {
+	tag := tagLit(f.encodeTag)
+	comment := tagComment(f.encodeTag)
+
+	var guards []string
+
+	// the encoder skips nil pointers, and encodes what they point to
+	recv := x
+
+	if p, ok := t.(*types.Pointer); ok {
+		guards = append(guards, x+" != nil")
+		t = p.Elem()
+		x = "(*" + x + ")"
+
+		switch t.Underlying().(type) {
+		case *types.Pointer, *types.Interface:
+			return "", false
+		}
+	}
+
+	// the encoder skips nil slices
+	if _, ok := t.Underlying().(*types.Slice); ok {
+		guards = append(guards, x+" != nil")
+	}
+
+	if omitEmpty {
+		if notEmpty := g.emptyExpr(x, t, true); notEmpty != "true" {
+			guards = append(guards, notEmpty)
+		}
+	}
+
+	var code string
+
+	switch {
+	case g.marshaler(t):
+		code = fmt.Sprintf("if err := %s.MarshalTTLV(e, %s); err != nil {%s\nreturn err\n}\n", recv, tag, comment)
+	case g.ptrMarshaler(t), isTTLVType(t, "TTLV"), isNamed(t, "math/big", "Int") && (f.enum || f.bitmask):
+		return "", false
+	default:
+		var ok bool
+
+		code, ok = g.encodePrimitive(si, f, x, t)
+		if !ok {
+			return "", false
+		}
+	}
+
+	if len(guards) > 0 {
+		code = "if " + strings.Join(guards, " && ") + " {\n" + code + "}\n"
+	}
+
+	return code, true
+}
+
+func (g *generator) encodePrimitive(si *structInfo, f *fieldInfo, x string, t types.Type) (string, bool) {
+	tag := tagLit(f.encodeTag)
+	comment := tagComment(f.encodeTag)
+	basic, _ := t.Underlying().(*types.Basic)
+
+	// the encoder tries to encode integers and strings as enums or bitmasks if
+	// the tag is registered as one, or the field has the enum or bitmask flag.
+	enumKind := basic != nil && (basic.Kind() == types.String || basic.Info()&types.IsInteger != 0 &&
+		basic.Kind() != types.Int64 && basic.Kind() != types.Uint64 && basic.Kind() != types.Uintptr)
+	registered := ttlv.DefaultRegistry.EnumForTag(f.encodeTag) != nil
+
+	switch {
+	case (f.enum || f.bitmask) && (registered || !enumKind || basic.Kind() == types.String):
+		// strings are parsed against the registry at runtime, and other types are errors
+		return "", false
+	case f.bitmask:
+		return fmt.Sprintf("e.EncodeInteger(%s, %s)%s\n", tag, g.convert(x, t, "int32"), comment), true
+	case f.enum:
+		return fmt.Sprintf("e.EncodeEnumeration(%s, %s)%s\n", tag, g.convert(x, t, "uint32"), comment), true
+	}
+
+	var code string
+
+	switch {
+	case isNamed(t, "time", "Time"):
+		if f.dateTimeExt {
+			return fmt.Sprintf("e.EncodeDateTimeExtended(%s, %s)%s\n", tag, x, comment), true
+		}
+
+		return fmt.Sprintf("e.EncodeDateTime(%s, %s)%s\n", tag, x, comment), true
+	case isNamed(t, "math/big", "Int"):
+		return fmt.Sprintf("e.EncodeBigInteger(%s, &%s)%s\n", tag, x, comment), true
+	case isNamed(t, "time", "Duration"):
+		return fmt.Sprintf("e.EncodeInterval(%s, %s)%s\n", tag, x, comment), true
+	case isByteSlice(t):
+		return fmt.Sprintf("e.EncodeByteString(%s, %s)%s\n", tag, g.convert(x, t, "[]byte"), comment), true
+	case basic == nil:
+		return "", false
+	}
+
+	switch basic.Kind() {
+	case types.String:
+		code = fmt.Sprintf("e.EncodeTextString(%s, %s)%s\n", tag, g.convert(x, t, "string"), comment)
+	case types.Bool:
+		code = fmt.Sprintf("e.EncodeBoolean(%s, %s)%s\n", tag, g.convert(x, t, "bool"), comment)
+	case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16:
+		code = fmt.Sprintf("e.EncodeInteger(%s, %s)%s\n", tag, g.convert(x, t, "int32"), comment)
+	case types.Int, types.Uint, types.Uint32:
+		// values which overflow an Integer are passed to the reflection based
+		// encoder, which returns the error
+		g.use("math")
+
+		code = fmt.Sprintf("if %s > math.MaxInt32 {\nreturn e.EncodeField(%s, %s)\n}\n\ne.EncodeInteger(%s, %s)%s\n",
+			x, g.fieldRef(si, f), x, tag, g.convert(x, t, "int32"), comment)
+	case types.Int64, types.Uint64:
+		code = fmt.Sprintf("e.EncodeLongInteger(%s, %s)%s\n", tag, g.convert(x, t, "int64"), comment)
+	default:
+		return "", false
+	}
+
+	if enumKind && registered {
+		// whether the tag is an enum depends on what's registered at runtime
+		code = fmt.Sprintf("if e.EnumForTag(%s) != nil {\nif err := e.EncodeField(%s, &%s); err != nil {\nreturn err\n}\n} else {\n%s}\n",
+			tag, g.fieldRef(si, f), x, code)
+	}
+
+	return code, true
+}
+
+// convert returns x converted to the named type, if it isn't already that type.
+func (g *generator) convert(x string, t types.Type, typeName string) string {
+	if types.TypeString(t, nil) == typeName {
+		return x
+	}
+
+	return typeName + "(" + x + ")"
+}
+
+// emptyExpr returns an expression which is true if x, a value of type t, is
+// empty according to the ttlv package's isEmptyValue().  If not is true, the
+// expression is negated.
+func (g *generator) emptyExpr(x string, t types.Type, not bool) string {
+	pick := func(empty, notEmpty string) string {
+		if not {
+			return notEmpty
+		}
+
+		return empty
+	}
+
+	switch {
+	case isNamed(t, "time", "Time"):
+		return pick(x+".IsZero()", "!"+x+".IsZero()")
+	case isNamed(t, "math/big", "Int"):
+		return pick(x+".Sign() == 0", x+".Sign() != 0")
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Kind() == types.String:
+			return pick("len("+x+") == 0", "len("+x+") != 0")
+		case u.Kind() == types.Bool:
+			return pick("!"+x, x)
+		case u.Info()&types.IsNumeric != 0:
+			return pick(x+" == 0", x+" != 0")
+		}
+	case *types.Slice, *types.Array, *types.Map:
+		return pick("len("+x+") == 0", "len("+x+") != 0")
+	case *types.Pointer, *types.Interface:
+		return pick(x+" == nil", x+" != nil")
+	}
+
+	return pick("false", "true")
+}
+
+// encodesExpr returns an expression which is true if encoding x writes at least
+// one value, following the ttlv package's fieldInfo.encodes().
+func (g *generator) encodesExpr(x string, t types.Type, omitEmpty bool) string {
+	if p, ok := t.(*types.Pointer); ok {
+		if omitEmpty {
+			return x + " != nil && " + g.emptyExpr("(*"+x+")", p.Elem(), true)
+		}
+
+		return x + " != nil"
+	}
+
+	if _, ok := sequenceElem(t); ok {
+		return "len(" + x + ") != 0"
+	}
+
+	if omitEmpty {
+		return g.emptyExpr(x, t, true)
+	}
+
+	return "true"
+}
+
+// genValidation generates the checks for the required, min, max, and oneof
+// flags.  has returns an expression which is true if a field has a value, or
+// if present is false, if it doesn't.  encoded returns an expression which is
+// true if a field's value is encoded, and must be checked against its min and max.
+func (g *generator) genValidation(si *structInfo, has func(f *fieldInfo, present bool) string, encoded func(f *fieldInfo) string) {
+	var groups []string
+
+	for _, f := range si.fields {
+		if f.required {
+			g.printf("if %s {\nreturn ttlv.NewValidationError(reflect.TypeFor[%s](), %q, %s, ttlv.ErrMissingValue)%s\n}\n\n",
+				has(f, false), si.name, f.name, tagLit(f.tag), tagComment(f.tag))
+		}
+
+		if f.hasMin || f.hasMax {
+			if cond, ok := g.outOfRangeExpr(f); ok {
+				if r := encoded(f); r != "true" {
+					cond = r + " && (" + cond + ")"
+				}
+
+				g.printf("if %s {\nreturn ttlv.NewValidationError(reflect.TypeFor[%s](), %q, %s, ttlv.ErrValueOutOfRange)%s\n}\n\n",
+					cond, si.name, f.name, tagLit(f.tag), tagComment(f.tag))
+			}
+		}
+
+		if f.isOneOf && !contains(groups, f.oneOf) {
+			groups = append(groups, f.oneOf)
+		}
+	}
+
+	for i, group := range groups {
+		var names []string
+
+		counter := "count" + strconv.Itoa(i)
+		g.printf("var %s int\n", counter)
+
+		for _, f := range si.fields {
+			if !f.isOneOf || f.oneOf != group {
+				continue
+			}
+
+			names = append(names, f.name)
+			g.printf("if %s {\n%s++\n}\n", has(f, true), counter)
+		}
+
+		g.printf("\nswitch {\ncase %s == 0:\nreturn ttlv.NewValidationError(reflect.TypeFor[%s](), %q, ttlv.TagNone, ttlv.ErrMissingValue)\n", counter, si.name, strings.Join(names, "|"))
+		g.printf("case %s > 1:\nreturn ttlv.NewValidationError(reflect.TypeFor[%s](), %q, ttlv.TagNone, ttlv.ErrMultipleValues)\n}\n\n", counter, si.name, strings.Join(names, "|"))
+	}
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// outOfRangeExpr returns an expression which is true if the field's value is outside
+// its min and max, following the ttlv package's fieldInfo.inRange().
+func (g *generator) outOfRangeExpr(f *fieldInfo) (string, bool) {
+	x := "v." + f.name
+	t := f.typ
+
+	var guard string
+
+	if p, ok := t.(*types.Pointer); ok {
+		guard = x + " != nil && "
+		x = "(*" + x + ")"
+		t = p.Elem()
+	}
+
+	if isNamed(t, "math/big", "Int") {
+		g.use("math/big")
+
+		var conds []string
+		if f.hasMin {
+			conds = append(conds, fmt.Sprintf("%s.Cmp(big.NewInt(%d)) < 0", x, f.min))
+		}
+
+		if f.hasMax {
+			conds = append(conds, fmt.Sprintf("%s.Cmp(big.NewInt(%d)) > 0", x, f.max))
+		}
+
+		return guard + "(" + strings.Join(conds, " || ") + ")", true
+	}
+
+	var n string
+
+	unsigned := false
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Kind() == types.String:
+			n = "int64(len(" + x + "))"
+		case u.Info()&types.IsUnsigned != 0:
+			n = "uint64(" + x + ")"
+			unsigned = true
+		case u.Info()&types.IsInteger != 0:
+			n = "int64(" + x + ")"
+		default:
+			return "", false
+		}
+	case *types.Slice, *types.Array:
+		n = "int64(len(" + x + "))"
+	default:
+		return "", false
+	}
+
+	var conds []string
+
+	if f.hasMin {
+		switch {
+		case !unsigned:
+			conds = append(conds, fmt.Sprintf("%s < %d", n, f.min))
+		case f.min > 0:
+			conds = append(conds, fmt.Sprintf("%s < %d", n, f.min))
+		}
+	}
+
+	if f.hasMax {
+		switch {
+		case !unsigned:
+			conds = append(conds, fmt.Sprintf("%s > %d", n, f.max))
+		case f.max < 0:
+			conds = append(conds, "true")
+		default:
+			conds = append(conds, fmt.Sprintf("%s > %d", n, f.max))
+		}
+	}
+
+	if len(conds) == 0 {
+		return "", false
+	}
+
+	return guard + "(" + strings.Join(conds, " || ") + ")", true
+}
+
+func (g *generator) genUnmarshal(si *structInfo) {
+	g.printf("// UnmarshalTTLV implements ttlv.Unmarshaler.\n")
+	g.printf("func (v *%s) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {\n", si.name)
+	g.printf("if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {\n")
+	g.printf("// the generated code decodes the tags of ttlv.DefaultRegistry\nreturn d.DecodeValue(v, t)\n}\n\n")
+	g.printf("if len(t) == 0 {\nreturn nil\n}\n\n")
+	g.printf("if t.Type() != ttlv.TypeStructure {\nreturn d.UnmarshalingError(t, reflect.TypeFor[%s](), ttlv.ErrUnsupportedTypeError)\n}\n\n", si.name)
+	g.printf("if err := d.EnterStructure(t, reflect.TypeFor[%s]()); err != nil {\nreturn err\n}\n\ndefer d.LeaveStructure()\n\n", si.name)
+
+	if si.tagField != "" {
+		g.printf("v.%s = t.Tag()\n\n", si.tagField)
+	}
+
+	if si.validate {
+		g.printf("var present [%d]bool\n\n", len(si.fields))
+	}
+
+	g.printf("for n := t.ValueStructure(); n != nil; n = n.Next() {\n")
+	g.printf("switch n.Tag() {\n")
+
+	var anyField *fieldInfo
+
+	for _, f := range si.fields {
+		if f.any {
+			if anyField == nil {
+				anyField = f
+			}
+
+			continue
+		}
+
+		if f.tag == ttlv.TagNone {
+			continue
+		}
+
+		g.printf("case %s:%s\n", tagLit(f.tag), tagComment(f.tag))
+
+		if si.validate {
+			g.printf("present[%d] = true\n\n", f.index)
+		}
+
+		g.genDecodeField(si, f)
+	}
+
+	g.printf("default:\n")
+
+	switch {
+	case anyField != nil:
+		if si.validate {
+			g.printf("present[%d] = true\n\n", anyField.index)
+		}
+
+		g.printf("if err := d.DecodeField(%s, %s, n); err != nil {\nreturn err\n}\n", g.fieldRef(si, anyField), fieldPtr(anyField, false))
+	default:
+		g.printf("if d.DisallowExtraValues {\nreturn d.UnmarshalingError(t, reflect.TypeFor[%s](), ttlv.ErrUnexpectedValue)\n}\n", si.name)
+	}
+
+	g.printf("}\n}\n\n")
+
+	if si.validate {
+		has := func(f *fieldInfo, present bool) string {
+			if present {
+				return fmt.Sprintf("present[%d]", f.index)
+			}
+
+			return fmt.Sprintf("!present[%d]", f.index)
+		}
+
+		g.genValidation(si, has, func(f *fieldInfo) string {
+			return has(f, true)
+		})
+	}
+
+	g.printf("return nil\n}\n\n")
+}
+
+func (g *generator) genDecodeField(si *structInfo, f *fieldInfo) {
+	fallback := fmt.Sprintf("if err := d.DecodeField(%s, %s, n); err != nil {\nreturn err\n}\n", g.fieldRef(si, f), fieldPtr(f, false))
+	x := "v." + f.name
+	t := f.typ
+
+	if g.unmarshaler(t) && !g.annotatedStruct(t) {
+		g.printf("%s", fallback)
+		return
+	}
+
+	switch u := t.(type) {
+	case *types.Pointer:
+		// allocate pointers to annotated structs
+		if g.annotatedStruct(u.Elem()) {
+			g.printf("if n.Type() != ttlv.TypeStructure {\n%s} else {\nif %s == nil {\n%s = new(%s)\n}\n\n", fallback, x, x, g.typeString(u.Elem()))
+			g.printf("if err := %s.UnmarshalTTLV(d, n); err != nil {\nreturn d.FieldError(%s, -1, err)\n}\n}\n", x, g.fieldRef(si, f))
+
+			return
+		}
+
+		g.printf("%s", fallback)
+
+		return
+	default:
+	}
+
+	if g.annotatedStruct(t) {
+		g.printf("if n.Type() != ttlv.TypeStructure {\n%s} else if err := %s.UnmarshalTTLV(d, n); err != nil {\nreturn d.FieldError(%s, -1, err)\n}\n", fallback, x, g.fieldRef(si, f))
+		return
+	}
+
+	// slices, other than byte slices, append each value.  Arrays are left to
+	// the reflection based decoder.
+	if s, ok := t.Underlying().(*types.Slice); ok && !isByteSlice(t) {
+		elem := s.Elem()
+
+		if g.annotatedStruct(elem) {
+			g.printf("if n.Type() != ttlv.TypeStructure {\n%s} else {\n%s = append(%s, %s{})\n\n", fallback, x, x, g.typeString(elem))
+			g.printf("if err := %s[len(%s)-1].UnmarshalTTLV(d, n); err != nil {\n%s = %s[:len(%s)-1]\nreturn d.FieldError(%s, len(%s), err)\n}\n}\n", x, x, x, x, x, g.fieldRef(si, f), x)
+
+			return
+		}
+
+		cases, ok := g.decodePrimitive(elem)
+		if !ok || g.unmarshaler(elem) {
+			g.printf("%s", fallback)
+			return
+		}
+
+		g.printf("switch n.Type() {\n")
+
+		for _, c := range cases {
+			g.printf("case ttlv.Type%s:\n%s = append(%s, %s)\n", c.typ, x, x, c.expr)
+		}
+
+		g.printf("default:\n%s}\n", fallback)
+
+		return
+	}
+
+	cases, ok := g.decodePrimitive(t)
+	if !ok {
+		g.printf("%s", fallback)
+		return
+	}
+
+	g.printf("switch n.Type() {\n")
+
+	for _, c := range cases {
+		g.printf("case ttlv.Type%s:\n%s = %s\n", c.typ, x, c.expr)
+	}
+
+	g.printf("default:\n%s}\n", fallback)
+}
+
+type decodeCase struct {
+	typ  string
+	expr string
+}
+
+// decodePrimitive returns the TTLV types which can be decoded into type t without
+// any checks, and the expressions converting them.
+func (g *generator) decodePrimitive(t types.Type) ([]decodeCase, bool) {
+	conv := func(typ, method, result string) decodeCase {
+		expr := "n." + method + "()"
+		if types.TypeString(t, nil) != result {
+			expr = g.typeString(t) + "(" + expr + ")"
+		}
+
+		return decodeCase{typ: typ, expr: expr}
+	}
+
+	switch {
+	case isNamed(t, "time", "Time"):
+		return []decodeCase{
+			{typ: "DateTime", expr: "n.ValueDateTime()"},
+			{typ: "DateTimeExtended", expr: "n.ValueDateTime()"},
+		}, true
+	case isByteSlice(t):
+		return []decodeCase{conv("ByteString", "ValueByteString", "[]byte")}, true
+	}
+
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return nil, false
+	}
+
+	switch basic.Kind() {
+	case types.String:
+		return []decodeCase{conv("TextString", "ValueTextString", "string")}, true
+	case types.Bool:
+		return []decodeCase{conv("Boolean", "ValueBoolean", "bool")}, true
+	case types.Int, types.Int32:
+		return []decodeCase{conv("Integer", "ValueInteger", "int32")}, true
+	case types.Uint, types.Uint32:
+		return []decodeCase{conv("Enumeration", "ValueEnumeration", "uint32")}, true
+	case types.Int64:
+		return []decodeCase{conv("Interval", "ValueInterval", "time.Duration"), conv("LongInteger", "ValueLongInteger", "int64")}, true
+	case types.Uint64:
+		return []decodeCase{conv("LongInteger", "ValueLongInteger", "int64")}, true
+	default:
+		return nil, false
+	}
+}
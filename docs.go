@@ -1,3 +1,5 @@
+//go:generate go run -tags ttlvreflect ./cmd/ttlvgen
+
 // Package kmip is a general purpose KMIP library for implementing KMIP services and clients.
 //
 // The ttlv sub package contains the core logic for parsing the KMIP TTLV encoding formats,
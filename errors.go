@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
 	"github.com/ansel1/merry"
 )
 
@@ -33,7 +34,15 @@ func GetResultReason(err error) kmip14.ResultReason {
 	v := merry.Value(err, errorKeyResultReason)
 	switch t := v.(type) {
 	case nil:
-		return kmip14.ResultReason(0)
+		// validation errors from ttlv.Unmarshal and ttlv.Marshal map to standard reasons
+		switch {
+		case errors.Is(err, ttlv.ErrMissingValue):
+			return kmip14.ResultReasonMissingData
+		case errors.Is(err, ttlv.ErrValueOutOfRange), errors.Is(err, ttlv.ErrMultipleValues):
+			return kmip14.ResultReasonInvalidField
+		default:
+			return kmip14.ResultReason(0)
+		}
 	case kmip14.ResultReason:
 		return t
 	default:
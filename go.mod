@@ -8,6 +8,7 @@ require (
 	github.com/gemalto/flume v0.13.1
 	github.com/google/uuid v1.6.0
 	github.com/stretchr/testify v1.9.0
+	golang.org/x/crypto v0.28.0
 	golang.org/x/text v0.19.0
 )
 
@@ -17,7 +17,33 @@ func init() {
 	Register(&ttlv.DefaultRegistry)
 }
 
-// Registers the 1.4 enumeration values with the registry.
+// Registers the 1.4 enumeration values with the registry, and marks the tags in
+// SensitiveTags as sensitive.
 func Register(registry *ttlv.Registry) {
 	RegisterGeneratedDefinitions(registry)
+
+	for _, t := range SensitiveTags {
+		registry.SetSensitive(t, true)
+	}
+}
+
+// SensitiveTags are the tags whose values are secret: key material (including the
+// key material of split key parts), private key components, passwords, and the
+// plaintext of cryptographic operations.  ttlv.Print and the server's traffic log
+// redact their values.
+//
+// P and Q are also the public domain parameters of DSA and DH keys, but are redacted
+// everywhere, since they are the secret primes of RSA private keys.
+var SensitiveTags = []ttlv.Tag{
+	TagKeyMaterial,
+	TagPassword,
+	TagPrivateExponent,
+	TagP,
+	TagQ,
+	TagPrimeExponentP,
+	TagPrimeExponentQ,
+	TagCRTCoefficient,
+	TagD,
+	TagX,
+	TagData,
 }
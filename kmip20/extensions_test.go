@@ -0,0 +1,98 @@
+package kmip20
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterExtensions(t *testing.T) {
+	r := &ttlv.Registry{}
+	ttlv.RegisterTypes(r)
+	kmip14.Register(r)
+	Register(r)
+
+	// a query response, as decoded from the server
+	b, err := ttlv.Marshal(ttlv.Value{Tag: kmip14.TagResponsePayload, Value: ttlv.Values{
+		{Tag: kmip14.TagExtensionInformation, Value: ttlv.Values{
+			{Tag: kmip14.TagExtensionName, Value: "Vendor Key State"},
+			{Tag: kmip14.TagExtensionTag, Value: 0x540001},
+			{Tag: kmip14.TagExtensionType, Value: ttlv.EnumValue(ttlv.TypeEnumeration)},
+		}},
+		{Tag: kmip14.TagExtensionInformation, Value: ttlv.Values{
+			{Tag: kmip14.TagExtensionName, Value: "Frozen"},
+			{Tag: kmip14.TagExtensionTag, Value: 0x540001},
+			{Tag: TagExtensionEnumeration, Value: 0x7fffffff},
+		}},
+		{Tag: kmip14.TagExtensionInformation, Value: ttlv.Values{
+			{Tag: kmip14.TagExtensionName, Value: "Vendor Blob"},
+			{Tag: kmip14.TagExtensionTag, Value: int(kmip14.TagObjectType)},
+			{Tag: TagExtensionEnumeration, Value: -0x7fffffff}, // 0x80000001
+		}},
+		{Tag: kmip14.TagExtensionInformation, Value: ttlv.Values{
+			{Tag: kmip14.TagExtensionName, Value: "Frozen"},
+			{Tag: kmip14.TagExtensionTag, Value: 0x540001},
+			{Tag: TagExtensionEnumeration, Value: -0x7ffffffe}, // 0x80000002
+		}},
+		{Tag: kmip14.TagExtensionInformation, Value: ttlv.Values{
+			{Tag: kmip14.TagExtensionName, Value: "Nameless"},
+		}},
+	}})
+	require.NoError(t, err)
+
+	var resp QueryResponsePayload
+	require.NoError(t, ttlv.Unmarshal(b, &resp))
+	require.Len(t, resp.ExtensionInformation, 5)
+
+	// the out of range enumeration value is rejected, and nothing is registered
+	err = RegisterExtensions(r, resp.ExtensionInformation)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "0x7fffffff")
+	assert.Equal(t, "0x540001", r.FormatTag(0x540001))
+
+	resp.ExtensionInformation = append(resp.ExtensionInformation[:1], resp.ExtensionInformation[2:]...)
+	require.NoError(t, RegisterExtensions(r, resp.ExtensionInformation))
+
+	assert.Equal(t, "VendorKeyState", r.FormatTag(0x540001))
+	assert.Equal(t, "Vendor Key State", r.FormatTagCanonical(0x540001))
+	assert.Equal(t, "Frozen", r.FormatEnum(0x540001, 0x80000002))
+
+	// the standard values of extended enumerations are kept
+	assert.Equal(t, "VendorBlob", r.FormatEnum(kmip14.TagObjectType, 0x80000001))
+	assert.Equal(t, "SymmetricKey", r.FormatEnum(kmip14.TagObjectType, uint32(kmip14.ObjectTypeSymmetricKey)))
+
+	v, err := ttlv.Marshal(ttlv.Value{Tag: kmip14.TagRequestPayload, Value: ttlv.Values{
+		{Tag: kmip14.TagObjectType, Value: ttlv.EnumValue(0x80000001)},
+		{Tag: 0x540001, Value: ttlv.EnumValue(0x80000002)},
+	}})
+	require.NoError(t, err)
+
+	var sb strings.Builder
+
+	require.NoError(t, r.Print(&sb, "", "  ", v))
+	assert.Contains(t, sb.String(), "ObjectType (Enumeration/4): VendorBlob")
+	assert.Contains(t, sb.String(), "VendorKeyState (Enumeration/4): Frozen")
+
+	var buf bytes.Buffer
+
+	enc := ttlv.NewJSONEncoder(&buf)
+	enc.Registry = r
+	require.NoError(t, enc.Encode(v))
+	assert.Contains(t, buf.String(), `"tag":"VendorKeyState","type":"Enumeration","value":"Frozen"`)
+}
+
+func TestRegisterExtensions_standardTag(t *testing.T) {
+	r := &ttlv.Registry{}
+	kmip14.Register(r)
+
+	err := RegisterExtensions(r, []ExtensionInformation{
+		{ExtensionName: "Not A Batch Count", ExtensionTag: int(kmip14.TagBatchCount)},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "BatchCount", r.FormatTag(kmip14.TagBatchCount))
+}
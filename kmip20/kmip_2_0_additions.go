@@ -1,33 +1,39 @@
-//go:generate go run ../cmd/kmipgen/main.go -o kmip_2_0_additions_generated.go -i kmip_2_0_additions.json -p kmip20
+//go:generate go run ../cmd/kmipgen/main.go -o kmip_2_0_additions_generated.go -i kmip_2_0_additions.json -p kmip20 -a github.com/Seagate/kmip-go/kmip20/names
 
 // Package kmip20 contains definitions from the 2.0 specification.  They should eventually
 // be merged into the kmip_1_4.json (and that should be renamed to kmip_2_0_specs.json),
 // but I didn't have time to merge them in yet.  Just keeping them parked here until I have time
 // to incorporate them.
-// TODO: should the different versions of the spec be kept in separate declaration files?  Or should
-// the ttlv package add a spec version attribute to registration, so servers/clients can configure which
-// spec version they want to use, and ttlv would automatically filter allowed values on that?
+//
+// The definitions are registered as introduced in version 2.0, so a view of the registry
+// for an earlier version, such as ttlv.DefaultRegistry.ForVersion(ttlv.Version{Major: 1, Minor: 4}),
+// will reject them.
 package kmip20
 
 import (
-	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/kmip20/names"
 	"github.com/Seagate/kmip-go/ttlv"
 )
 
-//nolint:gochecknoinits
-func init() {
-	Register(&ttlv.DefaultRegistry)
-}
-
 // Register registers all the additional definitions from the KMIP 2.0 spec.  The registry
-// should already contain the 1.4 definitions.
+// should already contain the 1.4 definitions.  The definitions are registered with
+// ttlv.DefaultRegistry when the package is imported.
+//
+// Everything added to the registry is recorded as introduced in 2.0.  See the names
+// package to register them without importing this package.
 func Register(registry *ttlv.Registry) {
-	// register new 2.0 values
-	// KMIP 2.0 introduces a tag named "Attribute Reference", whose value is the enumeration of all Tags
-	registry.RegisterEnum(TagAttributeReference, registry.Tags())
+	names.Register(registry)
+}
+
+// SensitiveTags are the 2.0 tags whose values are secret, in addition to
+// kmip14.SensitiveTags.
+var SensitiveTags = names.SensitiveTags
 
-	// KMIP 2.0 has made the value of the Extension Type tag an enumeration of all type values
-	registry.RegisterEnum(kmip14.TagExtensionType, registry.Types())
+// ExtensionInformation describes a vendor extension, in a Query response.
+type ExtensionInformation = names.ExtensionInformation
 
-	RegisterGeneratedDefinitions(registry)
+// RegisterExtensions registers the names of the vendor extensions described by the
+// Extension Information of a Query response.  See names.RegisterExtensions.
+func RegisterExtensions(registry *ttlv.Registry, extensions []ExtensionInformation) error {
+	return names.RegisterExtensions(registry, extensions)
 }
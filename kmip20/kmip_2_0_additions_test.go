@@ -0,0 +1,40 @@
+package kmip20
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister_versions(t *testing.T) {
+	v14 := ttlv.DefaultRegistry.ForVersion(ttlv.Version{Major: 1, Minor: 4})
+	v20 := ttlv.DefaultRegistry.ForVersion(ttlv.Version{Major: 2})
+
+	b, err := ttlv.Marshal(ttlv.Value{Tag: kmip14.TagObjectType, Value: ObjectTypeCertificateRequest})
+	require.NoError(t, err)
+
+	require.NoError(t, v20.Validate(b))
+	require.True(t, errors.Is(v14.Validate(b), ttlv.ErrNotInVersion))
+
+	b, err = ttlv.Marshal(ttlv.Value{Tag: kmip14.TagObjectType, Value: ObjectTypeSymmetricKey})
+	require.NoError(t, err)
+
+	require.NoError(t, v14.Validate(b))
+
+	b, err = ttlv.Marshal(ttlv.Value{Tag: TagProtectionLevel, Value: ProtectionLevelHigh})
+	require.NoError(t, err)
+
+	require.NoError(t, v20.Validate(b))
+	require.True(t, errors.Is(v14.Validate(b), ttlv.ErrNotInVersion))
+
+	// JSON and XML decoders check each value against the view as it's read
+	dec := ttlv.NewJSONDecoder(strings.NewReader(`{"tag":"ProtectionLevel","type":"Enumeration","value":"High"}`))
+	dec.View = v14
+
+	var v interface{}
+	require.True(t, errors.Is(dec.Decode(&v), ttlv.ErrNotInVersion))
+}
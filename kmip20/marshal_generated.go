@@ -0,0 +1,196 @@
+// Code generated by ttlvgen; DO NOT EDIT.
+
+//go:build !ttlvreflect
+
+package kmip20
+
+import (
+	"reflect"
+
+	"github.com/Seagate/kmip-go/ttlv"
+)
+
+var ttlvCreateRequestPayloadFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[CreateRequestPayload](), Name: "ObjectType", Tag: 0x420057, Flags: ""},                      // ObjectType
+	{Struct: reflect.TypeFor[CreateRequestPayload](), Name: "Attributes", Tag: 0x420125, Flags: ""},                      // Attributes
+	{Struct: reflect.TypeFor[CreateRequestPayload](), Name: "ProtectionStorageMasks", Tag: 0x42015f, Flags: "omitempty"}, // ProtectionStorageMasks
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*CreateRequestPayload) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[CreateRequestPayload]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v CreateRequestPayload) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		tag = 0x420079 // RequestPayload
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := v.ObjectType.MarshalTTLV(e, 0x420057); err != nil { // ObjectType
+			return err
+		}
+
+		if err := e.EncodeField(ttlvCreateRequestPayloadFields[1], v.Attributes); err != nil {
+			return err
+		}
+
+		if v.ProtectionStorageMasks != 0 {
+			if err := v.ProtectionStorageMasks.MarshalTTLV(e, 0x42015f); err != nil { // ProtectionStorageMasks
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *CreateRequestPayload) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[CreateRequestPayload](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[CreateRequestPayload]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x420057: // ObjectType
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.ObjectType = ObjectType(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvCreateRequestPayloadFields[0], &v.ObjectType, n); err != nil {
+					return err
+				}
+			}
+		case 0x420125: // Attributes
+			if err := d.DecodeField(ttlvCreateRequestPayloadFields[1], &v.Attributes, n); err != nil {
+				return err
+			}
+		case 0x42015f: // ProtectionStorageMasks
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.ProtectionStorageMasks = ProtectionStorageMask(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvCreateRequestPayloadFields[2], &v.ProtectionStorageMasks, n); err != nil {
+					return err
+				}
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[CreateRequestPayload](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvCreateResponsePayloadFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[CreateResponsePayload](), Name: "ObjectType", Tag: 0x420057, Flags: ""},       // ObjectType
+	{Struct: reflect.TypeFor[CreateResponsePayload](), Name: "UniqueIdentifier", Tag: 0x420094, Flags: ""}, // UniqueIdentifier
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*CreateResponsePayload) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[CreateResponsePayload]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v CreateResponsePayload) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		return e.MarshalingError(tag, reflect.TypeFor[CreateResponsePayload](), ttlv.ErrNoTag)
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := v.ObjectType.MarshalTTLV(e, 0x420057); err != nil { // ObjectType
+			return err
+		}
+
+		if e.EnumForTag(0x420094) != nil {
+			if err := e.EncodeField(ttlvCreateResponsePayloadFields[1], &v.UniqueIdentifier); err != nil {
+				return err
+			}
+		} else {
+			e.EncodeTextString(0x420094, v.UniqueIdentifier) // UniqueIdentifier
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *CreateResponsePayload) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[CreateResponsePayload](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[CreateResponsePayload]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x420057: // ObjectType
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.ObjectType = ObjectType(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvCreateResponsePayloadFields[0], &v.ObjectType, n); err != nil {
+					return err
+				}
+			}
+		case 0x420094: // UniqueIdentifier
+			switch n.Type() {
+			case ttlv.TypeTextString:
+				v.UniqueIdentifier = n.ValueTextString()
+			default:
+				if err := d.DecodeField(ttlvCreateResponsePayloadFields[1], &v.UniqueIdentifier, n); err != nil {
+					return err
+				}
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[CreateResponsePayload](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package names
+
+import (
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/ansel1/merry"
+)
+
+// minExtensionEnumValue is the start of the range of enumeration values reserved for
+// extensions, 8XXXXXXX.
+const minExtensionEnumValue uint32 = 0x80000000
+
+// RegisterExtensions registers the names of the vendor extensions described by the
+// Extension Information of a Query response, so Print and the JSON and XML encodings
+// show them by name, e.g. VendorKeyState instead of 0x540001:
+//
+//	var resp kmip20.QueryResponsePayload
+//	// ... query the server with QueryFunctionQueryExtensionMap
+//	err := names.RegisterExtensions(&ttlv.DefaultRegistry, resp.ExtensionInformation)
+//
+// Each entry with an Extension Tag names that tag.  An entry which also has an Extension
+// Enumeration names a value of the enumeration with that tag instead, which may be a
+// standard tag, like a vendor Object Type.  Entries without an Extension Tag are ignored.
+// The other fields describe how the extensions are used, and aren't needed to name them.
+//
+// Extensions can't rename the standard tags and enumeration values: tags must be in the
+// extensions range, 0x54XXXX, and enumeration values in the range 8XXXXXXX.  If any entry
+// is invalid, nothing is registered.
+func RegisterExtensions(registry *ttlv.Registry, extensions []ExtensionInformation) error {
+	var tags []ExtensionInformation
+
+	enumValues := map[ttlv.Tag][]ExtensionInformation{}
+
+	for _, ext := range extensions {
+		tag := ttlv.Tag(ext.ExtensionTag)
+
+		switch {
+		case ext.ExtensionTag == 0:
+			continue
+		case ext.ExtensionName == "":
+			return merry.Errorf("extension %v has no name", tag)
+		case ext.ExtensionEnumeration != 0:
+			if uint32(ext.ExtensionEnumeration) < minExtensionEnumValue {
+				return merry.Errorf("extension %q: enumeration value %#08x of %v is not in the extensions range", ext.ExtensionName, uint32(ext.ExtensionEnumeration), tag)
+			}
+
+			if e := registry.EnumForTag(tag); e != nil && e.Bitmask() {
+				return merry.Errorf("extension %q: %v is a bitmask, not an enumeration", ext.ExtensionName, tag)
+			}
+
+			enumValues[tag] = append(enumValues[tag], ext)
+		case !tag.Extension():
+			return merry.Errorf("extension %q: tag %#06x is not in the extensions range", ext.ExtensionName, ext.ExtensionTag)
+		default:
+			tags = append(tags, ext)
+		}
+	}
+
+	for _, ext := range tags {
+		registry.RegisterTag(ttlv.Tag(ext.ExtensionTag), ext.ExtensionName)
+	}
+
+	for tag, exts := range enumValues {
+		// registered enums mustn't be modified, so register a copy with the new values
+		e := copyEnum(registry.EnumForTag(tag))
+		for _, ext := range exts {
+			e.RegisterValue(uint32(ext.ExtensionEnumeration), ext.ExtensionName)
+		}
+
+		registry.RegisterEnum(tag, &e)
+	}
+
+	return nil
+}
+
+// copyEnum returns a copy of the values registered in e, which may be nil.
+func copyEnum(e ttlv.EnumMap) ttlv.Enum {
+	c := ttlv.NewEnum()
+
+	if e == nil {
+		return c
+	}
+
+	for _, v := range e.Values() {
+		if name, ok := e.CanonicalName(v); ok {
+			c.RegisterValue(v, name)
+		}
+	}
+
+	return c
+}
@@ -0,0 +1,66 @@
+//go:generate go run ../../cmd/kmipgen/main.go -o names_generated.go -i ../kmip_2_0_additions.json -p names
+
+// Package names defines the tags and enumeration values added by the KMIP 2.0 specification,
+// and registers their names.  The kmip20 package aliases these constants and types, and adds
+// the payloads and operation handlers.  This package only depends on the ttlv and kmip14
+// packages, so tools which just print KMIP values, like ppkmip, can import it without linking
+// the kmip package's client and server.
+//
+// Importing the package registers the names with ttlv.DefaultRegistry.  The kmip20 package
+// imports it too.
+package names
+
+import (
+	// the 1.4 definitions must be registered first
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+)
+
+//nolint:gochecknoinits
+func init() {
+	Register(&ttlv.DefaultRegistry)
+}
+
+// Register registers all the additional definitions from the KMIP 2.0 spec.  The registry
+// should already contain the 1.4 definitions.
+//
+// Everything added to the registry is recorded as introduced in 2.0.
+func Register(registry *ttlv.Registry) {
+	registry.RegisterSince(ttlv.Version{Major: 2}, func(r *ttlv.Registry) {
+		// register new 2.0 values
+		// KMIP 2.0 introduces a tag named "Attribute Reference", whose value is the enumeration of all Tags
+		r.RegisterEnum(TagAttributeReference, r.Tags())
+
+		// KMIP 2.0 has made the value of the Extension Type tag an enumeration of all type values
+		r.RegisterEnum(kmip14.TagExtensionType, r.Types())
+
+		RegisterGeneratedDefinitions(r)
+	})
+
+	for _, t := range SensitiveTags {
+		registry.SetSensitive(t, true)
+	}
+}
+
+// SensitiveTags are the 2.0 tags whose values are secret, in addition to
+// kmip14.SensitiveTags.
+var SensitiveTags = []ttlv.Tag{
+	TagOneTimePassword,
+	TagHashedPassword,
+	TagServerHashedPassword,
+	TagTicketValue,
+}
+
+// 7.9 Extension Information
+// An Extension Information object is a structure describing Objects with Item Tag values in the Extensions range. The Extension Name
+// is a Text String that is used to name the Object. The Extension Tag is the Item Tag Value of the Object. The Extension Type is
+// the Item Type Value of the Object.
+type ExtensionInformation struct {
+	ExtensionName               string // Required: Yes
+	ExtensionTag                int    // Required: No
+	ExtensionType               int    // Required: No
+	ExtensionEnumeration        int    // Required: No
+	ExtensionAttribute          bool   // Required: No
+	ExtensionParentStructureTag int    // Required: No
+	ExtensionDescription        string // Required: No
+}
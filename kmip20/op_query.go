@@ -29,20 +29,6 @@ type DefaultsInformation struct {
 	ObjectDefaults ObjectDefaults // Required: Yes
 }
 
-// 7.9 Extension Information
-// An Extension Information object is a structure describing Objects with Item Tag values in the Extensions range. The Extension Name
-// is a Text String that is used to name the Object. The Extension Tag is the Item Tag Value of the Object. The Extension Type is
-// the Item Type Value of the Object.
-type ExtensionInformation struct {
-	ExtensionName               string // Required: Yes
-	ExtensionTag                int    // Required: No
-	ExtensionType               int    // Required: No
-	ExtensionEnumeration        int    // Required: No
-	ExtensionAttribute          bool   // Required: No
-	ExtensionParentStructureTag int    // Required: No
-	ExtensionDescription        string // Required: No
-}
-
 // 7.18 Object Defaults
 // The Object Defaults is a structure that details the values that the server will use if the client omits them on factory methods for
 // objects. The structure list the Attributes and  their values by Object Type enumeration.
@@ -1,3 +1,5 @@
+//go:generate go run -tags ttlvreflect ../cmd/ttlvgen
+
 package kmip20
 
 import "github.com/Seagate/kmip-go/ttlv"
@@ -6,6 +8,7 @@ type Attributes struct {
 	Values ttlv.Values
 }
 
+//ttlvgen:generate
 type CreateRequestPayload struct {
 	TTLVTag                struct{} `ttlv:"RequestPayload"`
 	ObjectType             ObjectType
@@ -13,6 +16,7 @@ type CreateRequestPayload struct {
 	ProtectionStorageMasks ProtectionStorageMask `ttlv:",omitempty"`
 }
 
+//ttlvgen:generate
 type CreateResponsePayload struct {
 	ObjectType       ObjectType
 	UniqueIdentifier string
@@ -58,6 +58,25 @@ func TestCreateRequestPayload(t *testing.T) {
 				),
 			),
 		},
+		{
+			name: "mapforattrs",
+			in: CreateRequestPayload{
+				ObjectType: ObjectTypeSymmetricKey,
+				Attributes: map[string]interface{}{
+					"CryptographicUsageMask": kmip14.CryptographicUsageMaskEncrypt | kmip14.CryptographicUsageMaskDecrypt,
+					"CryptographicAlgorithm": CryptographicAlgorithmARIA,
+					"CryptographicLength":    56,
+				},
+			},
+			expected: s(kmip14.TagRequestPayload,
+				v(kmip14.TagObjectType, ObjectTypeSymmetricKey),
+				s(TagAttributes,
+					v(kmip14.TagCryptographicAlgorithm, CryptographicAlgorithmARIA),
+					v(kmip14.TagCryptographicLength, 56),
+					v(kmip14.TagCryptographicUsageMask, kmip14.CryptographicUsageMaskEncrypt|kmip14.CryptographicUsageMaskDecrypt),
+				),
+			),
+		},
 		{
 			name: "attributesstruct",
 			in: CreateRequestPayload{
@@ -116,6 +135,79 @@ func TestCreateRequestPayload(t *testing.T) {
 	}
 }
 
+func TestResolveType(t *testing.T) {
+	b, err := ttlv.Marshal(s(kmip14.TagBatchItem,
+		v(kmip14.TagOperation, OperationCreate),
+		s(kmip14.TagRequestPayload,
+			v(kmip14.TagObjectType, ObjectTypeSymmetricKey),
+			s(TagAttributes,
+				v(kmip14.TagCryptographicAlgorithm, CryptographicAlgorithmAES),
+				v(kmip14.TagCryptographicLength, 256),
+			),
+		),
+	))
+	require.NoError(t, err)
+
+	var item struct {
+		Operation      Operation
+		RequestPayload interface{}
+	}
+
+	dec := ttlv.NewDecoder(nil)
+	dec.TypeResolver = ResolveType
+	require.NoError(t, dec.DecodeValue(&item, b))
+
+	require.Equal(t, &CreateRequestPayload{
+		ObjectType: ObjectTypeSymmetricKey,
+		Attributes: s(TagAttributes,
+			v(kmip14.TagCryptographicAlgorithm, ttlv.EnumValue(CryptographicAlgorithmAES)),
+			v(kmip14.TagCryptographicLength, int32(256)),
+		),
+	}, item.RequestPayload)
+}
+
+func benchmarkCreateRequestPayload() CreateRequestPayload {
+	return CreateRequestPayload{
+		ObjectType: ObjectTypeSymmetricKey,
+		Attributes: Attributes{
+			Values: ttlv.Values{
+				v(kmip14.TagCryptographicAlgorithm, CryptographicAlgorithmAES),
+				v(kmip14.TagCryptographicLength, 256),
+				v(kmip14.TagCryptographicUsageMask, kmip14.CryptographicUsageMaskEncrypt|kmip14.CryptographicUsageMaskDecrypt),
+			},
+		},
+	}
+}
+
+func BenchmarkMarshalCreateRequestPayload(b *testing.B) {
+	p := benchmarkCreateRequestPayload()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := ttlv.Marshal(&p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalCreateRequestPayload(b *testing.B) {
+	in := benchmarkCreateRequestPayload()
+
+	buf, err := ttlv.Marshal(&in)
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var p CreateRequestPayload
+		if err := ttlv.Unmarshal(buf, &p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func v(tag ttlv.Tag, val interface{}) ttlv.Value {
 	return ttlv.NewValue(tag, val)
 }
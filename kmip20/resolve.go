@@ -0,0 +1,78 @@
+package kmip20
+
+import (
+	"reflect"
+
+	"github.com/Seagate/kmip-go"
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+)
+
+// RequestPayloadTypes maps operations to the types ResolveType decodes their
+// 2.0 request payloads into.
+var RequestPayloadTypes = map[Operation]reflect.Type{
+	OperationActivate:      reflect.TypeFor[*ActivateRequestPayload](),
+	OperationCreate:        reflect.TypeFor[*CreateRequestPayload](),
+	OperationCreateKeyPair: reflect.TypeFor[*CreateKeyPairRequestPayload](),
+	OperationDestroy:       reflect.TypeFor[*DestroyRequestPayload](),
+	OperationGet:           reflect.TypeFor[*GetRequestPayload](),
+	OperationGetAttributes: reflect.TypeFor[*GetAttributesRequestPayload](),
+	OperationLocate:        reflect.TypeFor[*LocateRequestPayload](),
+	OperationQuery:         reflect.TypeFor[*QueryRequestPayload](),
+	OperationRegister:      reflect.TypeFor[*RegisterRequestPayload](),
+	OperationReKey:         reflect.TypeFor[*ReKeyRequestPayload](),
+	OperationRevoke:        reflect.TypeFor[*RevokeRequestPayload](),
+	OperationSetAttribute:  reflect.TypeFor[*SetAttributeRequestPayload](),
+}
+
+// ResponsePayloadTypes maps operations to the types ResolveType decodes their
+// 2.0 response payloads into.
+var ResponsePayloadTypes = map[Operation]reflect.Type{
+	OperationActivate:      reflect.TypeFor[*ActivateResponsePayload](),
+	OperationCreate:        reflect.TypeFor[*CreateResponsePayload](),
+	OperationCreateKeyPair: reflect.TypeFor[*CreateKeyPairResponsePayload](),
+	OperationDestroy:       reflect.TypeFor[*DestroyResponsePayload](),
+	OperationGet:           reflect.TypeFor[*GetResponsePayload](),
+	OperationGetAttributes: reflect.TypeFor[*GetAttributesResponsePayload](),
+	OperationLocate:        reflect.TypeFor[*LocateResponsePayload](),
+	OperationQuery:         reflect.TypeFor[*QueryResponsePayload](),
+	OperationRegister:      reflect.TypeFor[*RegisterResponsePayload](),
+	OperationReKey:         reflect.TypeFor[*ReKeyResponsePayload](),
+	OperationRevoke:        reflect.TypeFor[*RevokeResponsePayload](),
+	OperationSetAttribute:  reflect.TypeFor[*SetAttributeResponsePayload](),
+}
+
+// ResolveType is a ttlv.TypeResolver for 2.0 messages.  It decodes request and
+// response payloads according to the batch item's Operation, using RequestPayloadTypes
+// and ResponsePayloadTypes.  The 2.0 attribute structures, like Attributes and
+// NewAttribute, which hold any attribute values, are decoded into a ttlv.Value,
+// whose Value is the ttlv.Values of the attributes.
+// Everything else is resolved by kmip.ResolveType.
+//
+//	dec := ttlv.NewDecoder(conn)
+//	dec.TypeResolver = kmip20.ResolveType
+func ResolveType(v ttlv.TTLV, parents []ttlv.TTLV) (reflect.Type, error) {
+	if len(parents) == 0 || v.Type() != ttlv.TypeStructure {
+		return nil, nil
+	}
+
+	switch v.Tag() {
+	case kmip14.TagRequestPayload, kmip14.TagResponsePayload:
+		types := RequestPayloadTypes
+		if v.Tag() == kmip14.TagResponsePayload {
+			types = ResponsePayloadTypes
+		}
+
+		for n := parents[len(parents)-1].ValueStructure(); n != nil; n = n.Next() {
+			if n.Tag() == kmip14.TagOperation && n.Type() == ttlv.TypeEnumeration {
+				return types[Operation(n.ValueEnumeration())], nil
+			}
+		}
+
+		return nil, nil
+	case TagAttributes, TagCommonAttributes, TagPrivateKeyAttributes, TagPublicKeyAttributes, TagNewAttribute:
+		return reflect.TypeFor[ttlv.Value](), nil
+	default:
+		return kmip.ResolveType(v, parents)
+	}
+}
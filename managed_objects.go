@@ -17,6 +17,7 @@ type Certificate struct {
 
 // 2.2.2
 
+//ttlvgen:generate
 type SymmetricKey struct {
 	KeyBlock KeyBlock
 }
@@ -0,0 +1,1507 @@
+// Code generated by ttlvgen; DO NOT EDIT.
+
+//go:build !ttlvreflect
+
+package kmip
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+)
+
+var ttlvKeyBlockFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[KeyBlock](), Name: "KeyFormatType", Tag: 0x420042, Flags: ""},                   // KeyFormatType
+	{Struct: reflect.TypeFor[KeyBlock](), Name: "KeyCompressionType", Tag: 0x420041, Flags: "omitempty"},     // KeyCompressionType
+	{Struct: reflect.TypeFor[KeyBlock](), Name: "KeyValue", Tag: 0x420045, Flags: "omitempty"},               // KeyValue
+	{Struct: reflect.TypeFor[KeyBlock](), Name: "CryptographicAlgorithm", Tag: 0x420028, Flags: "omitempty"}, // CryptographicAlgorithm
+	{Struct: reflect.TypeFor[KeyBlock](), Name: "CryptographicLength", Tag: 0x42002a, Flags: "omitempty"},    // CryptographicLength
+	{Struct: reflect.TypeFor[KeyBlock](), Name: "KeyWrappingData", Tag: 0x420046, Flags: ""},                 // KeyWrappingData
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*KeyBlock) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[KeyBlock]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v KeyBlock) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		tag = 0x420040 // KeyBlock
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := v.KeyFormatType.MarshalTTLV(e, 0x420042); err != nil { // KeyFormatType
+			return err
+		}
+
+		if v.KeyCompressionType != 0 {
+			if err := v.KeyCompressionType.MarshalTTLV(e, 0x420041); err != nil { // KeyCompressionType
+				return err
+			}
+		}
+
+		if v.KeyValue != nil {
+			if err := v.KeyValue.MarshalTTLV(e, 0x420045); err != nil { // KeyValue
+				return err
+			}
+		}
+
+		if v.CryptographicAlgorithm != 0 {
+			if err := v.CryptographicAlgorithm.MarshalTTLV(e, 0x420028); err != nil { // CryptographicAlgorithm
+				return err
+			}
+		}
+
+		if v.CryptographicLength != 0 {
+			if v.CryptographicLength > math.MaxInt32 {
+				return e.EncodeField(ttlvKeyBlockFields[4], v.CryptographicLength)
+			}
+
+			e.EncodeInteger(0x42002a, int32(v.CryptographicLength)) // CryptographicLength
+		}
+
+		if err := e.EncodeField(ttlvKeyBlockFields[5], v.KeyWrappingData); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *KeyBlock) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[KeyBlock](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[KeyBlock]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x420042: // KeyFormatType
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.KeyFormatType = kmip14.KeyFormatType(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvKeyBlockFields[0], &v.KeyFormatType, n); err != nil {
+					return err
+				}
+			}
+		case 0x420041: // KeyCompressionType
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.KeyCompressionType = kmip14.KeyCompressionType(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvKeyBlockFields[1], &v.KeyCompressionType, n); err != nil {
+					return err
+				}
+			}
+		case 0x420045: // KeyValue
+			if n.Type() != ttlv.TypeStructure {
+				if err := d.DecodeField(ttlvKeyBlockFields[2], &v.KeyValue, n); err != nil {
+					return err
+				}
+			} else {
+				if v.KeyValue == nil {
+					v.KeyValue = new(KeyValue)
+				}
+
+				if err := v.KeyValue.UnmarshalTTLV(d, n); err != nil {
+					return d.FieldError(ttlvKeyBlockFields[2], -1, err)
+				}
+			}
+		case 0x420028: // CryptographicAlgorithm
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.CryptographicAlgorithm = kmip14.CryptographicAlgorithm(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvKeyBlockFields[3], &v.CryptographicAlgorithm, n); err != nil {
+					return err
+				}
+			}
+		case 0x42002a: // CryptographicLength
+			switch n.Type() {
+			case ttlv.TypeInteger:
+				v.CryptographicLength = int(n.ValueInteger())
+			default:
+				if err := d.DecodeField(ttlvKeyBlockFields[4], &v.CryptographicLength, n); err != nil {
+					return err
+				}
+			}
+		case 0x420046: // KeyWrappingData
+			if err := d.DecodeField(ttlvKeyBlockFields[5], &v.KeyWrappingData, n); err != nil {
+				return err
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[KeyBlock](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvKeyValueFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[KeyValue](), Name: "KeyMaterial", Tag: 0x420043, Flags: ""}, // KeyMaterial
+	{Struct: reflect.TypeFor[KeyValue](), Name: "Attribute", Tag: 0x420008, Flags: ""},   // Attribute
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*KeyValue) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[KeyValue]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v KeyValue) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		tag = 0x420045 // KeyValue
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := e.EncodeField(ttlvKeyValueFields[0], v.KeyMaterial); err != nil {
+			return err
+		}
+
+		if err := e.EncodeField(ttlvKeyValueFields[1], &v.Attribute); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *KeyValue) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[KeyValue](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[KeyValue]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x420043: // KeyMaterial
+			if err := d.DecodeField(ttlvKeyValueFields[0], &v.KeyMaterial, n); err != nil {
+				return err
+			}
+		case 0x420008: // Attribute
+			if err := d.DecodeField(ttlvKeyValueFields[1], &v.Attribute, n); err != nil {
+				return err
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[KeyValue](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvSymmetricKeyFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[SymmetricKey](), Name: "KeyBlock", Tag: 0x420040, Flags: ""}, // KeyBlock
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*SymmetricKey) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[SymmetricKey]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v SymmetricKey) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		tag = 0x42008f // SymmetricKey
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := v.KeyBlock.MarshalTTLV(e, 0x420040); err != nil { // KeyBlock
+			return err
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *SymmetricKey) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[SymmetricKey](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[SymmetricKey]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x420040: // KeyBlock
+			if n.Type() != ttlv.TypeStructure {
+				if err := d.DecodeField(ttlvSymmetricKeyFields[0], &v.KeyBlock, n); err != nil {
+					return err
+				}
+			} else if err := v.KeyBlock.UnmarshalTTLV(d, n); err != nil {
+				return d.FieldError(ttlvSymmetricKeyFields[0], -1, err)
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[SymmetricKey](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvGetRequestPayloadFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[GetRequestPayload](), Name: "UniqueIdentifier", Tag: 0x420094, Flags: ""}, // UniqueIdentifier
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*GetRequestPayload) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[GetRequestPayload]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v GetRequestPayload) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		return e.MarshalingError(tag, reflect.TypeFor[GetRequestPayload](), ttlv.ErrNoTag)
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if e.EnumForTag(0x420094) != nil {
+			if err := e.EncodeField(ttlvGetRequestPayloadFields[0], &v.UniqueIdentifier); err != nil {
+				return err
+			}
+		} else {
+			e.EncodeTextString(0x420094, v.UniqueIdentifier) // UniqueIdentifier
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *GetRequestPayload) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[GetRequestPayload](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[GetRequestPayload]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x420094: // UniqueIdentifier
+			switch n.Type() {
+			case ttlv.TypeTextString:
+				v.UniqueIdentifier = n.ValueTextString()
+			default:
+				if err := d.DecodeField(ttlvGetRequestPayloadFields[0], &v.UniqueIdentifier, n); err != nil {
+					return err
+				}
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[GetRequestPayload](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvGetResponsePayloadFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[GetResponsePayload](), Name: "ObjectType", Tag: 0x420057, Flags: "required"},       // ObjectType
+	{Struct: reflect.TypeFor[GetResponsePayload](), Name: "UniqueIdentifier", Tag: 0x420094, Flags: "required"}, // UniqueIdentifier
+	{Struct: reflect.TypeFor[GetResponsePayload](), Name: "Certificate", Tag: 0x420013, Flags: ""},              // Certificate
+	{Struct: reflect.TypeFor[GetResponsePayload](), Name: "SymmetricKey", Tag: 0x42008f, Flags: ""},             // SymmetricKey
+	{Struct: reflect.TypeFor[GetResponsePayload](), Name: "PrivateKey", Tag: 0x420064, Flags: ""},               // PrivateKey
+	{Struct: reflect.TypeFor[GetResponsePayload](), Name: "PublicKey", Tag: 0x42006d, Flags: ""},                // PublicKey
+	{Struct: reflect.TypeFor[GetResponsePayload](), Name: "SplitKey", Tag: 0x420089, Flags: ""},                 // SplitKey
+	{Struct: reflect.TypeFor[GetResponsePayload](), Name: "Template", Tag: 0x420090, Flags: ""},                 // Template
+	{Struct: reflect.TypeFor[GetResponsePayload](), Name: "SecretData", Tag: 0x420085, Flags: ""},               // SecretData
+	{Struct: reflect.TypeFor[GetResponsePayload](), Name: "OpaqueObject", Tag: 0x42005b, Flags: ""},             // OpaqueObject
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*GetResponsePayload) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[GetResponsePayload]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v GetResponsePayload) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		return e.MarshalingError(tag, reflect.TypeFor[GetResponsePayload](), ttlv.ErrNoTag)
+	}
+
+	if v.ObjectType == 0 {
+		return ttlv.NewValidationError(reflect.TypeFor[GetResponsePayload](), "ObjectType", 0x420057, ttlv.ErrMissingValue) // ObjectType
+	}
+
+	if len(v.UniqueIdentifier) == 0 {
+		return ttlv.NewValidationError(reflect.TypeFor[GetResponsePayload](), "UniqueIdentifier", 0x420094, ttlv.ErrMissingValue) // UniqueIdentifier
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := v.ObjectType.MarshalTTLV(e, 0x420057); err != nil { // ObjectType
+			return err
+		}
+
+		if e.EnumForTag(0x420094) != nil {
+			if err := e.EncodeField(ttlvGetResponsePayloadFields[1], &v.UniqueIdentifier); err != nil {
+				return err
+			}
+		} else {
+			e.EncodeTextString(0x420094, v.UniqueIdentifier) // UniqueIdentifier
+		}
+
+		if err := e.EncodeField(ttlvGetResponsePayloadFields[2], v.Certificate); err != nil {
+			return err
+		}
+
+		if v.SymmetricKey != nil {
+			if err := v.SymmetricKey.MarshalTTLV(e, 0x42008f); err != nil { // SymmetricKey
+				return err
+			}
+		}
+
+		if err := e.EncodeField(ttlvGetResponsePayloadFields[4], v.PrivateKey); err != nil {
+			return err
+		}
+
+		if err := e.EncodeField(ttlvGetResponsePayloadFields[5], v.PublicKey); err != nil {
+			return err
+		}
+
+		if err := e.EncodeField(ttlvGetResponsePayloadFields[6], v.SplitKey); err != nil {
+			return err
+		}
+
+		if err := e.EncodeField(ttlvGetResponsePayloadFields[7], v.Template); err != nil {
+			return err
+		}
+
+		if err := e.EncodeField(ttlvGetResponsePayloadFields[8], v.SecretData); err != nil {
+			return err
+		}
+
+		if err := e.EncodeField(ttlvGetResponsePayloadFields[9], v.OpaqueObject); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *GetResponsePayload) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[GetResponsePayload](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[GetResponsePayload]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	var present [10]bool
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x420057: // ObjectType
+			present[0] = true
+
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.ObjectType = kmip14.ObjectType(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvGetResponsePayloadFields[0], &v.ObjectType, n); err != nil {
+					return err
+				}
+			}
+		case 0x420094: // UniqueIdentifier
+			present[1] = true
+
+			switch n.Type() {
+			case ttlv.TypeTextString:
+				v.UniqueIdentifier = n.ValueTextString()
+			default:
+				if err := d.DecodeField(ttlvGetResponsePayloadFields[1], &v.UniqueIdentifier, n); err != nil {
+					return err
+				}
+			}
+		case 0x420013: // Certificate
+			present[2] = true
+
+			if err := d.DecodeField(ttlvGetResponsePayloadFields[2], &v.Certificate, n); err != nil {
+				return err
+			}
+		case 0x42008f: // SymmetricKey
+			present[3] = true
+
+			if n.Type() != ttlv.TypeStructure {
+				if err := d.DecodeField(ttlvGetResponsePayloadFields[3], &v.SymmetricKey, n); err != nil {
+					return err
+				}
+			} else {
+				if v.SymmetricKey == nil {
+					v.SymmetricKey = new(SymmetricKey)
+				}
+
+				if err := v.SymmetricKey.UnmarshalTTLV(d, n); err != nil {
+					return d.FieldError(ttlvGetResponsePayloadFields[3], -1, err)
+				}
+			}
+		case 0x420064: // PrivateKey
+			present[4] = true
+
+			if err := d.DecodeField(ttlvGetResponsePayloadFields[4], &v.PrivateKey, n); err != nil {
+				return err
+			}
+		case 0x42006d: // PublicKey
+			present[5] = true
+
+			if err := d.DecodeField(ttlvGetResponsePayloadFields[5], &v.PublicKey, n); err != nil {
+				return err
+			}
+		case 0x420089: // SplitKey
+			present[6] = true
+
+			if err := d.DecodeField(ttlvGetResponsePayloadFields[6], &v.SplitKey, n); err != nil {
+				return err
+			}
+		case 0x420090: // Template
+			present[7] = true
+
+			if err := d.DecodeField(ttlvGetResponsePayloadFields[7], &v.Template, n); err != nil {
+				return err
+			}
+		case 0x420085: // SecretData
+			present[8] = true
+
+			if err := d.DecodeField(ttlvGetResponsePayloadFields[8], &v.SecretData, n); err != nil {
+				return err
+			}
+		case 0x42005b: // OpaqueObject
+			present[9] = true
+
+			if err := d.DecodeField(ttlvGetResponsePayloadFields[9], &v.OpaqueObject, n); err != nil {
+				return err
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[GetResponsePayload](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	if !present[0] {
+		return ttlv.NewValidationError(reflect.TypeFor[GetResponsePayload](), "ObjectType", 0x420057, ttlv.ErrMissingValue) // ObjectType
+	}
+
+	if !present[1] {
+		return ttlv.NewValidationError(reflect.TypeFor[GetResponsePayload](), "UniqueIdentifier", 0x420094, ttlv.ErrMissingValue) // UniqueIdentifier
+	}
+
+	return nil
+}
+
+var ttlvProtocolVersionFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[ProtocolVersion](), Name: "ProtocolVersionMajor", Tag: 0x42006a, Flags: ""}, // ProtocolVersionMajor
+	{Struct: reflect.TypeFor[ProtocolVersion](), Name: "ProtocolVersionMinor", Tag: 0x42006b, Flags: ""}, // ProtocolVersionMinor
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*ProtocolVersion) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[ProtocolVersion]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v ProtocolVersion) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		tag = 0x420069 // ProtocolVersion
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if v.ProtocolVersionMajor > math.MaxInt32 {
+			return e.EncodeField(ttlvProtocolVersionFields[0], v.ProtocolVersionMajor)
+		}
+
+		e.EncodeInteger(0x42006a, int32(v.ProtocolVersionMajor)) // ProtocolVersionMajor
+
+		if v.ProtocolVersionMinor > math.MaxInt32 {
+			return e.EncodeField(ttlvProtocolVersionFields[1], v.ProtocolVersionMinor)
+		}
+
+		e.EncodeInteger(0x42006b, int32(v.ProtocolVersionMinor)) // ProtocolVersionMinor
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *ProtocolVersion) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[ProtocolVersion](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[ProtocolVersion]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x42006a: // ProtocolVersionMajor
+			switch n.Type() {
+			case ttlv.TypeInteger:
+				v.ProtocolVersionMajor = int(n.ValueInteger())
+			default:
+				if err := d.DecodeField(ttlvProtocolVersionFields[0], &v.ProtocolVersionMajor, n); err != nil {
+					return err
+				}
+			}
+		case 0x42006b: // ProtocolVersionMinor
+			switch n.Type() {
+			case ttlv.TypeInteger:
+				v.ProtocolVersionMinor = int(n.ValueInteger())
+			default:
+				if err := d.DecodeField(ttlvProtocolVersionFields[1], &v.ProtocolVersionMinor, n); err != nil {
+					return err
+				}
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[ProtocolVersion](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvRequestMessageFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[RequestMessage](), Name: "RequestHeader", Tag: 0x420077, Flags: ""}, // RequestHeader
+	{Struct: reflect.TypeFor[RequestMessage](), Name: "BatchItem", Tag: 0x42000f, Flags: ""},     // BatchItem
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*RequestMessage) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[RequestMessage]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v RequestMessage) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		tag = 0x420078 // RequestMessage
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := v.RequestHeader.MarshalTTLV(e, 0x420077); err != nil { // RequestHeader
+			return err
+		}
+
+		for i := range v.BatchItem {
+			if err := v.BatchItem[i].MarshalTTLV(e, 0x42000f); err != nil { // BatchItem
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *RequestMessage) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[RequestMessage](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[RequestMessage]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x420077: // RequestHeader
+			if n.Type() != ttlv.TypeStructure {
+				if err := d.DecodeField(ttlvRequestMessageFields[0], &v.RequestHeader, n); err != nil {
+					return err
+				}
+			} else if err := v.RequestHeader.UnmarshalTTLV(d, n); err != nil {
+				return d.FieldError(ttlvRequestMessageFields[0], -1, err)
+			}
+		case 0x42000f: // BatchItem
+			if n.Type() != ttlv.TypeStructure {
+				if err := d.DecodeField(ttlvRequestMessageFields[1], &v.BatchItem, n); err != nil {
+					return err
+				}
+			} else {
+				v.BatchItem = append(v.BatchItem, RequestBatchItem{})
+
+				if err := v.BatchItem[len(v.BatchItem)-1].UnmarshalTTLV(d, n); err != nil {
+					v.BatchItem = v.BatchItem[:len(v.BatchItem)-1]
+					return d.FieldError(ttlvRequestMessageFields[1], len(v.BatchItem), err)
+				}
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[RequestMessage](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvResponseMessageFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[ResponseMessage](), Name: "ResponseHeader", Tag: 0x42007a, Flags: ""}, // ResponseHeader
+	{Struct: reflect.TypeFor[ResponseMessage](), Name: "BatchItem", Tag: 0x42000f, Flags: ""},      // BatchItem
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*ResponseMessage) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[ResponseMessage]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v ResponseMessage) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		tag = 0x42007b // ResponseMessage
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := v.ResponseHeader.MarshalTTLV(e, 0x42007a); err != nil { // ResponseHeader
+			return err
+		}
+
+		for i := range v.BatchItem {
+			if err := v.BatchItem[i].MarshalTTLV(e, 0x42000f); err != nil { // BatchItem
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *ResponseMessage) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[ResponseMessage](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[ResponseMessage]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x42007a: // ResponseHeader
+			if n.Type() != ttlv.TypeStructure {
+				if err := d.DecodeField(ttlvResponseMessageFields[0], &v.ResponseHeader, n); err != nil {
+					return err
+				}
+			} else if err := v.ResponseHeader.UnmarshalTTLV(d, n); err != nil {
+				return d.FieldError(ttlvResponseMessageFields[0], -1, err)
+			}
+		case 0x42000f: // BatchItem
+			if n.Type() != ttlv.TypeStructure {
+				if err := d.DecodeField(ttlvResponseMessageFields[1], &v.BatchItem, n); err != nil {
+					return err
+				}
+			} else {
+				v.BatchItem = append(v.BatchItem, ResponseBatchItem{})
+
+				if err := v.BatchItem[len(v.BatchItem)-1].UnmarshalTTLV(d, n); err != nil {
+					v.BatchItem = v.BatchItem[:len(v.BatchItem)-1]
+					return d.FieldError(ttlvResponseMessageFields[1], len(v.BatchItem), err)
+				}
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[ResponseMessage](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvRequestHeaderFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "ProtocolVersion", Tag: 0x420069, Flags: ""},                       // ProtocolVersion
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "MaximumResponseSize", Tag: 0x420050, Flags: "omitempty"},          // MaximumResponseSize
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "ClientCorrelationValue", Tag: 0x420105, Flags: "omitempty"},       // ClientCorrelationValue
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "ServerCorrelationValue", Tag: 0x420106, Flags: "omitempty"},       // ServerCorrelationValue
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "AsynchronousIndicator", Tag: 0x420007, Flags: "omitempty"},        // AsynchronousIndicator
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "AttestationCapableIndicator", Tag: 0x4200d3, Flags: "omitempty"},  // AttestationCapableIndicator
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "AttestationType", Tag: 0x4200c7, Flags: ""},                       // AttestationType
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "Authentication", Tag: 0x42000c, Flags: ""},                        // Authentication
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "BatchErrorContinuationOption", Tag: 0x42000e, Flags: "omitempty"}, // BatchErrorContinuationOption
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "BatchOrderOption", Tag: 0x420010, Flags: "omitempty"},             // BatchOrderOption
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "TimeStamp", Tag: 0x420092, Flags: ""},                             // TimeStamp
+	{Struct: reflect.TypeFor[RequestHeader](), Name: "BatchCount", Tag: 0x42000d, Flags: ""},                            // BatchCount
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*RequestHeader) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[RequestHeader]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v RequestHeader) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		tag = 0x420077 // RequestHeader
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := v.ProtocolVersion.MarshalTTLV(e, 0x420069); err != nil { // ProtocolVersion
+			return err
+		}
+
+		if v.MaximumResponseSize != 0 {
+			if v.MaximumResponseSize > math.MaxInt32 {
+				return e.EncodeField(ttlvRequestHeaderFields[1], v.MaximumResponseSize)
+			}
+
+			e.EncodeInteger(0x420050, int32(v.MaximumResponseSize)) // MaximumResponseSize
+		}
+
+		if len(v.ClientCorrelationValue) != 0 {
+			e.EncodeTextString(0x420105, v.ClientCorrelationValue) // ClientCorrelationValue
+		}
+
+		if len(v.ServerCorrelationValue) != 0 {
+			e.EncodeTextString(0x420106, v.ServerCorrelationValue) // ServerCorrelationValue
+		}
+
+		if v.AsynchronousIndicator {
+			e.EncodeBoolean(0x420007, v.AsynchronousIndicator) // AsynchronousIndicator
+		}
+
+		if v.AttestationCapableIndicator {
+			e.EncodeBoolean(0x4200d3, v.AttestationCapableIndicator) // AttestationCapableIndicator
+		}
+
+		for i := range v.AttestationType {
+			if err := v.AttestationType[i].MarshalTTLV(e, 0x4200c7); err != nil { // AttestationType
+				return err
+			}
+		}
+
+		if err := e.EncodeField(ttlvRequestHeaderFields[7], v.Authentication); err != nil {
+			return err
+		}
+
+		if v.BatchErrorContinuationOption != 0 {
+			if err := v.BatchErrorContinuationOption.MarshalTTLV(e, 0x42000e); err != nil { // BatchErrorContinuationOption
+				return err
+			}
+		}
+
+		if v.BatchOrderOption {
+			e.EncodeBoolean(0x420010, v.BatchOrderOption) // BatchOrderOption
+		}
+
+		if v.TimeStamp != nil {
+			e.EncodeDateTime(0x420092, (*v.TimeStamp)) // TimeStamp
+		}
+
+		if v.BatchCount > math.MaxInt32 {
+			return e.EncodeField(ttlvRequestHeaderFields[11], v.BatchCount)
+		}
+
+		e.EncodeInteger(0x42000d, int32(v.BatchCount)) // BatchCount
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *RequestHeader) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[RequestHeader](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[RequestHeader]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x420069: // ProtocolVersion
+			if n.Type() != ttlv.TypeStructure {
+				if err := d.DecodeField(ttlvRequestHeaderFields[0], &v.ProtocolVersion, n); err != nil {
+					return err
+				}
+			} else if err := v.ProtocolVersion.UnmarshalTTLV(d, n); err != nil {
+				return d.FieldError(ttlvRequestHeaderFields[0], -1, err)
+			}
+		case 0x420050: // MaximumResponseSize
+			switch n.Type() {
+			case ttlv.TypeInteger:
+				v.MaximumResponseSize = int(n.ValueInteger())
+			default:
+				if err := d.DecodeField(ttlvRequestHeaderFields[1], &v.MaximumResponseSize, n); err != nil {
+					return err
+				}
+			}
+		case 0x420105: // ClientCorrelationValue
+			switch n.Type() {
+			case ttlv.TypeTextString:
+				v.ClientCorrelationValue = n.ValueTextString()
+			default:
+				if err := d.DecodeField(ttlvRequestHeaderFields[2], &v.ClientCorrelationValue, n); err != nil {
+					return err
+				}
+			}
+		case 0x420106: // ServerCorrelationValue
+			switch n.Type() {
+			case ttlv.TypeTextString:
+				v.ServerCorrelationValue = n.ValueTextString()
+			default:
+				if err := d.DecodeField(ttlvRequestHeaderFields[3], &v.ServerCorrelationValue, n); err != nil {
+					return err
+				}
+			}
+		case 0x420007: // AsynchronousIndicator
+			switch n.Type() {
+			case ttlv.TypeBoolean:
+				v.AsynchronousIndicator = n.ValueBoolean()
+			default:
+				if err := d.DecodeField(ttlvRequestHeaderFields[4], &v.AsynchronousIndicator, n); err != nil {
+					return err
+				}
+			}
+		case 0x4200d3: // AttestationCapableIndicator
+			switch n.Type() {
+			case ttlv.TypeBoolean:
+				v.AttestationCapableIndicator = n.ValueBoolean()
+			default:
+				if err := d.DecodeField(ttlvRequestHeaderFields[5], &v.AttestationCapableIndicator, n); err != nil {
+					return err
+				}
+			}
+		case 0x4200c7: // AttestationType
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.AttestationType = append(v.AttestationType, kmip14.AttestationType(n.ValueEnumeration()))
+			default:
+				if err := d.DecodeField(ttlvRequestHeaderFields[6], &v.AttestationType, n); err != nil {
+					return err
+				}
+			}
+		case 0x42000c: // Authentication
+			if err := d.DecodeField(ttlvRequestHeaderFields[7], &v.Authentication, n); err != nil {
+				return err
+			}
+		case 0x42000e: // BatchErrorContinuationOption
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.BatchErrorContinuationOption = kmip14.BatchErrorContinuationOption(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvRequestHeaderFields[8], &v.BatchErrorContinuationOption, n); err != nil {
+					return err
+				}
+			}
+		case 0x420010: // BatchOrderOption
+			switch n.Type() {
+			case ttlv.TypeBoolean:
+				v.BatchOrderOption = n.ValueBoolean()
+			default:
+				if err := d.DecodeField(ttlvRequestHeaderFields[9], &v.BatchOrderOption, n); err != nil {
+					return err
+				}
+			}
+		case 0x420092: // TimeStamp
+			if err := d.DecodeField(ttlvRequestHeaderFields[10], &v.TimeStamp, n); err != nil {
+				return err
+			}
+		case 0x42000d: // BatchCount
+			switch n.Type() {
+			case ttlv.TypeInteger:
+				v.BatchCount = int(n.ValueInteger())
+			default:
+				if err := d.DecodeField(ttlvRequestHeaderFields[11], &v.BatchCount, n); err != nil {
+					return err
+				}
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[RequestHeader](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvRequestBatchItemFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[RequestBatchItem](), Name: "Operation", Tag: 0x42005c, Flags: ""},                  // Operation
+	{Struct: reflect.TypeFor[RequestBatchItem](), Name: "UniqueBatchItemID", Tag: 0x420093, Flags: "omitempty"}, // UniqueBatchItemID
+	{Struct: reflect.TypeFor[RequestBatchItem](), Name: "RequestPayload", Tag: 0x420079, Flags: ""},             // RequestPayload
+	{Struct: reflect.TypeFor[RequestBatchItem](), Name: "MessageExtension", Tag: 0x420051, Flags: "omitempty"},  // MessageExtension
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*RequestBatchItem) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[RequestBatchItem]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v RequestBatchItem) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		return e.MarshalingError(tag, reflect.TypeFor[RequestBatchItem](), ttlv.ErrNoTag)
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := v.Operation.MarshalTTLV(e, 0x42005c); err != nil { // Operation
+			return err
+		}
+
+		if v.UniqueBatchItemID != nil && len(v.UniqueBatchItemID) != 0 {
+			e.EncodeByteString(0x420093, v.UniqueBatchItemID) // UniqueBatchItemID
+		}
+
+		if err := e.EncodeField(ttlvRequestBatchItemFields[2], v.RequestPayload); err != nil {
+			return err
+		}
+
+		if err := e.EncodeField(ttlvRequestBatchItemFields[3], v.MessageExtension); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *RequestBatchItem) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[RequestBatchItem](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[RequestBatchItem]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x42005c: // Operation
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.Operation = kmip14.Operation(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvRequestBatchItemFields[0], &v.Operation, n); err != nil {
+					return err
+				}
+			}
+		case 0x420093: // UniqueBatchItemID
+			switch n.Type() {
+			case ttlv.TypeByteString:
+				v.UniqueBatchItemID = n.ValueByteString()
+			default:
+				if err := d.DecodeField(ttlvRequestBatchItemFields[1], &v.UniqueBatchItemID, n); err != nil {
+					return err
+				}
+			}
+		case 0x420079: // RequestPayload
+			if err := d.DecodeField(ttlvRequestBatchItemFields[2], &v.RequestPayload, n); err != nil {
+				return err
+			}
+		case 0x420051: // MessageExtension
+			if err := d.DecodeField(ttlvRequestBatchItemFields[3], &v.MessageExtension, n); err != nil {
+				return err
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[RequestBatchItem](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvResponseHeaderFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[ResponseHeader](), Name: "ProtocolVersion", Tag: 0x420069, Flags: ""},                 // ProtocolVersion
+	{Struct: reflect.TypeFor[ResponseHeader](), Name: "TimeStamp", Tag: 0x420092, Flags: ""},                       // TimeStamp
+	{Struct: reflect.TypeFor[ResponseHeader](), Name: "Nonce", Tag: 0x4200c8, Flags: ""},                           // Nonce
+	{Struct: reflect.TypeFor[ResponseHeader](), Name: "AttestationType", Tag: 0x4200c7, Flags: ""},                 // AttestationType
+	{Struct: reflect.TypeFor[ResponseHeader](), Name: "ClientCorrelationValue", Tag: 0x420105, Flags: "omitempty"}, // ClientCorrelationValue
+	{Struct: reflect.TypeFor[ResponseHeader](), Name: "ServerCorrelationValue", Tag: 0x420106, Flags: "omitempty"}, // ServerCorrelationValue
+	{Struct: reflect.TypeFor[ResponseHeader](), Name: "BatchCount", Tag: 0x42000d, Flags: ""},                      // BatchCount
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*ResponseHeader) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[ResponseHeader]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v ResponseHeader) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		tag = 0x42007a // ResponseHeader
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if err := v.ProtocolVersion.MarshalTTLV(e, 0x420069); err != nil { // ProtocolVersion
+			return err
+		}
+
+		e.EncodeDateTime(0x420092, v.TimeStamp) // TimeStamp
+
+		if err := e.EncodeField(ttlvResponseHeaderFields[2], v.Nonce); err != nil {
+			return err
+		}
+
+		for i := range v.AttestationType {
+			if err := v.AttestationType[i].MarshalTTLV(e, 0x4200c7); err != nil { // AttestationType
+				return err
+			}
+		}
+
+		if len(v.ClientCorrelationValue) != 0 {
+			e.EncodeTextString(0x420105, v.ClientCorrelationValue) // ClientCorrelationValue
+		}
+
+		if len(v.ServerCorrelationValue) != 0 {
+			e.EncodeTextString(0x420106, v.ServerCorrelationValue) // ServerCorrelationValue
+		}
+
+		if v.BatchCount > math.MaxInt32 {
+			return e.EncodeField(ttlvResponseHeaderFields[6], v.BatchCount)
+		}
+
+		e.EncodeInteger(0x42000d, int32(v.BatchCount)) // BatchCount
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *ResponseHeader) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[ResponseHeader](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[ResponseHeader]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x420069: // ProtocolVersion
+			if n.Type() != ttlv.TypeStructure {
+				if err := d.DecodeField(ttlvResponseHeaderFields[0], &v.ProtocolVersion, n); err != nil {
+					return err
+				}
+			} else if err := v.ProtocolVersion.UnmarshalTTLV(d, n); err != nil {
+				return d.FieldError(ttlvResponseHeaderFields[0], -1, err)
+			}
+		case 0x420092: // TimeStamp
+			switch n.Type() {
+			case ttlv.TypeDateTime:
+				v.TimeStamp = n.ValueDateTime()
+			case ttlv.TypeDateTimeExtended:
+				v.TimeStamp = n.ValueDateTime()
+			default:
+				if err := d.DecodeField(ttlvResponseHeaderFields[1], &v.TimeStamp, n); err != nil {
+					return err
+				}
+			}
+		case 0x4200c8: // Nonce
+			if err := d.DecodeField(ttlvResponseHeaderFields[2], &v.Nonce, n); err != nil {
+				return err
+			}
+		case 0x4200c7: // AttestationType
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.AttestationType = append(v.AttestationType, kmip14.AttestationType(n.ValueEnumeration()))
+			default:
+				if err := d.DecodeField(ttlvResponseHeaderFields[3], &v.AttestationType, n); err != nil {
+					return err
+				}
+			}
+		case 0x420105: // ClientCorrelationValue
+			switch n.Type() {
+			case ttlv.TypeTextString:
+				v.ClientCorrelationValue = n.ValueTextString()
+			default:
+				if err := d.DecodeField(ttlvResponseHeaderFields[4], &v.ClientCorrelationValue, n); err != nil {
+					return err
+				}
+			}
+		case 0x420106: // ServerCorrelationValue
+			switch n.Type() {
+			case ttlv.TypeTextString:
+				v.ServerCorrelationValue = n.ValueTextString()
+			default:
+				if err := d.DecodeField(ttlvResponseHeaderFields[5], &v.ServerCorrelationValue, n); err != nil {
+					return err
+				}
+			}
+		case 0x42000d: // BatchCount
+			switch n.Type() {
+			case ttlv.TypeInteger:
+				v.BatchCount = int(n.ValueInteger())
+			default:
+				if err := d.DecodeField(ttlvResponseHeaderFields[6], &v.BatchCount, n); err != nil {
+					return err
+				}
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[ResponseHeader](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+var ttlvResponseBatchItemFields = [...]ttlv.Field{
+	{Struct: reflect.TypeFor[ResponseBatchItem](), Name: "Operation", Tag: 0x42005c, Flags: "omitempty"},                    // Operation
+	{Struct: reflect.TypeFor[ResponseBatchItem](), Name: "UniqueBatchItemID", Tag: 0x420093, Flags: "omitempty"},            // UniqueBatchItemID
+	{Struct: reflect.TypeFor[ResponseBatchItem](), Name: "ResultStatus", Tag: 0x42007f, Flags: ""},                          // ResultStatus
+	{Struct: reflect.TypeFor[ResponseBatchItem](), Name: "ResultReason", Tag: 0x42007e, Flags: "omitempty"},                 // ResultReason
+	{Struct: reflect.TypeFor[ResponseBatchItem](), Name: "ResultMessage", Tag: 0x42007d, Flags: "omitempty"},                // ResultMessage
+	{Struct: reflect.TypeFor[ResponseBatchItem](), Name: "AsynchronousCorrelationValue", Tag: 0x420006, Flags: "omitempty"}, // AsynchronousCorrelationValue
+	{Struct: reflect.TypeFor[ResponseBatchItem](), Name: "ResponsePayload", Tag: 0x42007c, Flags: "omitempty"},              // ResponsePayload
+	{Struct: reflect.TypeFor[ResponseBatchItem](), Name: "MessageExtension", Tag: 0x420051, Flags: ""},                      // MessageExtension
+}
+
+// TTLVGenerated returns the type ttlvgen generated MarshalTTLV and UnmarshalTTLV for.
+func (*ResponseBatchItem) TTLVGenerated() reflect.Type {
+	return reflect.TypeFor[ResponseBatchItem]()
+}
+
+// MarshalTTLV implements ttlv.Marshaler.
+func (v ResponseBatchItem) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	if e.Registry != nil && e.Registry != &ttlv.DefaultRegistry {
+		// the generated code encodes the tags of ttlv.DefaultRegistry
+		return e.EncodeValue(tag, v)
+	}
+
+	if tag == ttlv.TagNone {
+		return e.MarshalingError(tag, reflect.TypeFor[ResponseBatchItem](), ttlv.ErrNoTag)
+	}
+
+	return e.EncodeStructure(tag, func(e *ttlv.Encoder) error {
+		if v.Operation != 0 {
+			if err := v.Operation.MarshalTTLV(e, 0x42005c); err != nil { // Operation
+				return err
+			}
+		}
+
+		if v.UniqueBatchItemID != nil && len(v.UniqueBatchItemID) != 0 {
+			e.EncodeByteString(0x420093, v.UniqueBatchItemID) // UniqueBatchItemID
+		}
+
+		if err := v.ResultStatus.MarshalTTLV(e, 0x42007f); err != nil { // ResultStatus
+			return err
+		}
+
+		if v.ResultReason != 0 {
+			if err := v.ResultReason.MarshalTTLV(e, 0x42007e); err != nil { // ResultReason
+				return err
+			}
+		}
+
+		if len(v.ResultMessage) != 0 {
+			e.EncodeTextString(0x42007d, v.ResultMessage) // ResultMessage
+		}
+
+		if v.AsynchronousCorrelationValue != nil && len(v.AsynchronousCorrelationValue) != 0 {
+			e.EncodeByteString(0x420006, v.AsynchronousCorrelationValue) // AsynchronousCorrelationValue
+		}
+
+		if err := e.EncodeField(ttlvResponseBatchItemFields[6], v.ResponsePayload); err != nil {
+			return err
+		}
+
+		if err := e.EncodeField(ttlvResponseBatchItemFields[7], v.MessageExtension); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// UnmarshalTTLV implements ttlv.Unmarshaler.
+func (v *ResponseBatchItem) UnmarshalTTLV(d *ttlv.Decoder, t ttlv.TTLV) error {
+	if d.Registry != nil && d.Registry != &ttlv.DefaultRegistry {
+		// the generated code decodes the tags of ttlv.DefaultRegistry
+		return d.DecodeValue(v, t)
+	}
+
+	if len(t) == 0 {
+		return nil
+	}
+
+	if t.Type() != ttlv.TypeStructure {
+		return d.UnmarshalingError(t, reflect.TypeFor[ResponseBatchItem](), ttlv.ErrUnsupportedTypeError)
+	}
+
+	if err := d.EnterStructure(t, reflect.TypeFor[ResponseBatchItem]()); err != nil {
+		return err
+	}
+
+	defer d.LeaveStructure()
+
+	for n := t.ValueStructure(); n != nil; n = n.Next() {
+		switch n.Tag() {
+		case 0x42005c: // Operation
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.Operation = kmip14.Operation(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvResponseBatchItemFields[0], &v.Operation, n); err != nil {
+					return err
+				}
+			}
+		case 0x420093: // UniqueBatchItemID
+			switch n.Type() {
+			case ttlv.TypeByteString:
+				v.UniqueBatchItemID = n.ValueByteString()
+			default:
+				if err := d.DecodeField(ttlvResponseBatchItemFields[1], &v.UniqueBatchItemID, n); err != nil {
+					return err
+				}
+			}
+		case 0x42007f: // ResultStatus
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.ResultStatus = kmip14.ResultStatus(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvResponseBatchItemFields[2], &v.ResultStatus, n); err != nil {
+					return err
+				}
+			}
+		case 0x42007e: // ResultReason
+			switch n.Type() {
+			case ttlv.TypeEnumeration:
+				v.ResultReason = kmip14.ResultReason(n.ValueEnumeration())
+			default:
+				if err := d.DecodeField(ttlvResponseBatchItemFields[3], &v.ResultReason, n); err != nil {
+					return err
+				}
+			}
+		case 0x42007d: // ResultMessage
+			switch n.Type() {
+			case ttlv.TypeTextString:
+				v.ResultMessage = n.ValueTextString()
+			default:
+				if err := d.DecodeField(ttlvResponseBatchItemFields[4], &v.ResultMessage, n); err != nil {
+					return err
+				}
+			}
+		case 0x420006: // AsynchronousCorrelationValue
+			switch n.Type() {
+			case ttlv.TypeByteString:
+				v.AsynchronousCorrelationValue = n.ValueByteString()
+			default:
+				if err := d.DecodeField(ttlvResponseBatchItemFields[5], &v.AsynchronousCorrelationValue, n); err != nil {
+					return err
+				}
+			}
+		case 0x42007c: // ResponsePayload
+			if err := d.DecodeField(ttlvResponseBatchItemFields[6], &v.ResponsePayload, n); err != nil {
+				return err
+			}
+		case 0x420051: // MessageExtension
+			if err := d.DecodeField(ttlvResponseBatchItemFields[7], &v.MessageExtension, n); err != nil {
+				return err
+			}
+		default:
+			if d.DisallowExtraValues {
+				return d.UnmarshalingError(t, reflect.TypeFor[ResponseBatchItem](), ttlv.ErrUnexpectedValue)
+			}
+		}
+	}
+
+	return nil
+}
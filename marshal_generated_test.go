@@ -0,0 +1,376 @@
+package kmip
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The expected encodings in these tests were produced by the reflection based encoder.
+// The tests run against the generated marshalers by default, and against the reflection
+// based encoder when built with the ttlvreflect tag, so they verify both produce the same
+// bytes.
+
+func testRequestMessage() RequestMessage {
+	return RequestMessage{
+		RequestHeader: RequestHeader{
+			ProtocolVersion: ProtocolVersion{
+				ProtocolVersionMajor: 1,
+				ProtocolVersionMinor: 4,
+			},
+			MaximumResponseSize: 4096,
+			BatchCount:          1,
+		},
+		BatchItem: []RequestBatchItem{
+			{
+				Operation:         kmip14.OperationGet,
+				UniqueBatchItemID: []byte{0x01, 0x02, 0x03, 0x04},
+				RequestPayload: GetRequestPayload{
+					UniqueIdentifier: "49a1ca88-6bea-4fb2-b450-7e58802c3038",
+				},
+			},
+		},
+	}
+}
+
+func testGetResponsePayload() GetResponsePayload {
+	return GetResponsePayload{
+		ObjectType:       kmip14.ObjectTypeSymmetricKey,
+		UniqueIdentifier: "49a1ca88-6bea-4fb2-b450-7e58802c3038",
+		SymmetricKey: &SymmetricKey{
+			KeyBlock: KeyBlock{
+				KeyFormatType: kmip14.KeyFormatTypeRaw,
+				KeyValue: &KeyValue{
+					KeyMaterial: []byte{0x73, 0x67, 0x57, 0x80, 0x51, 0x01, 0x2a, 0x6d, 0x13, 0x4a, 0x85, 0x5e, 0x25, 0xc8, 0xcd, 0x5e},
+				},
+				CryptographicAlgorithm: kmip14.CryptographicAlgorithmAES,
+				CryptographicLength:    128,
+			},
+		},
+	}
+}
+
+func testResponseMessage() ResponseMessage {
+	return ResponseMessage{
+		ResponseHeader: ResponseHeader{
+			ProtocolVersion: ProtocolVersion{
+				ProtocolVersionMajor: 1,
+				ProtocolVersionMinor: 4,
+			},
+			TimeStamp:  time.Date(2021, 4, 12, 15, 30, 0, 0, time.UTC),
+			BatchCount: 1,
+		},
+		BatchItem: []ResponseBatchItem{
+			{
+				Operation:         kmip14.OperationGet,
+				UniqueBatchItemID: []byte{0x01, 0x02, 0x03, 0x04},
+				ResultStatus:      kmip14.ResultStatusSuccess,
+				ResponsePayload:   testGetResponsePayload(),
+			},
+		},
+	}
+}
+
+// marshal encodes v with tag, so values without an intrinsic tag, like payloads, can
+// be encoded on their own.
+func marshal(tag ttlv.Tag, v interface{}) (ttlv.TTLV, error) {
+	var buf bytes.Buffer
+
+	if err := ttlv.NewEncoder(&buf).EncodeValue(tag, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func TestGeneratedMarshalers(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      ttlv.Tag
+		in       interface{}
+		expected string
+		// ptr is a pointer to a zero value to unmarshal the encoding into
+		ptr interface{}
+	}{
+		{
+			name:     "requestmessage",
+			in:       testRequestMessage(),
+			expected: "42007801000000b04200770100000048420069010000002042006a0200000004000000010000000042006b020000000400000004000000004200500200000004000010000000000042000d0200000004000000010000000042000f010000005842005c05000000040000000a00000000420093080000000401020304000000004200790100000030420094070000002434396131636138382d366265612d346662322d623435302d37653538383032633330333800000000",
+			ptr:      &RequestMessage{},
+		},
+		{
+			name:     "getresponsepayload",
+			tag:      kmip14.TagResponsePayload,
+			in:       testGetResponsePayload(),
+			expected: "42007c01000000a042005705000000040000000200000000420094070000002434396131636138382d366265612d346662322d623435302d3765353838303263333033380000000042008f0100000058420040010000005042004205000000040000000100000000420045010000001842004308000000107367578051012a6d134a855e25c8cd5e4200280500000004000000030000000042002a02000000040000008000000000",
+			ptr:      &GetResponsePayload{},
+		},
+		{
+			name:     "responsemessage",
+			in:       testResponseMessage(),
+			expected: "42007b010000013042007a0100000048420069010000002042006a0200000004000000010000000042006b020000000400000004000000004200920900000008000000006074677842000d0200000004000000010000000042000f01000000d842005c05000000040000000a000000004200930800000004010203040000000042007f0500000004000000000000000042007c01000000a042005705000000040000000200000000420094070000002434396131636138382d366265612d346662322d623435302d3765353838303263333033380000000042008f0100000058420040010000005042004205000000040000000100000000420045010000001842004308000000107367578051012a6d134a855e25c8cd5e4200280500000004000000030000000042002a02000000040000008000000000",
+			ptr:      &ResponseMessage{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := marshal(tc.tag, tc.in)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, hex.EncodeToString(b))
+
+			// decoding and re-encoding should produce the same bytes.  Payloads in
+			// interface fields decode as ttlv.TTLV, which re-encodes as-is.
+			require.NoError(t, ttlv.Unmarshal(b, tc.ptr))
+
+			b2, err := marshal(tc.tag, tc.ptr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, hex.EncodeToString(b2))
+		})
+	}
+}
+
+func TestGeneratedMarshalersEmbedded(t *testing.T) {
+	// the generated methods are promoted to structs embedding generated types.  They
+	// must be ignored, so the embedded fields are flattened, as with the reflection
+	// based encoder.
+	type extendedGet struct {
+		GetRequestPayload
+		KeyFormatType kmip14.KeyFormatType
+	}
+
+	in := extendedGet{
+		GetRequestPayload: GetRequestPayload{UniqueIdentifier: "key1"},
+		KeyFormatType:     kmip14.KeyFormatTypeRaw,
+	}
+
+	expected, err := ttlv.Marshal(ttlv.NewStruct(kmip14.TagRequestPayload,
+		ttlv.NewValue(kmip14.TagUniqueIdentifier, "key1"),
+		ttlv.NewValue(kmip14.TagKeyFormatType, kmip14.KeyFormatTypeRaw),
+	))
+	require.NoError(t, err)
+
+	b, err := marshal(kmip14.TagRequestPayload, in)
+	require.NoError(t, err)
+	assert.Equal(t, expected, b, ttlv.Diff(expected, b))
+
+	b, err = marshal(kmip14.TagRequestPayload, &in)
+	require.NoError(t, err)
+	assert.Equal(t, expected, b, ttlv.Diff(expected, b))
+
+	var out extendedGet
+
+	require.NoError(t, ttlv.Unmarshal(expected, &out))
+	assert.Equal(t, in, out)
+
+	// methods declared by the embedding struct are still used
+	b, err = marshal(kmip14.TagRequestPayload, ownGet{})
+	require.NoError(t, err)
+
+	expected, err = ttlv.Marshal(ttlv.NewValue(kmip14.TagRequestPayload, "own"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, b)
+
+	// the generated methods of embedded fields still work when called directly
+	m, ok := interface{}(in).(ttlv.Marshaler)
+	if !ok {
+		// built with the ttlvreflect tag, without the generated methods
+		return
+	}
+
+	var buf bytes.Buffer
+
+	enc := ttlv.NewEncoder(&buf)
+	require.NoError(t, m.MarshalTTLV(enc, kmip14.TagRequestPayload))
+	require.NoError(t, enc.Flush())
+
+	expected, err = marshal(kmip14.TagRequestPayload, in.GetRequestPayload)
+	require.NoError(t, err)
+	assert.Equal(t, expected, ttlv.TTLV(buf.Bytes()))
+}
+
+// ownGet embeds a type with generated methods, and declares its own MarshalTTLV.  It
+// declares TTLVGenerated too, so its method isn't mistaken for the promoted generated one.
+type ownGet struct {
+	GetRequestPayload
+}
+
+func (ownGet) MarshalTTLV(e *ttlv.Encoder, tag ttlv.Tag) error {
+	return e.EncodeValue(tag, "own")
+}
+
+func (*ownGet) TTLVGenerated() reflect.Type {
+	return nil
+}
+
+func TestGeneratedMarshalersRegistry(t *testing.T) {
+	// the generated methods encode the tags of DefaultRegistry.  With another Registry,
+	// values are encoded and decoded with the tags it names.
+	r := &ttlv.Registry{}
+	ttlv.RegisterTypes(r)
+	r.RegisterTag(ttlv.Tag(0x540001), "UniqueIdentifier")
+
+	expected, err := ttlv.Marshal(ttlv.NewStruct(kmip14.TagRequestPayload,
+		ttlv.NewValue(ttlv.Tag(0x540001), "key1"),
+	))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	enc := ttlv.NewEncoder(&buf)
+	enc.Registry = r
+	require.NoError(t, enc.EncodeValue(kmip14.TagRequestPayload, GetRequestPayload{UniqueIdentifier: "key1"}))
+	assert.Equal(t, expected, ttlv.TTLV(buf.Bytes()), ttlv.Diff(expected, buf.Bytes()))
+
+	var p GetRequestPayload
+
+	dec := ttlv.NewDecoder(nil)
+	dec.Registry = r
+	require.NoError(t, dec.DecodeValue(&p, expected))
+	assert.Equal(t, GetRequestPayload{UniqueIdentifier: "key1"}, p)
+
+	// the generated methods fall back on reflection when they're called directly, too
+	m, ok := interface{}(&p).(interface {
+		ttlv.Marshaler
+		ttlv.Unmarshaler
+	})
+	if !ok {
+		// built with the ttlvreflect tag, without the generated methods
+		return
+	}
+
+	buf.Reset()
+	require.NoError(t, m.MarshalTTLV(enc, kmip14.TagRequestPayload))
+	require.NoError(t, enc.Flush())
+	assert.Equal(t, expected, ttlv.TTLV(buf.Bytes()), ttlv.Diff(expected, buf.Bytes()))
+
+	p = GetRequestPayload{}
+	require.NoError(t, m.UnmarshalTTLV(dec, expected))
+	assert.Equal(t, GetRequestPayload{UniqueIdentifier: "key1"}, p)
+}
+
+func TestGeneratedMarshalersValidation(t *testing.T) {
+	p := testGetResponsePayload()
+	p.UniqueIdentifier = ""
+
+	_, err := marshal(kmip14.TagResponsePayload, p)
+	require.ErrorIs(t, err, ttlv.ErrMissingValue)
+
+	b, err := ttlv.Marshal(ttlv.NewStruct(kmip14.TagResponsePayload,
+		ttlv.NewValue(kmip14.TagObjectType, kmip14.ObjectTypeSymmetricKey),
+	))
+	require.NoError(t, err)
+
+	var got GetResponsePayload
+	err = ttlv.Unmarshal(b, &got)
+	require.ErrorIs(t, err, ttlv.ErrMissingValue)
+}
+
+func TestGeneratedUnmarshalersErrorPosition(t *testing.T) {
+	tests := []struct {
+		name            string
+		header, item    ttlv.Values
+		path, fieldPath string
+	}{
+		{
+			name: "nestedStruct",
+			header: ttlv.Values{
+				{Tag: kmip14.TagProtocolVersion, Value: ttlv.Values{
+					{Tag: kmip14.TagProtocolVersionMajor, Value: "one"},
+				}},
+			},
+			item:      ttlv.Values{{Tag: kmip14.TagOperation, Value: kmip14.OperationGet}},
+			path:      "RequestMessage/RequestHeader/ProtocolVersion/ProtocolVersionMajor",
+			fieldPath: "RequestMessage.RequestHeader.ProtocolVersion.ProtocolVersionMajor",
+		},
+		{
+			name:      "sliceElement",
+			item:      ttlv.Values{{Tag: kmip14.TagOperation, Value: ttlv.Values{}}},
+			path:      "RequestMessage/BatchItem[1]/Operation",
+			fieldPath: "RequestMessage.BatchItem[1].Operation",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := ttlv.Marshal(ttlv.Value{Tag: kmip14.TagRequestMessage, Value: ttlv.Values{
+				{Tag: kmip14.TagRequestHeader, Value: tc.header},
+				{Tag: kmip14.TagBatchItem, Value: ttlv.Values{{Tag: kmip14.TagOperation, Value: kmip14.OperationGet}}},
+				{Tag: kmip14.TagBatchItem, Value: tc.item},
+			}})
+			require.NoError(t, err)
+
+			var msg RequestMessage
+			err = ttlv.Unmarshal(b, &msg)
+			require.Error(t, err)
+
+			var ue *ttlv.UnmarshalerError
+			require.ErrorAs(t, err, &ue)
+			assert.Equal(t, tc.path, ue.Path)
+			assert.Equal(t, tc.fieldPath, ue.FieldPath)
+			assert.Equal(t, ue.Tag, ttlv.TTLV(b[ue.Offset:]).Tag())
+		})
+	}
+}
+
+func BenchmarkMarshalRequestMessage(b *testing.B) {
+	msg := testRequestMessage()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := ttlv.Marshal(msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalRequestMessage(b *testing.B) {
+	buf, err := ttlv.Marshal(testRequestMessage())
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var msg RequestMessage
+		if err := ttlv.Unmarshal(buf, &msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalGetResponsePayload(b *testing.B) {
+	p := testGetResponsePayload()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := marshal(kmip14.TagResponsePayload, p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalGetResponsePayload(b *testing.B) {
+	buf, err := marshal(kmip14.TagResponsePayload, testGetResponsePayload())
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var p GetResponsePayload
+		if err := ttlv.Unmarshal(buf, &p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
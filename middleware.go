@@ -0,0 +1,211 @@
+package kmip
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/ansel1/merry"
+	"github.com/gemalto/flume"
+)
+
+// ItemMiddleware wraps an ItemHandler with cross-cutting behavior, like logging or
+// panic recovery.  Register them with OperationMux.Use.
+type ItemMiddleware func(ItemHandler) ItemHandler
+
+// MessageMiddleware wraps a MessageHandler with cross-cutting behavior.  Register them
+// with StandardProtocolHandler.Use.
+type MessageMiddleware func(MessageHandler) MessageHandler
+
+// Use appends middleware to the chain wrapping the mux's ItemHandlers.  The first middleware
+// registered is the outermost.  Middleware applies to the handlers of all operations,
+// whether registered before or after Use is called.  It wraps the ItemHandler only: items
+// which fail authorization, or have no handler, don't reach the middleware.
+func (m *OperationMux) Use(mw ...ItemMiddleware) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.middleware = append(m.middleware, mw...)
+
+	// the chain is composed once per handler, rather than for each item
+	for op, h := range m.handlers {
+		m.wrapped[op] = m.wrap(h)
+	}
+}
+
+// wrap returns h wrapped in the mux's middleware.
+func (m *OperationMux) wrap(h ItemHandler) ItemHandler {
+	for i := len(m.middleware) - 1; i >= 0; i-- {
+		h = m.middleware[i](h)
+	}
+
+	return h
+}
+
+// Use appends middleware to the chain wrapping the handler's MessageHandler.  The first
+// middleware registered is the outermost.  Use may be called while the handler is serving
+// requests.  Requests which arrive after it returns pass through the new middleware.
+func (h *StandardProtocolHandler) Use(mw ...MessageMiddleware) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.middleware = append(h.middleware, mw...)
+	h.chain = nil
+}
+
+// messageHandler returns the MessageHandler wrapped in the middleware.
+func (h *StandardProtocolHandler) messageHandler() MessageHandler {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.chain == nil {
+		// the chain is composed once, rather than for each message.  The innermost
+		// handler reads MessageHandler for each message, so changes to it still apply.
+		var next MessageHandler = MessageHandlerFunc(func(ctx context.Context, req *Request, resp *Response) {
+			h.MessageHandler.HandleMessage(ctx, req, resp)
+		})
+
+		for i := len(h.middleware) - 1; i >= 0; i-- {
+			next = h.middleware[i](next)
+		}
+
+		h.chain = next
+	}
+
+	return h.chain
+}
+
+// ErrPanic is the cause of the errors ItemRecovery returns for panics.
+var ErrPanic = WithResultReason(merry.New("panic").WithUserMessage("internal server error"), kmip14.ResultReasonGeneralFailure)
+
+// recovered logs the value recovered from a panic, with the stack.
+func recovered(ctx context.Context, v interface{}) {
+	const size = 64 << 10
+	buf := make([]byte, size)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	if e, ok := v.(error); ok {
+		v = Details(e)
+	}
+
+	flume.FromContext(ctx).Error("panic handling request", "panic", fmt.Sprint(v), "stack", string(buf))
+}
+
+// ItemRecovery is an ItemMiddleware which recovers panics in the ItemHandler.  The panic
+// is logged, and the item fails with ResultReasonGeneralFailure.
+func ItemRecovery(next ItemHandler) ItemHandler {
+	return ItemHandlerFunc(func(ctx context.Context, req *Request) (item *ResponseBatchItem, err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				recovered(ctx, v)
+				item, err = nil, merry.Appendf(ErrPanic, "%v", v)
+			}
+		}()
+
+		return next.HandleItem(ctx, req)
+	})
+}
+
+// MessageRecovery is a MessageMiddleware which recovers panics in the MessageHandler.  The panic
+// is logged, and the response is replaced with a single failed batch item, with
+// ResultReasonGeneralFailure.  Without it, the connection is closed.
+func MessageRecovery(next MessageHandler) MessageHandler {
+	return MessageHandlerFunc(func(ctx context.Context, req *Request, resp *Response) {
+		defer func() {
+			if v := recover(); v != nil {
+				recovered(ctx, v)
+				resp.errorResponse(kmip14.ResultReasonGeneralFailure, "internal server error")
+			}
+		}()
+
+		next.HandleMessage(ctx, req, resp)
+	})
+}
+
+// ItemLogging is an ItemMiddleware which logs each batch item, with its operation, result,
+// and duration, to the logger in the context.
+func ItemLogging(next ItemHandler) ItemHandler {
+	return ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+		start := time.Now()
+		item, err := next.HandleItem(ctx, req)
+
+		args := []interface{}{"operation", req.CurrentItem.Operation, "duration", time.Since(start)}
+
+		switch {
+		case err != nil:
+			args = append(args, "err", err)
+		case item != nil:
+			args = append(args, "status", item.ResultStatus)
+			if item.ResultStatus == kmip14.ResultStatusOperationFailed {
+				args = append(args, "reason", item.ResultReason, "message", item.ResultMessage)
+			}
+		}
+
+		flume.FromContext(ctx).Info("handled batch item", args...)
+
+		return item, err
+	})
+}
+
+// MessageLogging is a MessageMiddleware which logs each request message, with its batch
+// count, the number of failed batch items, and duration, to the logger in the context.
+func MessageLogging(next MessageHandler) MessageHandler {
+	return MessageHandlerFunc(func(ctx context.Context, req *Request, resp *Response) {
+		start := time.Now()
+		next.HandleMessage(ctx, req, resp)
+
+		failed := 0
+
+		for i := range resp.BatchItem {
+			if resp.BatchItem[i].ResultStatus == kmip14.ResultStatusOperationFailed {
+				failed++
+			}
+		}
+
+		flume.FromContext(ctx).Info("handled request",
+			"batchCount", len(req.Message.BatchItem),
+			"failed", failed,
+			"duration", time.Since(start),
+		)
+	})
+}
+
+// ItemLatency returns an ItemMiddleware which measures how long each batch item takes to
+// handle.  observe is called with the operation, the result status of the item, and
+// the duration.  Items whose handler returned an error have ResultStatusOperationFailed.
+func ItemLatency(observe func(op kmip14.Operation, status kmip14.ResultStatus, d time.Duration)) ItemMiddleware {
+	return func(next ItemHandler) ItemHandler {
+		return ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+			start := time.Now()
+			item, err := next.HandleItem(ctx, req)
+
+			status := kmip14.ResultStatusSuccess
+
+			switch {
+			case err != nil:
+				status = kmip14.ResultStatusOperationFailed
+			case item != nil:
+				status = item.ResultStatus
+			}
+
+			observe(req.CurrentItem.Operation, status, time.Since(start))
+
+			return item, err
+		})
+	}
+}
+
+// MessageLatency returns a MessageMiddleware which measures how long each request message
+// takes to handle.  observe is called with the number of batch items in the request, and
+// the duration.
+func MessageLatency(observe func(batchCount int, d time.Duration)) MessageMiddleware {
+	return func(next MessageHandler) MessageHandler {
+		return MessageHandlerFunc(func(ctx context.Context, req *Request, resp *Response) {
+			start := time.Now()
+			next.HandleMessage(ctx, req, resp)
+			observe(len(req.Message.BatchItem), time.Since(start))
+		})
+	}
+}
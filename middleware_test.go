@@ -0,0 +1,203 @@
+package kmip
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveMessage serves the test request message with h, and returns the decoded response.
+func serveMessage(t *testing.T, h *StandardProtocolHandler) ResponseMessage {
+	t.Helper()
+
+	req, err := ttlv.Marshal(testRequestMessage())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	h.ServeKMIP(context.Background(), &Request{TTLV: req}, &buf)
+
+	var resp ResponseMessage
+	require.NoError(t, ttlv.Unmarshal(buf.Bytes(), &resp))
+
+	return resp
+}
+
+func TestOperationMux_Use(t *testing.T) {
+	var order []string
+
+	trace := func(name string) ItemMiddleware {
+		return func(next ItemHandler) ItemHandler {
+			return ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+				order = append(order, name)
+				return next.HandleItem(ctx, req)
+			})
+		}
+	}
+
+	mux := &OperationMux{}
+	mux.Use(trace("first"), trace("second"))
+	// middleware applies to handlers registered after Use too
+	mux.Handle(kmip14.OperationGet, ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+		order = append(order, "handler")
+		return &ResponseBatchItem{}, nil
+	}))
+	mux.Use(trace("third"))
+
+	assert.Equal(t, []kmip14.ResultReason{0, kmip14.ResultReasonOperationNotSupported}, handleItems(mux, nil, getItem("key1"), RequestBatchItem{Operation: kmip14.OperationDestroy}))
+	assert.Equal(t, []string{"first", "second", "third", "handler"}, order)
+}
+
+func TestOperationMux_Use_composedOnce(t *testing.T) {
+	wrapped := 0
+
+	mux := &OperationMux{}
+	mux.Use(func(next ItemHandler) ItemHandler {
+		wrapped++
+		return next
+	})
+	mux.Handle(kmip14.OperationGet, ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+		return &ResponseBatchItem{}, nil
+	}))
+
+	for i := 0; i < 3; i++ {
+		handleItems(mux, nil, getItem("key1"), getItem("key2"))
+	}
+
+	assert.Equal(t, 1, wrapped)
+
+	// adding middleware composes the chain again
+	mux.Use(ItemLogging)
+	handleItems(mux, nil, getItem("key1"))
+	assert.Equal(t, 2, wrapped)
+}
+
+func TestItemRecovery(t *testing.T) {
+	var observed []kmip14.ResultStatus
+
+	mux := &OperationMux{}
+	mux.Use(ItemLatency(func(op kmip14.Operation, status kmip14.ResultStatus, d time.Duration) {
+		assert.Equal(t, kmip14.OperationGet, op)
+		observed = append(observed, status)
+	}), ItemLogging, ItemRecovery)
+	mux.Handle(kmip14.OperationGet, ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+		panic("boom")
+	}))
+
+	h := testProtocolHandler()
+	h.MessageHandler = mux
+
+	resp := serveMessage(t, h)
+	require.Len(t, resp.BatchItem, 1)
+	assert.Equal(t, kmip14.ResultStatusOperationFailed, resp.BatchItem[0].ResultStatus)
+	assert.Equal(t, kmip14.ResultReasonGeneralFailure, resp.BatchItem[0].ResultReason)
+	assert.Equal(t, "internal server error", resp.BatchItem[0].ResultMessage)
+	assert.Equal(t, []kmip14.ResultStatus{kmip14.ResultStatusOperationFailed}, observed)
+}
+
+func TestStandardProtocolHandler_Use(t *testing.T) {
+	var batchCounts []int
+
+	h := testProtocolHandler()
+	h.Use(MessageLatency(func(batchCount int, d time.Duration) {
+		batchCounts = append(batchCounts, batchCount)
+	}), MessageLogging)
+
+	resp := serveMessage(t, h)
+	require.Len(t, resp.BatchItem, 1)
+	assert.Equal(t, kmip14.ResultStatusSuccess, resp.BatchItem[0].ResultStatus, resp.BatchItem[0].ResultMessage)
+	assert.Equal(t, []int{1}, batchCounts)
+}
+
+func TestStandardProtocolHandler_Use_composedOnce(t *testing.T) {
+	wrapped := 0
+
+	h := testProtocolHandler()
+	h.Use(func(next MessageHandler) MessageHandler {
+		wrapped++
+		return next
+	})
+
+	for i := 0; i < 3; i++ {
+		serveMessage(t, h)
+	}
+
+	assert.Equal(t, 1, wrapped)
+
+	// adding middleware composes the chain again
+	h.Use(MessageLogging)
+	serveMessage(t, h)
+	assert.Equal(t, 2, wrapped)
+}
+
+func TestStandardProtocolHandler_Use_afterServing(t *testing.T) {
+	var order []string
+
+	trace := func(name string) MessageMiddleware {
+		return func(next MessageHandler) MessageHandler {
+			return MessageHandlerFunc(func(ctx context.Context, req *Request, resp *Response) {
+				order = append(order, name)
+				next.HandleMessage(ctx, req, resp)
+			})
+		}
+	}
+
+	h := testProtocolHandler()
+	h.Use(trace("first"))
+	serveMessage(t, h)
+
+	// middleware and handlers set after the first request are used
+	h.Use(trace("second"))
+	h.MessageHandler = MessageHandlerFunc(func(ctx context.Context, req *Request, resp *Response) {
+		order = append(order, "handler")
+	})
+	serveMessage(t, h)
+
+	assert.Equal(t, []string{"first", "first", "second", "handler"}, order)
+}
+
+func TestStandardProtocolHandler_Use_concurrent(t *testing.T) {
+	h := testProtocolHandler()
+
+	req, err := ttlv.Marshal(testRequestMessage())
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			h.Use(MessageLogging)
+		}()
+
+		go func() {
+			defer wg.Done()
+			h.ServeKMIP(context.Background(), &Request{TTLV: req}, &bytes.Buffer{})
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestMessageRecovery(t *testing.T) {
+	h := testProtocolHandler()
+	h.MessageHandler = MessageHandlerFunc(func(ctx context.Context, req *Request, resp *Response) {
+		panic("boom")
+	})
+	h.Use(MessageRecovery)
+
+	resp := serveMessage(t, h)
+	require.Len(t, resp.BatchItem, 1)
+	assert.Equal(t, 1, resp.ResponseHeader.BatchCount)
+	assert.Equal(t, kmip14.ResultReasonGeneralFailure, resp.BatchItem[0].ResultReason)
+	assert.Equal(t, "internal server error", resp.BatchItem[0].ResultMessage)
+}
@@ -22,7 +22,7 @@ type ActivateRequestPayload struct {
 // Table 211
 
 type ActivateResponsePayload struct {
-	UniqueIdentifier string // Required: Yes
+	UniqueIdentifier string `ttlv:",required"`
 }
 
 type ActivateHandler struct {
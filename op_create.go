@@ -8,12 +8,13 @@ import (
 	"github.com/Seagate/kmip-go/kmip14"
 )
 
-// TODO: should request and response payloads implement validation?
-// Sort of makes sense to run validation over the request at this level, at least for spec
-// compliance, though perhaps handlers may want to be more relaxed with validation.
-//
-// Should the response object run through validation?  What is a valid response may change as
-// the spec changes.  Maybe this should just be handled by spec compliance tests.
+// The fields of the request and response payloads in this package which the spec marks
+// "Required: Yes" have the "required" struct tag flag, which ttlv.Marshal and ttlv.Unmarshal
+// enforce.  Other fields are optional, like the Unique Identifier of most requests, which
+// defaults to the ID Placeholder.  Errors from missing or invalid fields are mapped to the
+// MissingData and InvalidField result reasons by GetResultReason.
+// Managed object fields are the exception, and aren't marked "required" or "oneof": payloads
+// only model some of the object types, and an object of another type, like a PGPKey, is valid.
 
 // 4.1
 //
@@ -31,14 +32,14 @@ import (
 //
 // TemplateAttribute MUST include CryptographicAlgorithm (3.4) and CryptographicUsageMask (3.19).
 type CreateRequestPayload struct {
-	ObjectType        kmip14.ObjectType
-	TemplateAttribute TemplateAttribute
+	ObjectType        kmip14.ObjectType `ttlv:",required"`
+	TemplateAttribute TemplateAttribute `ttlv:",required"`
 }
 
 // CreateResponsePayload 4.1 Table 164
 type CreateResponsePayload struct {
-	ObjectType        kmip14.ObjectType
-	UniqueIdentifier  string
+	ObjectType        kmip14.ObjectType `ttlv:",required"`
+	UniqueIdentifier  string            `ttlv:",required"`
 	TemplateAttribute *TemplateAttribute
 }
 
@@ -31,8 +31,8 @@ type CreateKeyPairRequestPayload struct {
 }
 
 type CreateKeyPairResponsePayload struct {
-	PrivateKeyUniqueIdentifier  string
-	PublicKeyUniqueIdentifier   string
+	PrivateKeyUniqueIdentifier  string `ttlv:",required"`
+	PublicKeyUniqueIdentifier   string `ttlv:",required"`
 	PrivateKeyTemplateAttribute *TemplateAttribute
 	PublicKeyTemplateAttribute  *TemplateAttribute
 }
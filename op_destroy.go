@@ -11,7 +11,7 @@ type DestroyRequestPayload struct {
 
 // DestroyResponsePayload
 type DestroyResponsePayload struct {
-	UniqueIdentifier string
+	UniqueIdentifier string `ttlv:",required"`
 }
 
 type DestroyHandler struct {
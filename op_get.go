@@ -7,14 +7,18 @@ import (
 )
 
 // GetRequestPayload ////////////////////////////////////////
+//
+//ttlvgen:generate
 type GetRequestPayload struct {
 	UniqueIdentifier string
 }
 
 // GetResponsePayload
+//
+//ttlvgen:generate
 type GetResponsePayload struct {
-	ObjectType       kmip14.ObjectType
-	UniqueIdentifier string
+	ObjectType       kmip14.ObjectType `ttlv:",required"`
+	UniqueIdentifier string            `ttlv:",required"`
 	Certificate      *Certificate
 	SymmetricKey     *SymmetricKey
 	PrivateKey       *PrivateKey
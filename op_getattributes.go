@@ -17,7 +17,7 @@ type GetAttributesRequestPayload struct {
 // Table 197
 
 type GetAttributesResponsePayload struct {
-	UniqueIdentifier string      // Required: Yes
+	UniqueIdentifier string      `ttlv:",required"`
 	Attribute        []Attribute // Required: No
 }
 
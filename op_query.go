@@ -11,7 +11,7 @@ import (
 // Table 259
 
 type QueryRequestPayload struct {
-	QueryFunction []kmip14.QueryFunction
+	QueryFunction []kmip14.QueryFunction `ttlv:",required"`
 }
 
 type CapabilityInformation struct {
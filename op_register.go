@@ -12,8 +12,8 @@ import (
 // Table 169
 
 type RegisterRequestPayload struct {
-	ObjectType        kmip14.ObjectType
-	TemplateAttribute TemplateAttribute
+	ObjectType        kmip14.ObjectType `ttlv:",required"`
+	TemplateAttribute TemplateAttribute `ttlv:",required"`
 	Certificate       *Certificate
 	SymmetricKey      *SymmetricKey
 	PrivateKey        *PrivateKey
@@ -27,7 +27,7 @@ type RegisterRequestPayload struct {
 // Table 170
 
 type RegisterResponsePayload struct {
-	UniqueIdentifier  string
+	UniqueIdentifier  string `ttlv:",required"`
 	TemplateAttribute TemplateAttribute
 }
 
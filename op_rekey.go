@@ -11,7 +11,7 @@ type ReKeyRequestPayload struct {
 
 // ReKeyResponsePayload
 type ReKeyResponsePayload struct {
-	UniqueIdentifier string
+	UniqueIdentifier string `ttlv:",required"`
 }
 
 type ReKeyHandler struct {
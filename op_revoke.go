@@ -11,7 +11,7 @@ import (
 // Table 121
 
 type RevocationReasonStruct struct {
-	RevocationReasonCode kmip14.RevocationReasonCode // Required: Yes
+	RevocationReasonCode kmip14.RevocationReasonCode `ttlv:",required"`
 	RevocationMessage    string                      // Required: No
 }
 
@@ -19,14 +19,14 @@ type RevocationReasonStruct struct {
 
 type RevokeRequestPayload struct {
 	UniqueIdentifier         string                 // Required: No
-	RevocationReason         RevocationReasonStruct // Required: Yes
+	RevocationReason         RevocationReasonStruct `ttlv:",required"`
 	CompromiseOccurrenceDate []byte                 // Required: No
 }
 
 // Table 213
 
 type RevokeResponsePayload struct {
-	UniqueIdentifier string // Required: Yes
+	UniqueIdentifier string `ttlv:",required"`
 }
 
 type RevokeHandler struct {
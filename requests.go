@@ -9,16 +9,20 @@ package kmip
 // package as an analogy, ProtocolHandler is similar to the wire-level HTTP protocol handling in
 // http.Server and http.Transport.  MessageHandler parses KMIP TTLV bytes into golang request and response structs.
 // ItemHandler is a bit like http.ServeMux, routing particular KMIP operations to registered handlers.
+// Cross-cutting behavior, like logging and panic recovery, can be layered around the MessageHandler
+// and ItemHandlers with MessageMiddleware and ItemMiddleware.
 
 import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"reflect"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -38,14 +42,16 @@ var serverLog = flume.New("kmip_server")
 // each request).  For each KMIP connection, requests are processed serially.  The handling
 // of the request is delegated to the ProtocolHandler.
 //
+// Each connection has a Session, which handlers can use to store state, like the identity of
+// an authenticated client, for the life of the connection.  Since HTTP is an intrinsically stateless
+// model, it makes sense for the http package to delegate session management to third party packages,
+// but KMIP connections are long lived, so the Session is the connection context.
+//
 // Limitations:
 //
 // This implementation is functional (it can respond to KMIP requests), but incomplete.  Some of the
-// connection management features of the http package haven't been ported over, and also, there is
-// currently no connection-context in which to store things like an authentication or session management.
-// Since HTTP is an intrinsically stateless model, it makes sense for the http package to delegate session
-// management to third party packages, but for KMIP, it would makes sense for there to be some first
-// class support for a connection context.
+// connection management features of the http package, like keep-alive control and
+// RegisterOnShutdown, haven't been ported over.
 //
 // This package also only handles the binary TTLV encoding for now.  It may make sense for this
 // server to detect or support the XML and JSON encodings as well.  It may also makes sense to support
@@ -53,11 +59,101 @@ var serverLog = flume.New("kmip_server")
 type Server struct {
 	Handler ProtocolHandler
 
+	// MaxMessageSize, MaxDepth, and MaxItemsPerStructure limit the requests the server
+	// will read from a connection, so a client can't exhaust the server's memory with a
+	// hostile request.  See ttlv.Decoder.  If zero, DefaultMaxMessageSize, DefaultMaxDepth,
+	// and DefaultMaxItemsPerStructure are used.  Negative values disable the limit.
+	// The connection is closed when a request exceeds a limit.
+	MaxMessageSize       int
+	MaxDepth             int
+	MaxItemsPerStructure int
+
+	// ReadTimeout is the maximum duration for reading a request, measured from
+	// the first byte of the request.  It also limits the TLS handshake.  Zero means no timeout.
+	ReadTimeout time.Duration
+
+	// WriteTimeout is the maximum duration before timing out writes of the response,
+	// measured from the end of reading the request, so it includes the time spent handling
+	// the request.  It also limits the TLS handshake.  Zero means no timeout.
+	WriteTimeout time.Duration
+
+	// IdleTimeout is the maximum amount of time to wait for the next request on
+	// a connection.  If zero, the value of ReadTimeout is used.  If both are zero,
+	// there is no timeout.  The connection is closed when it times out.
+	IdleTimeout time.Duration
+
+	// MaxConnections limits the number of connections served at once.  When the limit
+	// is reached, Serve stops accepting connections until one is closed.  Zero means
+	// no limit.
+	MaxConnections int
+
+	// ConnState, if set, is called when a connection changes state.  See the
+	// ConnState type for details.
+	ConnState func(net.Conn, ConnState)
+
+	// OnConnect, if set, is called with the Session of each new connection, before any
+	// requests are read.  If it returns an error, the connection is closed.
+	OnConnect func(ctx context.Context, s *Session) error
+	// OnClose, if set, is called with the Session of each connection accepted by OnConnect,
+	// after the connection is closed, and after the functions registered with Session.OnClose.
+	OnClose func(s *Session)
+
 	mu         sync.Mutex
 	listeners  map[*net.Listener]struct{}
+	activeConn map[*conn]struct{}
+	connSem    chan struct{} // holds a token for each connection, if MaxConnections is set
+	doneChan   chan struct{}
 	inShutdown int32 // accessed atomically (non-zero means we're in Shutdown)
 }
 
+// A ConnState represents the state of a client connection to a server.
+// It's used by the optional Server.ConnState hook.
+type ConnState int
+
+const (
+	// StateNew represents a new connection that is expected to
+	// send a request immediately.  Connections begin at this
+	// state and then transition to either StateActive or
+	// StateClosed.
+	StateNew ConnState = iota
+
+	// StateActive represents a connection that has read 1 or more
+	// bytes of a request.  The Server.ConnState hook for
+	// StateActive fires before the request has entered a handler
+	// and doesn't fire again until the response has been written.
+	// After the response is written, the connection transitions
+	// to StateIdle.
+	StateActive
+
+	// StateIdle represents a connection that has finished
+	// handling a request and is waiting for a new request.
+	// Connections transition from StateIdle to either
+	// StateActive or StateClosed.
+	StateIdle
+
+	// StateClosed represents a closed connection.
+	// This is a terminal state.
+	StateClosed
+)
+
+var stateName = map[ConnState]string{
+	StateNew:    "new",
+	StateActive: "active",
+	StateIdle:   "idle",
+	StateClosed: "closed",
+}
+
+func (c ConnState) String() string {
+	return stateName[c]
+}
+
+// Default limits on the requests read by a Server.
+const (
+	DefaultMaxMessageSize       = 1 << 20 // 1 MB
+	DefaultMaxDepth             = 32
+	DefaultMaxItemsPerStructure = 4096
+)
+
 // ErrServerClosed is returned by the Server's Serve, ServeTLS, ListenAndServe,
 // and ListenAndServeTLS methods after a call to Shutdown or Close.
 var ErrServerClosed = errors.New("http: Server closed")
@@ -86,8 +182,12 @@ func (srv *Server) Serve(l net.Listener) error {
 	ctx := baseCtx
 	// ctx := context.WithValue(baseCtx, ServerContextKey, srv)
 	for {
+		if !srv.acquireConn() {
+			return ErrServerClosed
+		}
 		rw, e := l.Accept()
 		if e != nil {
+			srv.releaseConn()
 			if srv.shuttingDown() {
 				return ErrServerClosed
 			}
@@ -108,30 +208,87 @@ func (srv *Server) Serve(l net.Listener) error {
 		}
 		tempDelay = 0
 		c := &conn{server: srv, rwc: rw}
-		// c.setState(c.rwc, StateNew) // before Serve can return
+		c.setState(c.rwc, StateNew) // before Serve can return
 		go c.serve(ctx)
 	}
 }
 
+// acquireConn blocks until the server is below MaxConnections.  It reports
+// false if the server is shut down while waiting.
+func (srv *Server) acquireConn() bool {
+	if srv.MaxConnections <= 0 {
+		return true
+	}
+
+	srv.mu.Lock()
+	if srv.connSem == nil {
+		srv.connSem = make(chan struct{}, srv.MaxConnections)
+	}
+	sem := srv.connSem
+	srv.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-srv.getDoneChan():
+		return false
+	}
+}
+
+// releaseConn releases the token taken by acquireConn.
+func (srv *Server) releaseConn() {
+	if srv.MaxConnections <= 0 {
+		return
+	}
+
+	srv.mu.Lock()
+	sem := srv.connSem
+	srv.mu.Unlock()
+
+	<-sem
+}
+
+func (srv *Server) getDoneChan() <-chan struct{} {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.getDoneChanLocked()
+}
+
+func (srv *Server) getDoneChanLocked() chan struct{} {
+	if srv.doneChan == nil {
+		srv.doneChan = make(chan struct{})
+	}
+	return srv.doneChan
+}
+
+func (srv *Server) closeDoneChanLocked() {
+	ch := srv.getDoneChanLocked()
+	select {
+	case <-ch:
+		// Already closed. Don't close again.
+	default:
+		// Safe to close here. We're the only closer, guarded
+		// by srv.mu.
+		close(ch)
+	}
+}
+
 // Close immediately closes all active net.Listeners and any
 // connections in state StateNew, StateActive, or StateIdle. For a
 // graceful shutdown, use Shutdown.
 //
-// Close does not attempt to close (and does not even know about)
-// any hijacked connections, such as WebSockets.
-//
 // Close returns any error returned from closing the Server's
 // underlying Listener(s).
 func (srv *Server) Close() error {
 	atomic.StoreInt32(&srv.inShutdown, 1)
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
-	// srv.closeDoneChanLocked()
+	srv.closeDoneChanLocked()
 	err := srv.closeListenersLocked()
-	//for c := range srv.activeConn {
-	//	c.rwc.Close()
-	//	delete(srv.activeConn, c)
-	//}
+	for c := range srv.activeConn {
+		_ = c.rwc.Close()
+		delete(srv.activeConn, c)
+	}
 	return err
 }
 
@@ -147,7 +304,8 @@ var shutdownPollInterval = 500 * time.Millisecond
 // Shutdown gracefully shuts down the server without interrupting any
 // active connections. Shutdown works by first closing all open
 // listeners, then closing all idle connections, and then waiting
-// indefinitely for connections to return to idle and then shut down.
+// indefinitely for connections to finish the request they are handling,
+// return to idle, and then shut down.
 // If the provided context expires before the shutdown is complete,
 // Shutdown returns the context's error, otherwise it returns any
 // error returned from closing the Server's underlying Listener(s).
@@ -156,12 +314,6 @@ var shutdownPollInterval = 500 * time.Millisecond
 // ListenAndServeTLS immediately return ErrServerClosed. Make sure the
 // program doesn't exit and waits instead for Shutdown to return.
 //
-// Shutdown does not attempt to close nor wait for hijacked
-// connections such as WebSockets. The caller of Shutdown should
-// separately notify such long-lived connections of shutdown and wait
-// for them to close, if desired. See RegisterOnShutdown for a way to
-// register shutdown notification functions.
-//
 // Once Shutdown has been called on a server, it may not be reused;
 // future calls to methods such as Serve will return ErrServerClosed.
 func (srv *Server) Shutdown(ctx context.Context) error {
@@ -169,25 +321,47 @@ func (srv *Server) Shutdown(ctx context.Context) error {
 
 	srv.mu.Lock()
 	lnerr := srv.closeListenersLocked()
-	//srv.closeDoneChanLocked()
-	//for _, f := range srv.onShutdown {
-	//	go f()
-	//}
+	srv.closeDoneChanLocked()
 	srv.mu.Unlock()
 
 	ticker := time.NewTicker(shutdownPollInterval)
 	defer ticker.Stop()
-	return lnerr
-	//for {
-	//	if srv.closeIdleConns() {
-	//		return lnerr
-	//	}
-	//	select {
-	//	case <-ctx.Done():
-	//		return ctx.Err()
-	//	case <-ticker.C:
-	//	}
-	//}
+	for {
+		if srv.closeIdleConns() {
+			return lnerr
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// closeIdleConns closes all idle connections and reports whether the
+// server is quiescent.
+func (srv *Server) closeIdleConns() bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	quiescent := true
+	for c := range srv.activeConn {
+		st, unixSec := c.getState()
+		// Issue 22682: treat StateNew connections as if
+		// they're idle if we haven't read the first request's
+		// header in over 5 seconds.
+		if st == StateNew && unixSec < time.Now().Unix()-5 {
+			st = StateIdle
+		}
+		if st != StateIdle || unixSec == 0 {
+			// Assume unixSec == 0 means it's a very new
+			// connection, without state set yet.
+			quiescent = false
+			continue
+		}
+		_ = c.rwc.Close()
+		delete(srv.activeConn, c)
+	}
+	return quiescent
 }
 
 func (srv *Server) closeListenersLocked() error {
@@ -228,10 +402,53 @@ func (srv *Server) trackListener(ln *net.Listener, add bool) bool {
 	return true
 }
 
+func (srv *Server) trackConn(c *conn, add bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.activeConn == nil {
+		srv.activeConn = make(map[*conn]struct{})
+	}
+	if add {
+		srv.activeConn[c] = struct{}{}
+	} else {
+		delete(srv.activeConn, c)
+	}
+}
+
 func (srv *Server) shuttingDown() bool {
 	return atomic.LoadInt32(&srv.inShutdown) != 0
 }
 
+// idleTimeout returns the IdleTimeout, or ReadTimeout if IdleTimeout is zero.
+func (srv *Server) idleTimeout() time.Duration {
+	if srv.IdleTimeout != 0 {
+		return srv.IdleTimeout
+	}
+	return srv.ReadTimeout
+}
+
+// newDecoder returns a decoder for reading requests from r, with the server's limits.
+func (srv *Server) newDecoder(r io.Reader) *ttlv.Decoder {
+	dec := ttlv.NewDecoder(r)
+	dec.MaxMessageSize = limit(srv.MaxMessageSize, DefaultMaxMessageSize)
+	dec.MaxDepth = limit(srv.MaxDepth, DefaultMaxDepth)
+	dec.MaxItemsPerStructure = limit(srv.MaxItemsPerStructure, DefaultMaxItemsPerStructure)
+
+	return dec
+}
+
+// limit returns def if v is zero, and 0 (no limit) if v is negative.
+func limit(v, def int) int {
+	switch {
+	case v == 0:
+		return def
+	case v < 0:
+		return 0
+	default:
+		return v
+	}
+}
+
 type conn struct {
 	rwc        net.Conn
 	remoteAddr string
@@ -244,7 +461,12 @@ type conn struct {
 	bufr *bufio.Reader
 	dec  *ttlv.Decoder
 
-	server *Server
+	// curState holds the connection's ConnState in the low 8 bits, and the unix time
+	// of the last state change in the rest.
+	curState atomic.Uint64
+
+	server  *Server
+	session *Session
 }
 
 func (c *conn) close() {
@@ -252,6 +474,31 @@ func (c *conn) close() {
 	_ = c.rwc.Close()
 }
 
+func (c *conn) setState(nc net.Conn, state ConnState) {
+	srv := c.server
+	switch state {
+	case StateNew:
+		srv.trackConn(c, true)
+	case StateClosed:
+		srv.trackConn(c, false)
+		srv.releaseConn()
+	case StateActive, StateIdle:
+	}
+	if state > 0xff || state < 0 {
+		panic("internal error")
+	}
+	packedState := uint64(time.Now().Unix()<<8) | uint64(state)
+	c.curState.Store(packedState)
+	if hook := srv.ConnState; hook != nil {
+		hook(nc, state)
+	}
+}
+
+func (c *conn) getState() (state ConnState, unixSec int64) {
+	packedState := c.curState.Load()
+	return ConnState(packedState & 0xff), int64(packedState >> 8)
+}
+
 // Serve a new connection.
 func (c *conn) serve(ctx context.Context) {
 	ctx = flume.WithLogger(ctx, serverLog)
@@ -276,19 +523,24 @@ func (c *conn) serve(ctx context.Context) {
 			// c.server.logf("http: panic serving %v: %v\n%s", c.remoteAddr, err, buf)
 		}
 		cancelCtx()
-		// if !c.hijacked() {
 		c.close()
-		//	c.setState(c.rwc, StateClosed)
-		//}
+		c.setState(c.rwc, StateClosed)
+		if c.session != nil {
+			c.session.close()
+
+			if fn := c.server.OnClose; fn != nil {
+				fn(c.session)
+			}
+		}
 	}()
 
 	if tlsConn, ok := c.rwc.(*tls.Conn); ok {
-		//if d := c.server.ReadTimeout; d != 0 {
-		//	c.rwc.SetReadDeadline(time.Now().Add(d))
-		//}
-		//if d := c.server.WriteTimeout; d != 0 {
-		//	c.rwc.SetWriteDeadline(time.Now().Add(d))
-		//}
+		if d := c.server.ReadTimeout; d != 0 {
+			_ = c.rwc.SetReadDeadline(time.Now().Add(d))
+		}
+		if d := c.server.WriteTimeout; d != 0 {
+			_ = c.rwc.SetWriteDeadline(time.Now().Add(d))
+		}
 		if err := tlsConn.Handshake(); err != nil {
 			// TODO: logging support
 			fmt.Printf("kmip: TLS handshake error from %s: %v", c.rwc.RemoteAddr(), err)
@@ -297,164 +549,135 @@ func (c *conn) serve(ctx context.Context) {
 		}
 		c.tlsState = new(tls.ConnectionState)
 		*c.tlsState = tlsConn.ConnectionState()
-		//if proto := c.tlsState.NegotiatedProtocol; validNPN(proto) {
-		//	if fn := c.server.TLSNextProto[proto]; fn != nil {
-		//		h := initNPNRequest{tlsConn, serverHandler{c.server}}
-		//		fn(c.server, tlsConn, h)
-		//	}
-		//	return
-		//}
 	}
 
+	session := NewSession(c.remoteAddr, c.localAddr, c.tlsState)
+	ctx = WithSession(ctx, session)
+
+	if fn := c.server.OnConnect; fn != nil {
+		if err := fn(ctx, session); err != nil {
+			fmt.Printf("kmip: rejected connection from %s: %v\n", c.remoteAddr, err)
+			return
+		}
+	}
+
+	// set after OnConnect succeeds, so OnClose is only called for accepted sessions
+	c.session = session
+
 	// TODO: do we really need instance pooling here?  We expect KMIP connections to be long lasting
-	c.dec = ttlv.NewDecoder(c.rwc)
 	c.bufr = bufio.NewReader(c.rwc)
+	// the decoder shares bufr (bufio.NewReader returns it as is), so peeking bufr
+	// for the next request doesn't lose bytes buffered by the decoder
+	c.dec = c.server.newDecoder(c.bufr)
 	// c.bufw = newBufioWriterSize(checkConnErrorWriter{c}, 4<<10)
 
 	for {
+		// wait for the next request.  The connection is active once the first byte arrives.
+		_ = c.rwc.SetReadDeadline(deadline(c.server.idleTimeout()))
+		if _, err := c.bufr.Peek(1); err != nil {
+			c.logReadError(err)
+			return
+		}
+
+		c.setState(c.rwc, StateActive)
+		_ = c.rwc.SetReadDeadline(deadline(c.server.ReadTimeout))
+
 		w, err := c.readRequest(ctx)
-		//if c.r.remain != c.server.initialReadLimitSize() {
-		// If we read any bytes off the wire, we're active.
-		//c.setState(c.rwc, StateActive)
-		//}
 		if err != nil {
-			if merry.Is(err, io.EOF) {
-				fmt.Println("client closed connection")
+			if isCommonNetReadError(err) || isLimitError(err) {
+				// for limit errors, the rest of the request is still on the wire, so the
+				// connection can't be used anymore
+				c.logReadError(err)
 				return
 			}
 
 			// TODO: do something with this error
 			panic(err)
-			//const errorHeaders= "\r\nContent-Type: text/plain; charset=utf-8\r\nConnection: close\r\n\r\n"
-			//
-			//if err == errTooLarge {
-			//	// Their HTTP client may or may not be
-			//	// able to read this if we're
-			//	// responding to them and hanging up
-			//	// while they're still writing their
-			//	// request. Undefined behavior.
-			//	const publicErr= "431 Request Header Fields Too Large"
-			//	fmt.Fprintf(c.rwc, "HTTP/1.1 "+publicErr+errorHeaders+publicErr)
-			//	c.closeWriteAndWait()
-			//	return
-			//}
-			//if isCommonNetReadError(err) {
-			//	return // don't reply
-			//}
-			//
-			//publicErr := "400 Bad Request"
-			//if v, ok := err.(badRequestError); ok {
-			//	publicErr = publicErr + ": " + string(v)
-			//}
-			//
-			//fmt.Fprintf(c.rwc, "HTTP/1.1 "+publicErr+errorHeaders+publicErr)
-			//return
 		}
 
-		// Expect 100 Continue support
-		//req := w.req
-		//if req.expectsContinue() {
-		//	if req.ProtoAtLeast(1, 1) && req.ContentLength != 0 {
-		//		// Wrap the Body reader with one that replies on the connection
-		//		req.Body = &expectContinueReader{readCloser: req.Body, resp: w}
-		//	}
-		//} else if req.Header.get("Expect") != "" {
-		//	w.sendExpectationFailed()
-		//	return
-		//}
-
-		// c.curReq.Store(w)
-
-		//if requestBodyRemains(req.Body) {
-		//	registerOnHitEOF(req.Body, w.conn.r.startBackgroundRead)
-		//} else {
-		//	w.conn.r.startBackgroundRead()
-		//}
-
-		// HTTP cannot have multiple simultaneous active requests.[*]
 		// Until the server replies to this request, it can't read another,
 		// so we might as well run the handler in this goroutine.
-		// [*] Not strictly true: HTTP pipelining. We could let them all process
-		// in parallel even if their responses need to be serialized.
-		// But we're not going to implement HTTP pipelining because it
-		// was never deployed in the wild and the answer is HTTP/2.
+
+		_ = c.rwc.SetWriteDeadline(deadline(c.server.WriteTimeout))
 
 		h := c.server.Handler
 		if h == nil {
 			h = DefaultProtocolHandler
 		}
 
-		// var resp ResponseMessage
-		// err = c.server.MessageHandler.Handle(ctx, w, &resp)
 		// TODO: this cancelCtx() was created at the connection level, not the request level.  Need to
 		// figure out how to handle connection vs request timeouts and cancels.
-		// cancelCtx()
 
 		// TODO: use recycled buffered writer
 		writer := bufio.NewWriter(c.rwc)
 		h.ServeKMIP(ctx, w, writer)
-		err = writer.Flush()
-		if err != nil {
-			// TODO: handle error
-			panic(err)
+		if err := writer.Flush(); err != nil {
+			fmt.Printf("kmip: error writing response to %s: %v\n", c.remoteAddr, err)
+			return
 		}
 
-		//serverHandler{c.server}.ServeHTTP(w, w.req)
-		//w.cancelCtx()
-		//if c.hijacked() {
-		//	return
-		//}
-		//w.finishRequest()
-		//if !w.shouldReuseConnection() {
-		//	if w.requestBodyLimitHit || w.closedRequestBodyEarly() {
-		//		c.closeWriteAndWait()
-		//	}
-		//	return
-		//}
-		//c.setState(c.rwc, StateIdle)
-		//c.curReq.Store((*response)(nil))
-
-		//if !w.conn.server.doKeepAlives() {
-		//	// We're in shutdown mode. We might've replied
-		//	// to the user without "Connection: close" and
-		//	// they might think they can send another
-		//	// request, but such is life with HTTP/1.1.
-		//	return
-		//}
-		//
-		//if d := c.server.idleTimeout(); d != 0 {
-		//	c.rwc.SetReadDeadline(time.Now().Add(d))
-		//	if _, err := c.bufr.Peek(4); err != nil {
-		//		return
-		//	}
-		//}
-		//c.rwc.SetReadDeadline(time.Time{})
+		c.setState(c.rwc, StateIdle)
+
+		if c.server.shuttingDown() {
+			// the server is waiting for this connection to go idle, so don't wait
+			// for another request
+			return
+		}
+	}
+}
+
+// deadline returns the time d from now, or the zero time (no deadline) if d is zero.
+func deadline(d time.Duration) time.Time {
+	if d == 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(d)
+}
+
+// logReadError logs the error which ended reading requests from the connection.
+func (c *conn) logReadError(err error) {
+	var ne net.Error
+
+	switch {
+	case merry.Is(err, io.EOF):
+		fmt.Println("client closed connection")
+	case errors.As(err, &ne) && ne.Timeout():
+		fmt.Printf("kmip: closing connection from %s: timed out\n", c.remoteAddr)
+	case errors.Is(err, net.ErrClosed):
+		// closed by Server.Close or Server.Shutdown
+	default:
+		fmt.Printf("kmip: closing connection from %s: %v\n", c.remoteAddr, err)
 	}
 }
 
+// isCommonNetReadError returns true if err is a network error from which the
+// connection can't recover, like the client closing the connection, or a timeout.
+func isCommonNetReadError(err error) bool {
+	if merry.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed) {
+		return true
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	var oe *net.OpError
+	return errors.As(err, &oe) && oe.Op == "read"
+}
+
+// isLimitError returns true if err is caused by a request exceeding the server's limits.
+func isLimitError(err error) bool {
+	return merry.Is(err, ttlv.ErrMessageTooLarge) ||
+		merry.Is(err, ttlv.ErrMaxDepthExceeded) ||
+		merry.Is(err, ttlv.ErrTooManyItems)
+}
+
 // Read next request from connection.
 func (c *conn) readRequest(ctx context.Context) (w *Request, err error) {
 	//if c.hijacked() {
 	//	return nil, ErrHijacked
 	//}
 
-	//var (
-	//	wholeReqDeadline time.Time // or zero if none
-	//	hdrDeadline      time.Time // or zero if none
-	//)
-	//t0 := time.Now()
-	//if d := c.server.readHeaderTimeout(); d != 0 {
-	//	hdrDeadline = t0.Add(d)
-	//}
-	//if d := c.server.ReadTimeout; d != 0 {
-	//	wholeReqDeadline = t0.Add(d)
-	//}
-	//c.rwc.SetReadDeadline(hdrDeadline)
-	//if d := c.server.WriteTimeout; d != 0 {
-	//	defer func() {
-	//		c.rwc.SetWriteDeadline(time.Now().Add(d))
-	//	}()
-	//}
+	// read deadlines are set by serve, before the request is read
 
 	//c.r.setReadLimit(c.server.initialReadLimitSize())
 	//if c.lastMethod == "POST" {
@@ -478,15 +701,11 @@ func (c *conn) readRequest(ctx context.Context) (w *Request, err error) {
 		RemoteAddr: c.remoteAddr,
 		LocalAddr:  c.localAddr,
 		TLS:        c.tlsState,
+		Session:    c.session,
 	}
 
 	// c.r.setInfiniteReadLimit()
 
-	// Adjust the read deadline if necessary.
-	//if !hdrDeadline.Equal(wholeReqDeadline) {
-	//	c.rwc.SetReadDeadline(wholeReqDeadline)
-	//}
-
 	return req, nil
 }
 
@@ -503,9 +722,19 @@ type Request struct {
 	RemoteAddr string
 	LocalAddr  string
 
+	// Session is the Session of the connection this request was received on.  If nil,
+	// the StandardProtocolHandler sets it to the Session carried by the context, if any.
+	Session *Session
+
+	// Principal is the identity of the client, returned by the StandardProtocolHandler's
+	// Authenticator.  It is nil if there is no Authenticator.
+	Principal interface{}
+
 	IDPlaceholder string
 
 	decoder *ttlv.Decoder
+	// invalidItems holds the errors of the batch items whose payloads couldn't be decoded
+	invalidItems map[*RequestBatchItem]error
 }
 
 // coerceToTTLV attempts to coerce an interface value to TTLV.
@@ -526,8 +755,8 @@ func coerceToTTLV(v interface{}) (ttlv.TTLV, error) {
 
 // Unmarshal unmarshals ttlv into structures.  Handlers should prefer this
 // method over than their own Decoders or Unmarshal().  This method
-// enforces rules about whether extra fields are allowed, and reuses
-// buffers for efficiency.
+// enforces rules about whether extra fields are allowed, and which values
+// the negotiated protocol version defines, and reuses buffers for efficiency.
 func (r *Request) Unmarshal(ttlv ttlv.TTLV, into interface{}) error {
 	if len(ttlv) == 0 {
 		return nil
@@ -540,7 +769,20 @@ func (r *Request) DecodePayload(v interface{}) error {
 	if r.CurrentItem == nil {
 		return nil
 	}
-	ttlvVal, err := coerceToTTLV(r.CurrentItem.RequestPayload)
+
+	if err := r.invalidItems[r.CurrentItem]; err != nil {
+		return err
+	}
+
+	// the payload may have already been decoded into v's type by a TypeResolver, with
+	// the same rules as Unmarshal
+	payload := r.CurrentItem.RequestPayload
+	if p, pv := reflect.ValueOf(payload), reflect.ValueOf(v); p.Kind() == reflect.Ptr && !p.IsNil() && p.Type() == pv.Type() && !pv.IsNil() {
+		pv.Elem().Set(p.Elem())
+		return nil
+	}
+
+	ttlvVal, err := coerceToTTLV(payload)
 	if err != nil {
 		return err
 	}
@@ -626,13 +868,41 @@ var DefaultOperationMux = &OperationMux{}
 // level tasks like version negotiation and correlation values.
 //
 // It delegates handling of the request to a MessageHandler.
+//
+// Requests and responses are checked against the view of ttlv.DefaultRegistry scoped to
+// the negotiated protocol version.  Batch items with values the version doesn't define fail
+// with ResultReasonInvalidMessage, and responses with ResultReasonGeneralFailure.
 type StandardProtocolHandler struct {
 	ProtocolVersion ProtocolVersion
 	MessageHandler  MessageHandler
 
+	// LogTraffic logs each request and response at debug level.  The values of tags
+	// marked sensitive in ttlv.DefaultRegistry are redacted.
 	LogTraffic bool
+
+	// TypeResolver, if set, is used to decode the interface{} fields of request
+	// messages, like RequestPayload.  Set it to ResolveType to decode payloads into
+	// the payload types of their operations.  Request.DecodePayload copies payloads
+	// which already have the requested type.
+	//
+	// The fields are decoded once the protocol version is negotiated, so extra values
+	// are rejected as they are by Request.Unmarshal.  Batch items whose payloads can't
+	// be decoded fail on their own, without failing the rest of the message.
+	TypeResolver ttlv.TypeResolver
+
+	// Authenticator, if set, authenticates each request before it is passed to the
+	// MessageHandler.  Requests which fail authentication aren't handled.
+	Authenticator Authenticator
+
+	mu         sync.Mutex
+	middleware []MessageMiddleware
+	// chain is the MessageHandler wrapped in the middleware.  It is built by the first
+	// request after Use.
+	chain MessageHandler
 }
 
+// parseMessage decodes the request message, leaving its interface{} fields, like the
+// payloads, as TTLV.  They are decoded by resolveMessage, once the version is negotiated.
 func (h *StandardProtocolHandler) parseMessage(ctx context.Context, req *Request) error {
 	ttlvV := req.TTLV
 	if err := ttlvV.Valid(); err != nil {
@@ -644,7 +914,8 @@ func (h *StandardProtocolHandler) parseMessage(ctx context.Context, req *Request
 	}
 
 	var message RequestMessage
-	err := ttlv.Unmarshal(ttlvV, &message)
+
+	err := ttlv.NewDecoder(nil).DecodeValue(&message, ttlvV)
 	if err != nil {
 		return merry.Prepend(err, "failed to parse message")
 	}
@@ -654,12 +925,72 @@ func (h *StandardProtocolHandler) parseMessage(ctx context.Context, req *Request
 	return nil
 }
 
+// resolveMessage checks the request message against the View of the request's decoder,
+// and decodes its interface{} fields with the TypeResolver and the decoder, which apply
+// the rules of the negotiated version.  The batch items which fail are recorded in
+// invalidItems, and keep their TTLV payloads.
+func (h *StandardProtocolHandler) resolveMessage(req *Request) error {
+	var header RequestHeader
+	if err := h.resolveValue(req, &header, findValue(req.TTLV, kmip14.TagRequestHeader)); err != nil {
+		return merry.Prepend(err, "failed to parse message")
+	}
+
+	if h.TypeResolver != nil {
+		req.Message.RequestHeader = header
+	}
+
+	i := 0
+
+	for v := req.TTLV.ValueStructure(); len(v) > 0; v = v.Next() {
+		if v.Tag() != kmip14.TagBatchItem {
+			continue
+		}
+
+		var item RequestBatchItem
+		if err := h.resolveValue(req, &item, v); err != nil {
+			if req.invalidItems == nil {
+				req.invalidItems = map[*RequestBatchItem]error{}
+			}
+
+			err = merry.Prepend(err, "failed to parse payload")
+			req.invalidItems[&req.Message.BatchItem[i]] = WithResultReason(err, kmip14.ResultReasonInvalidMessage)
+		} else if h.TypeResolver != nil {
+			req.Message.BatchItem[i] = item
+		}
+
+		i++
+	}
+
+	return nil
+}
+
+// resolveValue checks the value against the View of the request's decoder, and decodes it
+// into ptr, if there is a TypeResolver.  ptr is unchanged if the value is invalid.
+func (h *StandardProtocolHandler) resolveValue(req *Request, ptr interface{}, v ttlv.TTLV) error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	if err := req.decoder.View.Validate(v[:v.FullLen()]); err != nil {
+		return err
+	}
+
+	if h.TypeResolver == nil {
+		return nil
+	}
+
+	return req.decoder.DecodeValue(ptr, v)
+}
+
 var responsePool = sync.Pool{}
 
+// maxPooledResponseSize is the largest encoded response whose buffer is kept by
+// the pool of responses.
+const maxPooledResponseSize = 64 * 1024
+
 type Response struct {
 	ResponseMessage
-	buf bytes.Buffer
-	enc *ttlv.Encoder
+	buf []byte
 }
 
 func newResponse() *Response {
@@ -669,29 +1000,72 @@ func newResponse() *Response {
 		r.reset()
 		return r
 	}
-	r := Response{}
-	r.enc = ttlv.NewEncoder(&r.buf)
-	return &r
+	return &Response{}
 }
 
 func releaseResponse(r *Response) {
+	if cap(r.buf) > maxPooledResponseSize {
+		return
+	}
 	responsePool.Put(r)
 }
 
 func (r *Response) reset() {
-	r.BatchItem = nil
-	r.ResponseMessage = ResponseMessage{}
-	r.buf.Reset()
+	// reuse the batch item slice, but don't hold on to the previous items
+	clear(r.BatchItem)
+	r.ResponseMessage = ResponseMessage{BatchItem: r.BatchItem[:0]}
+	r.buf = r.buf[:0]
 }
 
-func (r *Response) Bytes() []byte {
-	r.buf.Reset()
-	err := r.enc.Encode(&r.ResponseMessage)
+// encode encodes the response message, like Bytes, checking it against the View of the
+// negotiated version.  Batch items which fail to encode, or with values the View doesn't
+// define, are replaced with failures, so the client isn't sent values its version of the
+// spec doesn't define.  A nil View allows every value.
+func (r *Response) encode(view *ttlv.View) []byte {
+	buf, err := ttlv.MarshalAppend(r.buf[:0], &r.ResponseMessage)
+	if err == nil {
+		r.buf = buf
+		if view == nil || view.Validate(buf) == nil {
+			return buf
+		}
+	}
+
+	for i := range r.BatchItem {
+		bi := &r.BatchItem[i]
+
+		b, err := ttlv.Marshal(ttlv.Value{Tag: kmip14.TagBatchItem, Value: bi})
+		if err == nil && view != nil {
+			err = view.Validate(b)
+		}
+
+		if err != nil {
+			reason, _ := failure(err, kmip14.ResultReasonGeneralFailure)
+			*bi = ResponseBatchItem{
+				Operation:         bi.Operation,
+				UniqueBatchItemID: bi.UniqueBatchItemID,
+				ResultStatus:      kmip14.ResultStatusOperationFailed,
+				ResultReason:      reason,
+				ResultMessage:     err.Error(),
+			}
+		}
+	}
+
+	buf, err = ttlv.MarshalAppend(r.buf[:0], &r.ResponseMessage)
 	if err != nil {
+		// the header is set by the server, so this is a bug
 		panic(err)
 	}
 
-	return r.buf.Bytes()
+	r.buf = buf
+
+	return r.buf
+}
+
+// Bytes encodes the response message.  Batch items which fail to encode, like payloads
+// missing a required value, are replaced with failures.  The returned slice is reused
+// by the next call, and once the response is released.
+func (r *Response) Bytes() []byte {
+	return r.encode(nil)
 }
 
 func (r *Response) errorResponse(reason kmip14.ResultReason, msg string) {
@@ -702,6 +1076,7 @@ func (r *Response) errorResponse(reason kmip14.ResultReason, msg string) {
 			ResultMessage: msg,
 		},
 	}
+	r.ResponseHeader.BatchCount = len(r.BatchItem)
 }
 
 func (h *StandardProtocolHandler) handleRequest(ctx context.Context, req *Request, resp *Response) (logger flume.Logger) {
@@ -720,8 +1095,10 @@ func (h *StandardProtocolHandler) handleRequest(ctx context.Context, req *Reques
 	resp.ResponseHeader.BatchCount = len(resp.BatchItem)
 	resp.ResponseHeader.ServerCorrelationValue = scv
 
+	// ServeKMIP writes the encoded response, so responses returned early must be encoded too
 	if err := h.parseMessage(ctx, req); err != nil {
 		resp.errorResponse(kmip14.ResultReasonInvalidMessage, err.Error())
+		resp.Bytes()
 		return
 	}
 
@@ -736,22 +1113,49 @@ func (h *StandardProtocolHandler) handleRequest(ctx context.Context, req *Reques
 	if clientMajorVersion != h.ProtocolVersion.ProtocolVersionMajor {
 		resp.errorResponse(kmip14.ResultReasonInvalidMessage,
 			fmt.Sprintf("mismatched protocol versions, client: %d, server: %d", clientMajorVersion, h.ProtocolVersion.ProtocolVersionMajor))
+		resp.Bytes()
 		return
 	}
 
+	if req.Session == nil {
+		req.Session = SessionFromContext(ctx)
+	}
+
+	version := negotiateProtocolVersion(req.Message.RequestHeader.ProtocolVersion, h.ProtocolVersion)
+	if req.Session != nil {
+		req.Session.SetProtocolVersion(version)
+	}
+
+	if h.Authenticator != nil {
+		if err := h.authenticate(ctx, req); err != nil {
+			logger.Info("authentication failed", "err", err)
+			resp.errorResponse(failure(err, kmip14.ResultReasonAuthenticationNotSuccessful))
+			resp.Bytes()
+			return
+		}
+	}
+
 	// set a flag hinting to handlers that extra fields should not be tolerated when
 	// unmarshaling payloads.  According to spec, if server and client protocol version
-	// minor versions match, then extra fields should cause an error.  Not sure how to enforce
-	// this in this higher level handler, since we (the protocol/message handlers) don't unmarshal the payload.
-	// That's done by a particular item handler.
+	// minor versions match, then extra fields should cause an error.  Payloads decoded
+	// by the TypeResolver are checked here.  Otherwise, they're checked when the item
+	// handler unmarshals them.
 	req.DisallowExtraValues = req.Message.RequestHeader.ProtocolVersion.ProtocolVersionMinor == h.ProtocolVersion.ProtocolVersionMinor
 	req.decoder = ttlv.NewDecoder(nil)
 	req.decoder.DisallowExtraValues = req.DisallowExtraValues
+	req.decoder.TypeResolver = h.TypeResolver
+	req.decoder.View = version.view()
 
-	h.MessageHandler.HandleMessage(ctx, req, resp)
+	if err := h.resolveMessage(req); err != nil {
+		resp.errorResponse(kmip14.ResultReasonInvalidMessage, err.Error())
+		resp.Bytes()
+		return
+	}
+
+	h.messageHandler().HandleMessage(ctx, req, resp)
 	resp.ResponseHeader.BatchCount = len(resp.BatchItem)
 
-	respTTLV := resp.Bytes()
+	respTTLV := resp.encode(req.decoder.View)
 
 	if req.Message.RequestHeader.MaximumResponseSize > 0 && len(respTTLV) > req.Message.RequestHeader.MaximumResponseSize {
 		// new error resp
@@ -774,10 +1178,11 @@ func (h *StandardProtocolHandler) ServeKMIP(ctx context.Context, req *Request, w
 	if h.LogTraffic {
 		ttlvV := resp.Bytes()
 
+		// String() redacts sensitive values, like key material and passwords
 		logger.Debug("traffic log", "request", req.TTLV.String(), "response", ttlv.TTLV(ttlvV).String())
 		_, err = writer.Write(ttlvV)
 	} else {
-		_, err = resp.buf.WriteTo(writer)
+		_, err = writer.Write(resp.buf)
 	}
 	if err != nil {
 		panic(err)
@@ -786,6 +1191,43 @@ func (h *StandardProtocolHandler) ServeKMIP(ctx context.Context, req *Request, w
 	releaseResponse(resp)
 }
 
+// authenticate passes the request's credentials to the Authenticator, and stores the
+// principal in the request and session.  If the session's principal was authenticated
+// with the same credentials, it is used without calling the Authenticator.
+func (h *StandardProtocolHandler) authenticate(ctx context.Context, req *Request) error {
+	// only a digest of the credentials is kept, so the session doesn't hold on to passwords
+	var credential [sha256.Size]byte
+
+	header := findValue(req.TTLV, kmip14.TagRequestHeader)
+	if auth := findValue(header, kmip14.TagAuthentication); auth != nil {
+		credential = sha256.Sum256(auth[:auth.FullLen()])
+	}
+
+	if req.Session != nil {
+		if principal, ok := req.Session.authenticatedPrincipal(credential); ok {
+			req.Principal = principal
+			return nil
+		}
+	}
+
+	creds, err := decodeCredentials(req.Message.RequestHeader.Authentication)
+	if err != nil {
+		return err
+	}
+
+	principal, err := h.Authenticator.Authenticate(ctx, req, creds)
+	if err != nil {
+		return err
+	}
+
+	req.Principal = principal
+	if req.Session != nil {
+		req.Session.setAuthenticatedPrincipal(principal, credential)
+	}
+
+	return nil
+}
+
 func (r *ResponseMessage) addFailure(reason kmip14.ResultReason, msg string) {
 	if msg == "" {
 		msg = reason.String()
@@ -803,10 +1245,20 @@ func (r *ResponseMessage) addFailure(reason kmip14.ResultReason, msg string) {
 // ErrorHandler, which converts it into a error *ResponseBatchItem.  OperationMux handles correlating
 // items in the request to items in the response.
 type OperationMux struct {
-	mu       sync.RWMutex
-	handlers map[kmip14.Operation]ItemHandler
+	mu         sync.RWMutex
+	handlers   map[kmip14.Operation]ItemHandler
+	middleware []ItemMiddleware
+	// wrapped holds the handlers wrapped in the middleware
+	wrapped map[kmip14.Operation]ItemHandler
 	// ErrorHandler defaults to the DefaultErrorHandler.
 	ErrorHandler ErrorHandler
+
+	// Authorizer, if set, decides whether each batch item may be performed, before
+	// it is routed to its ItemHandler.
+	Authorizer Authorizer
+	// ObjectAttributes, if set, returns the attributes of the object with the unique
+	// identifier, so the Authorizer can check the target object of an operation.
+	ObjectAttributes func(ctx context.Context, uniqueIdentifier string) ([]Attribute, error)
 }
 
 // ErrorHandler converts a golang error into a *ResponseBatchItem (which should hold information
@@ -837,6 +1289,23 @@ var DefaultErrorHandler = ErrorHandlerFunc(func(err error) *ResponseBatchItem {
 	return newFailedResponseBatchItem(reason, msg)
 })
 
+// failure returns the result reason of err, or def if it has none, and the user message
+// of err, or the reason if it has none.  Only user messages are returned to clients, so
+// the details of authentication and authorization failures aren't leaked.
+func failure(err error, def kmip14.ResultReason) (kmip14.ResultReason, string) {
+	reason := GetResultReason(err)
+	if reason == kmip14.ResultReason(0) {
+		reason = def
+	}
+
+	msg := merry.UserMessage(err)
+	if msg == "" {
+		msg = reason.String()
+	}
+
+	return reason, msg
+}
+
 func newFailedResponseBatchItem(reason kmip14.ResultReason, msg string) *ResponseBatchItem {
 	return &ResponseBatchItem{
 		ResultStatus:  kmip14.ResultStatusOperationFailed,
@@ -852,6 +1321,17 @@ func (m *OperationMux) bi(ctx context.Context, req *Request, reqItem *RequestBat
 		return newFailedResponseBatchItem(kmip14.ResultReasonOperationNotSupported, "")
 	}
 
+	if err := req.invalidItems[reqItem]; err != nil {
+		return newFailedResponseBatchItem(kmip14.ResultReasonInvalidMessage, err.Error())
+	}
+
+	if m.Authorizer != nil {
+		if err := m.authorize(ctx, req); err != nil {
+			flume.FromContext(ctx).Info("operation denied", "operation", reqItem.Operation, "err", err)
+			return newFailedResponseBatchItem(failure(err, kmip14.ResultReasonPermissionDenied))
+		}
+	}
+
 	resp, err := h.HandleItem(ctx, req)
 	if err != nil {
 		eh := m.ErrorHandler
@@ -868,6 +1348,23 @@ func (m *OperationMux) bi(ctx context.Context, req *Request, reqItem *RequestBat
 	return resp
 }
 
+// authorize passes each access of the current item to the Authorizer.  The item is
+// only allowed if all are.
+func (m *OperationMux) authorize(ctx context.Context, req *Request) error {
+	accesses, err := m.accesses(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for _, access := range accesses {
+		if err := m.Authorizer.Authorize(ctx, req, access); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *OperationMux) HandleMessage(ctx context.Context, req *Request, resp *Response) {
 	for i := range req.Message.BatchItem {
 		reqItem := &req.Message.BatchItem[i]
@@ -884,16 +1381,19 @@ func (m *OperationMux) Handle(op kmip14.Operation, handler ItemHandler) {
 
 	if m.handlers == nil {
 		m.handlers = map[kmip14.Operation]ItemHandler{}
+		m.wrapped = map[kmip14.Operation]ItemHandler{}
 	}
 
 	m.handlers[op] = handler
+	m.wrapped[op] = m.wrap(handler)
 }
 
+// handlerForOp returns the handler for the operation, wrapped in the middleware.
 func (m *OperationMux) handlerForOp(op kmip14.Operation) ItemHandler {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return m.handlers[op]
+	return m.wrapped[op]
 }
 
 func (m *OperationMux) missingHandler(ctx context.Context, req *Request, resp *ResponseMessage) error {
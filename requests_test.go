@@ -0,0 +1,626 @@
+package kmip
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/kmip20/names"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProtocolHandler returns a handler which responds to Get requests with
+// testGetResponsePayload.
+func testProtocolHandler() *StandardProtocolHandler {
+	mux := &OperationMux{}
+	mux.Handle(kmip14.OperationGet, ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+		var p GetRequestPayload
+		if err := req.DecodePayload(&p); err != nil {
+			return nil, err
+		}
+
+		payload := testGetResponsePayload()
+		payload.UniqueIdentifier = p.UniqueIdentifier
+
+		return &ResponseBatchItem{
+			ResultStatus:    kmip14.ResultStatusSuccess,
+			ResponsePayload: &payload,
+		}, nil
+	}))
+
+	return &StandardProtocolHandler{
+		MessageHandler: mux,
+		ProtocolVersion: ProtocolVersion{
+			ProtocolVersionMajor: 1,
+			ProtocolVersionMinor: 4,
+		},
+	}
+}
+
+func TestStandardProtocolHandler_ServeKMIP(t *testing.T) {
+	h := testProtocolHandler()
+
+	req, err := ttlv.Marshal(testRequestMessage())
+	require.NoError(t, err)
+
+	// responses are pooled, so serve a few requests, and check they don't share buffers
+	var responses []*bytes.Buffer
+
+	for i := 0; i < 3; i++ {
+		var buf bytes.Buffer
+
+		h.ServeKMIP(context.Background(), &Request{TTLV: req}, &buf)
+		responses = append(responses, &buf)
+	}
+
+	for _, buf := range responses {
+		var resp ResponseMessage
+		require.NoError(t, ttlv.Unmarshal(buf.Bytes(), &resp))
+
+		require.Len(t, resp.BatchItem, 1)
+		assert.Equal(t, 1, resp.ResponseHeader.BatchCount)
+
+		bi := resp.BatchItem[0]
+		assert.Equal(t, kmip14.OperationGet, bi.Operation)
+		assert.Equal(t, kmip14.ResultStatusSuccess, bi.ResultStatus, bi.ResultMessage)
+		assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, bi.UniqueBatchItemID)
+
+		var p GetResponsePayload
+		require.NoError(t, ttlv.Unmarshal(bi.ResponsePayload.(ttlv.TTLV), &p)) //nolint:forcetypeassert
+		assert.Equal(t, testGetResponsePayload(), p)
+	}
+}
+
+func TestStandardProtocolHandler_TypeResolver(t *testing.T) {
+	h := testProtocolHandler()
+	h.TypeResolver = ResolveType
+
+	var header RequestHeader
+
+	mux := h.MessageHandler
+	h.MessageHandler = MessageHandlerFunc(func(ctx context.Context, req *Request, resp *Response) {
+		header = req.Message.RequestHeader
+		mux.HandleMessage(ctx, req, resp)
+	})
+
+	// the second item's payload has an extra value, which the 1.4 handler doesn't allow
+	msg := testRequestMessage()
+	msg.RequestHeader.BatchCount = 2
+	msg.BatchItem = append(msg.BatchItem, RequestBatchItem{
+		Operation:         kmip14.OperationGet,
+		UniqueBatchItemID: []byte{0x05},
+		RequestPayload: ttlv.Value{Tag: kmip14.TagRequestPayload, Value: ttlv.Values{
+			{Tag: kmip14.TagUniqueIdentifier, Value: "key1"},
+			{Tag: kmip14.TagComment, Value: "extra"},
+		}},
+	})
+
+	req, err := ttlv.Marshal(msg)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	h.ServeKMIP(context.Background(), &Request{TTLV: req}, &buf)
+
+	var resp ResponseMessage
+	require.NoError(t, ttlv.Unmarshal(buf.Bytes(), &resp))
+	require.Len(t, resp.BatchItem, 2)
+	assert.Equal(t, msg.RequestHeader, header)
+
+	// only the item with the extra value fails
+	assert.Equal(t, kmip14.ResultStatusSuccess, resp.BatchItem[0].ResultStatus, resp.BatchItem[0].ResultMessage)
+
+	bi := resp.BatchItem[1]
+	assert.Equal(t, kmip14.ResultStatusOperationFailed, bi.ResultStatus)
+	assert.Equal(t, kmip14.ResultReasonInvalidMessage, bi.ResultReason)
+	assert.Equal(t, []byte{0x05}, bi.UniqueBatchItemID)
+	assert.Contains(t, bi.ResultMessage, "failed to parse payload")
+
+	// a 1.3 client may send extra values
+	msg.RequestHeader.ProtocolVersion.ProtocolVersionMinor = 3
+	req, err = ttlv.Marshal(msg)
+	require.NoError(t, err)
+
+	buf.Reset()
+	h.ServeKMIP(context.Background(), &Request{TTLV: req}, &buf)
+
+	resp = ResponseMessage{}
+	require.NoError(t, ttlv.Unmarshal(buf.Bytes(), &resp))
+	require.Len(t, resp.BatchItem, 2)
+
+	for _, bi := range resp.BatchItem {
+		assert.Equal(t, kmip14.ResultStatusSuccess, bi.ResultStatus, bi.ResultMessage)
+	}
+
+	// an extra value in the header of a 1.4 client fails the whole message
+	req, err = ttlv.Marshal(ttlv.Value{Tag: kmip14.TagRequestMessage, Value: ttlv.Values{
+		{Tag: kmip14.TagRequestHeader, Value: ttlv.Values{
+			{Tag: kmip14.TagProtocolVersion, Value: ProtocolVersion{ProtocolVersionMajor: 1, ProtocolVersionMinor: 4}},
+			{Tag: kmip14.TagComment, Value: "extra"},
+			{Tag: kmip14.TagBatchCount, Value: 1},
+		}},
+		{Tag: kmip14.TagBatchItem, Value: &msg.BatchItem[0]},
+	}})
+	require.NoError(t, err)
+
+	buf.Reset()
+	h.ServeKMIP(context.Background(), &Request{TTLV: req}, &buf)
+
+	resp = ResponseMessage{}
+	require.NoError(t, ttlv.Unmarshal(buf.Bytes(), &resp))
+	require.Len(t, resp.BatchItem, 1)
+	assert.Equal(t, kmip14.ResultStatusOperationFailed, resp.BatchItem[0].ResultStatus)
+	assert.Equal(t, kmip14.ResultReasonInvalidMessage, resp.BatchItem[0].ResultReason)
+	assert.Contains(t, resp.BatchItem[0].ResultMessage, "failed to parse message: kmip: error unmarshaling RequestHeader")
+}
+
+func TestStandardProtocolHandler_version(t *testing.T) {
+	h := testProtocolHandler()
+
+	// the request and response are checked against the negotiated version, 1.4, which
+	// doesn't define the Protection Level tag, or the Certificate Request object type
+	mux := h.MessageHandler.(*OperationMux) //nolint:forcetypeassert
+	mux.Handle(kmip14.OperationQuery, ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+		return &ResponseBatchItem{
+			ResultStatus: kmip14.ResultStatusSuccess,
+			ResponsePayload: ttlv.Value{Tag: kmip14.TagResponsePayload, Value: ttlv.Values{
+				{Tag: kmip14.TagObjectType, Value: names.ObjectTypeCertificateRequest},
+			}},
+		}, nil
+	}))
+
+	msg := testRequestMessage()
+	msg.RequestHeader.BatchCount = 3
+	msg.BatchItem = append(msg.BatchItem,
+		RequestBatchItem{
+			Operation: kmip14.OperationGet,
+			RequestPayload: ttlv.Value{Tag: kmip14.TagRequestPayload, Value: ttlv.Values{
+				{Tag: kmip14.TagUniqueIdentifier, Value: "key1"},
+				{Tag: names.TagProtectionLevel, Value: names.ProtectionLevelHigh},
+			}},
+		},
+		RequestBatchItem{Operation: kmip14.OperationQuery},
+	)
+
+	req, err := ttlv.Marshal(msg)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	h.ServeKMIP(context.Background(), &Request{TTLV: req}, &buf)
+
+	var resp ResponseMessage
+	require.NoError(t, ttlv.Unmarshal(buf.Bytes(), &resp))
+	require.Len(t, resp.BatchItem, 3)
+
+	assert.Equal(t, kmip14.ResultStatusSuccess, resp.BatchItem[0].ResultStatus, resp.BatchItem[0].ResultMessage)
+
+	assert.Equal(t, kmip14.ResultReasonInvalidMessage, resp.BatchItem[1].ResultReason)
+	assert.Contains(t, resp.BatchItem[1].ResultMessage, "tag ProtectionLevel is not defined in 1.4")
+
+	assert.Equal(t, kmip14.OperationQuery, resp.BatchItem[2].Operation)
+	assert.Equal(t, kmip14.ResultReasonGeneralFailure, resp.BatchItem[2].ResultReason)
+	assert.Contains(t, resp.BatchItem[2].ResultMessage, "is not defined in 1.4")
+	assert.Nil(t, resp.BatchItem[2].ResponsePayload)
+
+	// a header the negotiated version doesn't define fails the whole message
+	req, err = ttlv.Marshal(ttlv.Value{Tag: kmip14.TagRequestMessage, Value: ttlv.Values{
+		{Tag: kmip14.TagRequestHeader, Value: ttlv.Values{
+			{Tag: kmip14.TagProtocolVersion, Value: msg.RequestHeader.ProtocolVersion},
+			{Tag: names.TagProtectionLevel, Value: names.ProtectionLevelHigh},
+			{Tag: kmip14.TagBatchCount, Value: 1},
+		}},
+		{Tag: kmip14.TagBatchItem, Value: &msg.BatchItem[0]},
+	}})
+	require.NoError(t, err)
+
+	buf.Reset()
+	h.ServeKMIP(context.Background(), &Request{TTLV: req}, &buf)
+
+	resp = ResponseMessage{}
+	require.NoError(t, ttlv.Unmarshal(buf.Bytes(), &resp))
+	require.Len(t, resp.BatchItem, 1)
+	assert.Equal(t, 1, resp.ResponseHeader.BatchCount)
+	assert.Equal(t, kmip14.ResultReasonInvalidMessage, resp.BatchItem[0].ResultReason)
+	assert.Contains(t, resp.BatchItem[0].ResultMessage, "tag ProtectionLevel is not defined in 1.4")
+}
+
+func TestStandardProtocolHandler_invalidResponse(t *testing.T) {
+	h := testProtocolHandler()
+
+	// the Destroy response payload is missing its required UniqueIdentifier, so it can't
+	// be encoded.  Only that item fails.
+	mux := h.MessageHandler.(*OperationMux) //nolint:forcetypeassert
+	mux.Handle(kmip14.OperationDestroy, ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+		return &ResponseBatchItem{
+			ResultStatus:    kmip14.ResultStatusSuccess,
+			ResponsePayload: &DestroyResponsePayload{},
+		}, nil
+	}))
+
+	msg := testRequestMessage()
+	msg.RequestHeader.BatchCount = 2
+	msg.BatchItem = append(msg.BatchItem, RequestBatchItem{
+		Operation:         kmip14.OperationDestroy,
+		UniqueBatchItemID: []byte{0x05},
+		RequestPayload:    &DestroyRequestPayload{UniqueIdentifier: "key1"},
+	})
+
+	req, err := ttlv.Marshal(msg)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	h.ServeKMIP(context.Background(), &Request{TTLV: req}, &buf)
+
+	var resp ResponseMessage
+	require.NoError(t, ttlv.Unmarshal(buf.Bytes(), &resp))
+	require.Len(t, resp.BatchItem, 2)
+
+	assert.Equal(t, kmip14.ResultStatusSuccess, resp.BatchItem[0].ResultStatus, resp.BatchItem[0].ResultMessage)
+
+	bi := resp.BatchItem[1]
+	assert.Equal(t, kmip14.OperationDestroy, bi.Operation)
+	assert.Equal(t, []byte{0x05}, bi.UniqueBatchItemID)
+	assert.Equal(t, kmip14.ResultStatusOperationFailed, bi.ResultStatus)
+	assert.Equal(t, kmip14.ResultReasonMissingData, bi.ResultReason)
+	assert.Contains(t, bi.ResultMessage, "DestroyResponsePayload.UniqueIdentifier")
+	assert.Nil(t, bi.ResponsePayload)
+}
+
+// blockingProtocolHandler returns a handler which signals started when it
+// receives a request, then waits for release before responding.
+func blockingProtocolHandler(started chan<- struct{}, release <-chan struct{}) *StandardProtocolHandler {
+	h := testProtocolHandler()
+	h.MessageHandler = MessageHandlerFunc(func(ctx context.Context, req *Request, resp *Response) {
+		started <- struct{}{}
+		<-release
+		testProtocolHandler().MessageHandler.HandleMessage(ctx, req, resp)
+	})
+
+	return h
+}
+
+func TestServer_ConnState(t *testing.T) {
+	var mu sync.Mutex
+
+	var states []ConnState
+
+	closed := make(chan struct{})
+
+	srv := &Server{
+		Handler: testProtocolHandler(),
+		ConnState: func(c net.Conn, state ConnState) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			states = append(states, state)
+			if state == StateClosed {
+				close(closed)
+			}
+		},
+	}
+
+	conn, err := net.Dial("tcp", serveTest(t, srv))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		resp := roundTrip(t, conn, testRequestMessage())
+		require.Len(t, resp.BatchItem, 1)
+	}
+
+	require.NoError(t, conn.Close())
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "connection wasn't closed")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, []ConnState{StateNew, StateActive, StateIdle, StateActive, StateIdle, StateClosed}, states)
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	srv := &Server{Handler: blockingProtocolHandler(started, release)}
+
+	conn, err := net.Dial("tcp", serveTest(t, srv))
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	// an idle connection, which should be closed right away
+	idle, err := net.Dial("tcp", conn.RemoteAddr().String())
+	require.NoError(t, err)
+
+	defer idle.Close()
+
+	go func() {
+		<-started
+		release <- struct{}{}
+	}()
+
+	roundTrip(t, idle, testRequestMessage())
+
+	resps := make(chan ResponseMessage, 1)
+
+	go func() {
+		resps <- roundTrip(t, conn, testRequestMessage())
+	}()
+
+	<-started
+
+	shutdown := make(chan error, 1)
+
+	go func() {
+		shutdown <- srv.Shutdown(context.Background())
+	}()
+
+	// shutdown waits for the in flight request
+	select {
+	case err := <-shutdown:
+		require.Fail(t, "Shutdown returned before the request finished", "err: %v", err)
+	case <-time.After(2 * shutdownPollInterval):
+	}
+
+	close(release)
+
+	select {
+	case resp := <-resps:
+		require.Len(t, resp.BatchItem, 1)
+		assert.Equal(t, kmip14.ResultStatusSuccess, resp.BatchItem[0].ResultStatus, resp.BatchItem[0].ResultMessage)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "no response")
+	}
+
+	select {
+	case err := <-shutdown:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Shutdown didn't return")
+	}
+
+	// both connections are closed
+	for _, c := range []net.Conn{conn, idle} {
+		require.NoError(t, c.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+		_, err = c.Read(make([]byte, 1))
+
+		var ne net.Error
+		require.Error(t, err)
+		assert.False(t, errors.As(err, &ne) && ne.Timeout(), "connection should have been closed: %v", err)
+	}
+}
+
+func TestServer_Shutdown_contextExpired(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	defer close(release)
+
+	srv := &Server{Handler: blockingProtocolHandler(started, release)}
+
+	conn, err := net.Dial("tcp", serveTest(t, srv))
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	req, err := ttlv.Marshal(testRequestMessage())
+	require.NoError(t, err)
+
+	_, err = conn.Write(req)
+	require.NoError(t, err)
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, srv.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func TestServer_IdleTimeout(t *testing.T) {
+	srv := &Server{
+		Handler:     testProtocolHandler(),
+		IdleTimeout: 50 * time.Millisecond,
+	}
+
+	conn, err := net.Dial("tcp", serveTest(t, srv))
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	resp := roundTrip(t, conn, testRequestMessage())
+	require.Len(t, resp.BatchItem, 1)
+
+	// the server closes the connection once it has been idle too long
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	_, err = conn.Read(make([]byte, 1))
+	require.Error(t, err)
+
+	var ne net.Error
+	assert.False(t, errors.As(err, &ne) && ne.Timeout(), "connection should have been closed: %v", err)
+}
+
+func TestServer_ReadTimeout(t *testing.T) {
+	srv := &Server{
+		Handler:     testProtocolHandler(),
+		ReadTimeout: 50 * time.Millisecond,
+		IdleTimeout: 5 * time.Second,
+	}
+
+	conn, err := net.Dial("tcp", serveTest(t, srv))
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	req, err := ttlv.Marshal(testRequestMessage())
+	require.NoError(t, err)
+
+	// send part of a request, then stall
+	_, err = conn.Write(req[:10])
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	_, err = conn.Read(make([]byte, 1))
+	require.Error(t, err)
+
+	var ne net.Error
+	assert.False(t, errors.As(err, &ne) && ne.Timeout(), "connection should have been closed: %v", err)
+}
+
+func TestServer_MaxConnections(t *testing.T) {
+	srv := &Server{
+		Handler:        testProtocolHandler(),
+		MaxConnections: 1,
+	}
+
+	addr := serveTest(t, srv)
+
+	conn1, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+
+	resp := roundTrip(t, conn1, testRequestMessage())
+	require.Len(t, resp.BatchItem, 1)
+
+	// the second connection isn't served until the first is closed
+	conn2, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+
+	defer conn2.Close()
+
+	req, err := ttlv.Marshal(testRequestMessage())
+	require.NoError(t, err)
+
+	_, err = conn2.Write(req)
+	require.NoError(t, err)
+
+	require.NoError(t, conn2.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+
+	_, err = conn2.Read(make([]byte, 1))
+
+	var ne net.Error
+	require.True(t, errors.As(err, &ne) && ne.Timeout(), "expected timeout, got %v", err)
+
+	require.NoError(t, conn1.Close())
+	require.NoError(t, conn2.SetReadDeadline(time.Time{}))
+
+	respTTLV, err := ttlv.NewDecoder(conn2).NextTTLV()
+	require.NoError(t, err)
+
+	var respMsg ResponseMessage
+	require.NoError(t, ttlv.Unmarshal(respTTLV, &respMsg))
+	require.Len(t, respMsg.BatchItem, 1)
+	assert.Equal(t, kmip14.ResultStatusSuccess, respMsg.BatchItem[0].ResultStatus)
+}
+
+// BenchmarkRequestMessageRoundTrip measures a client encoding a request message,
+// the server handling it, and the client decoding the response.
+func BenchmarkRequestMessageRoundTrip(b *testing.B) {
+	h := testProtocolHandler()
+	msg := testRequestMessage()
+
+	var (
+		reqBuf []byte
+		conn   bytes.Buffer
+	)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		req, err := ttlv.MarshalAppend(reqBuf[:0], msg)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		reqBuf = req
+
+		conn.Reset()
+		h.ServeKMIP(context.Background(), &Request{TTLV: req}, &conn)
+
+		var resp ResponseMessage
+		if err := ttlv.NewDecoder(nil).DecodeValue(&resp, conn.Bytes()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStandardProtocolHandler_ServeKMIP(b *testing.B) {
+	h := testProtocolHandler()
+
+	req, err := ttlv.Marshal(testRequestMessage())
+	require.NoError(b, err)
+
+	var conn bytes.Buffer
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		conn.Reset()
+		h.ServeKMIP(context.Background(), &Request{TTLV: req}, &conn)
+	}
+}
+
+func TestStandardProtocolHandler_ServeKMIP_maxDepth(t *testing.T) {
+	h := testProtocolHandler()
+
+	// a Get request, whose payload holds an extra value nested as deeply as the server's
+	// decoder allows.  Extra values are allowed from a 1.3 client.
+	nested := ttlv.Value{Tag: kmip14.TagComment, Value: "x"}
+	for i := 0; i < DefaultMaxDepth-3; i++ {
+		nested = ttlv.Value{Tag: kmip14.TagTemplateAttribute, Value: ttlv.Values{nested}}
+	}
+
+	msg := testRequestMessage()
+	msg.RequestHeader.ProtocolVersion.ProtocolVersionMinor = 3
+	msg.BatchItem[0].RequestPayload = ttlv.Value{Tag: kmip14.TagRequestPayload, Value: ttlv.Values{
+		{Tag: kmip14.TagUniqueIdentifier, Value: "key1"},
+		nested,
+	}}
+
+	b, err := ttlv.Marshal(msg)
+	require.NoError(t, err)
+
+	var srv Server
+
+	req, err := srv.newDecoder(bytes.NewReader(b)).NextTTLV()
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	var buf bytes.Buffer
+
+	h.ServeKMIP(context.Background(), &Request{TTLV: req}, &buf)
+
+	// the nested values must be validated in linear time
+	assert.Less(t, time.Since(start), time.Second)
+
+	var resp ResponseMessage
+	require.NoError(t, ttlv.Unmarshal(buf.Bytes(), &resp))
+	require.Len(t, resp.BatchItem, 1)
+	assert.Equal(t, kmip14.ResultStatusSuccess, resp.BatchItem[0].ResultStatus, resp.BatchItem[0].ResultMessage)
+
+	// one more level is rejected by the decoder
+	b, err = ttlv.Marshal(ttlv.Value{Tag: kmip14.TagRequestMessage, Value: ttlv.Values{{Tag: kmip14.TagBatchItem, Value: ttlv.Values{
+		{Tag: kmip14.TagRequestPayload, Value: ttlv.Values{{Tag: kmip14.TagTemplateAttribute, Value: ttlv.Values{nested}}}},
+	}}}})
+	require.NoError(t, err)
+
+	_, err = srv.newDecoder(bytes.NewReader(b)).NextTTLV()
+	require.ErrorIs(t, err, ttlv.ErrMaxDepthExceeded)
+}
@@ -0,0 +1,132 @@
+package kmip
+
+import (
+	"reflect"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+)
+
+// RequestPayloadTypes maps operations to the types ResolveType decodes their
+// request payloads into.
+var RequestPayloadTypes = map[kmip14.Operation]reflect.Type{
+	kmip14.OperationActivate:         reflect.TypeFor[*ActivateRequestPayload](),
+	kmip14.OperationCreate:           reflect.TypeFor[*CreateRequestPayload](),
+	kmip14.OperationCreateKeyPair:    reflect.TypeFor[*CreateKeyPairRequestPayload](),
+	kmip14.OperationDestroy:          reflect.TypeFor[*DestroyRequestPayload](),
+	kmip14.OperationDiscoverVersions: reflect.TypeFor[*DiscoverVersionsRequestPayload](),
+	kmip14.OperationGet:              reflect.TypeFor[*GetRequestPayload](),
+	kmip14.OperationGetAttributes:    reflect.TypeFor[*GetAttributesRequestPayload](),
+	kmip14.OperationLocate:           reflect.TypeFor[*LocateRequestPayload](),
+	kmip14.OperationQuery:            reflect.TypeFor[*QueryRequestPayload](),
+	kmip14.OperationRegister:         reflect.TypeFor[*RegisterRequestPayload](),
+	kmip14.OperationReKey:            reflect.TypeFor[*ReKeyRequestPayload](),
+	kmip14.OperationRevoke:           reflect.TypeFor[*RevokeRequestPayload](),
+}
+
+// ResponsePayloadTypes maps operations to the types ResolveType decodes their
+// response payloads into.
+var ResponsePayloadTypes = map[kmip14.Operation]reflect.Type{
+	kmip14.OperationActivate:         reflect.TypeFor[*ActivateResponsePayload](),
+	kmip14.OperationCreate:           reflect.TypeFor[*CreateResponsePayload](),
+	kmip14.OperationCreateKeyPair:    reflect.TypeFor[*CreateKeyPairResponsePayload](),
+	kmip14.OperationDestroy:          reflect.TypeFor[*DestroyResponsePayload](),
+	kmip14.OperationDiscoverVersions: reflect.TypeFor[*DiscoverVersionsResponsePayload](),
+	kmip14.OperationGet:              reflect.TypeFor[*GetResponsePayload](),
+	kmip14.OperationGetAttributes:    reflect.TypeFor[*GetAttributesResponsePayload](),
+	kmip14.OperationLocate:           reflect.TypeFor[*LocateResponsePayload](),
+	kmip14.OperationQuery:            reflect.TypeFor[*QueryResponsePayload](),
+	kmip14.OperationRegister:         reflect.TypeFor[*RegisterResponsePayload](),
+	kmip14.OperationReKey:            reflect.TypeFor[*ReKeyResponsePayload](),
+	kmip14.OperationRevoke:           reflect.TypeFor[*RevokeResponsePayload](),
+}
+
+// KeyMaterialTypes maps key format types to the types ResolveType decodes
+// Structure key material into.  Byte String key material is always decoded into
+// a []byte.
+var KeyMaterialTypes = map[kmip14.KeyFormatType]reflect.Type{
+	kmip14.KeyFormatTypeTransparentSymmetricKey:    reflect.TypeFor[TransparentSymmetricKey](),
+	kmip14.KeyFormatTypeTransparentDSAPrivateKey:   reflect.TypeFor[TransparentDSAPrivateKey](),
+	kmip14.KeyFormatTypeTransparentDSAPublicKey:    reflect.TypeFor[TransparentDSAPublicKey](),
+	kmip14.KeyFormatTypeTransparentRSAPrivateKey:   reflect.TypeFor[TransparentRSAPrivateKey](),
+	kmip14.KeyFormatTypeTransparentRSAPublicKey:    reflect.TypeFor[TransparentRSAPublicKey](),
+	kmip14.KeyFormatTypeTransparentDHPrivateKey:    reflect.TypeFor[TransparentDHPrivateKey](),
+	kmip14.KeyFormatTypeTransparentDHPublicKey:     reflect.TypeFor[TransparentDHPublicKey](),
+	kmip14.KeyFormatTypeTransparentECDSAPrivateKey: reflect.TypeFor[TransparentECDSAPrivateKey](),
+	kmip14.KeyFormatTypeTransparentECDSAPublicKey:  reflect.TypeFor[TransparentECDSAPublicKey](),
+	kmip14.KeyFormatTypeTransparentECDHPrivateKey:  reflect.TypeFor[TransparentECDHPrivateKey](),
+	kmip14.KeyFormatTypeTransparentECDHPublicKey:   reflect.TypeFor[TransparentECDHPublicKey](),
+	kmip14.KeyFormatTypeTransparentECMQVPrivateKey: reflect.TypeFor[TransparentECMQVPrivateKey](),
+	kmip14.KeyFormatTypeTransparentECMQVPublicKey:  reflect.TypeFor[TransparentECMQVPublicKey](),
+	kmip14.KeyFormatTypeTransparentECPrivateKey:    reflect.TypeFor[TransparentECPrivateKey](),
+	kmip14.KeyFormatTypeTransparentECPublicKey:     reflect.TypeFor[TransparentECPublicKey](),
+}
+
+// VendorExtensionTypes maps vendor identifications to the types ResolveType decodes
+// the VendorExtension of their MessageExtensions into.  It is empty by default.
+var VendorExtensionTypes = map[string]reflect.Type{}
+
+// ResolveType is a ttlv.TypeResolver for the interface{} fields of the types in this
+// package.  It decodes:
+//
+//   - RequestBatchItem.RequestPayload and ResponseBatchItem.ResponsePayload according to
+//     the batch item's Operation, using RequestPayloadTypes and ResponsePayloadTypes
+//   - KeyValue.KeyMaterial according to the KeyFormatType of the enclosing KeyBlock,
+//     using KeyMaterialTypes
+//   - MessageExtension.VendorExtension according to the VendorIdentification, using
+//     VendorExtensionTypes
+//   - Credential.CredentialValue according to the CredentialType, using
+//     CredentialValueTypes
+//
+// Values with no registered type are decoded as usual, into a ttlv.TTLV.
+//
+//	dec := ttlv.NewDecoder(conn)
+//	dec.TypeResolver = kmip.ResolveType
+func ResolveType(v ttlv.TTLV, parents []ttlv.TTLV) (reflect.Type, error) {
+	if len(parents) == 0 || v.Type() != ttlv.TypeStructure {
+		return nil, nil
+	}
+
+	parent := parents[len(parents)-1]
+
+	switch v.Tag() {
+	case kmip14.TagRequestPayload:
+		if op := findValue(parent, kmip14.TagOperation); op.Type() == ttlv.TypeEnumeration {
+			return RequestPayloadTypes[kmip14.Operation(op.ValueEnumeration())], nil
+		}
+	case kmip14.TagResponsePayload:
+		if op := findValue(parent, kmip14.TagOperation); op.Type() == ttlv.TypeEnumeration {
+			return ResponsePayloadTypes[kmip14.Operation(op.ValueEnumeration())], nil
+		}
+	case kmip14.TagKeyMaterial:
+		if len(parents) < 2 {
+			return nil, nil
+		}
+
+		// the key format type is a sibling of the enclosing KeyValue
+		if f := findValue(parents[len(parents)-2], kmip14.TagKeyFormatType); f.Type() == ttlv.TypeEnumeration {
+			return KeyMaterialTypes[kmip14.KeyFormatType(f.ValueEnumeration())], nil
+		}
+	case kmip14.TagCredentialValue:
+		if ct := findValue(parent, kmip14.TagCredentialType); ct.Type() == ttlv.TypeEnumeration {
+			return CredentialValueTypes[kmip14.CredentialType(ct.ValueEnumeration())], nil
+		}
+	case kmip14.TagVendorExtension:
+		if id := findValue(parent, kmip14.TagVendorIdentification); id.Type() == ttlv.TypeTextString {
+			return VendorExtensionTypes[id.ValueTextString()], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// findValue returns the first value with the tag in Structure s, or nil.
+func findValue(s ttlv.TTLV, tag ttlv.Tag) ttlv.TTLV {
+	for n := s.ValueStructure(); n != nil; n = n.Next() {
+		if n.Tag() == tag {
+			return n
+		}
+	}
+
+	return nil
+}
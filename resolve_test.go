@@ -0,0 +1,98 @@
+package kmip
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveType(t *testing.T) {
+	req, err := ttlv.Marshal(RequestMessage{
+		RequestHeader: RequestHeader{
+			ProtocolVersion: ProtocolVersion{ProtocolVersionMajor: 1, ProtocolVersionMinor: 4},
+			BatchCount:      2,
+		},
+		BatchItem: []RequestBatchItem{
+			{
+				Operation:      kmip14.OperationGet,
+				RequestPayload: GetRequestPayload{UniqueIdentifier: "key1"},
+			},
+			{
+				// no payload type is registered for this operation
+				Operation:      kmip14.OperationCheck,
+				RequestPayload: GetRequestPayload{UniqueIdentifier: "key2"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	dec := ttlv.NewDecoder(bytes.NewReader(req))
+	dec.TypeResolver = ResolveType
+
+	var msg RequestMessage
+	require.NoError(t, dec.DecodeValue(&msg, req))
+	assert.Equal(t, &GetRequestPayload{UniqueIdentifier: "key1"}, msg.BatchItem[0].RequestPayload)
+	assert.IsType(t, ttlv.TTLV{}, msg.BatchItem[1].RequestPayload)
+
+	// the payload can be taken from the request without decoding it again
+	r := Request{CurrentItem: &msg.BatchItem[0]}
+
+	var payload GetRequestPayload
+	require.NoError(t, r.DecodePayload(&payload))
+	assert.Equal(t, GetRequestPayload{UniqueIdentifier: "key1"}, payload)
+
+	resp, err := ttlv.Marshal(ResponseMessage{
+		ResponseHeader: ResponseHeader{
+			ProtocolVersion: ProtocolVersion{ProtocolVersionMajor: 1, ProtocolVersionMinor: 4},
+			BatchCount:      1,
+		},
+		BatchItem: []ResponseBatchItem{
+			{
+				Operation:    kmip14.OperationGet,
+				ResultStatus: kmip14.ResultStatusSuccess,
+				ResponsePayload: GetResponsePayload{
+					ObjectType:       kmip14.ObjectTypeSymmetricKey,
+					UniqueIdentifier: "key1",
+					SymmetricKey: &SymmetricKey{KeyBlock: KeyBlock{
+						KeyFormatType: kmip14.KeyFormatTypeTransparentSymmetricKey,
+						KeyValue: &KeyValue{
+							KeyMaterial: TransparentSymmetricKey{Key: []byte{0x01, 0x02}},
+						},
+					}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var respMsg ResponseMessage
+	require.NoError(t, dec.DecodeValue(&respMsg, resp))
+	require.IsType(t, &GetResponsePayload{}, respMsg.BatchItem[0].ResponsePayload)
+
+	getResp := respMsg.BatchItem[0].ResponsePayload.(*GetResponsePayload) //nolint:forcetypeassert
+	require.NotNil(t, getResp.SymmetricKey)
+	assert.Equal(t, TransparentSymmetricKey{Key: []byte{0x01, 0x02}}, getResp.SymmetricKey.KeyBlock.KeyValue.KeyMaterial)
+}
+
+func TestResolveType_credentialValue(t *testing.T) {
+	req, err := ttlv.Marshal(RequestHeader{
+		ProtocolVersion: ProtocolVersion{ProtocolVersionMajor: 1, ProtocolVersionMinor: 4},
+		Authentication: &Authentication{Credential: []Credential{{
+			CredentialType:  kmip14.CredentialTypeUsernameAndPassword,
+			CredentialValue: UsernameAndPasswordCredentialValue{Username: "alice", Password: "secret"},
+		}}},
+		BatchCount: 1,
+	})
+	require.NoError(t, err)
+
+	dec := ttlv.NewDecoder(bytes.NewReader(req))
+	dec.TypeResolver = ResolveType
+
+	var h RequestHeader
+	require.NoError(t, dec.DecodeValue(&h, req))
+	assert.Equal(t, &UsernameAndPasswordCredentialValue{Username: "alice", Password: "secret"}, h.Authentication.Credential[0].CredentialValue)
+}
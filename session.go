@@ -0,0 +1,195 @@
+package kmip
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/google/uuid"
+)
+
+// Session holds the state of a client's connection to a Server.  A Session is created
+// for each connection, after the TLS handshake, and lasts until the connection is closed.
+// Handlers can get the Session from the Request, or from the context with SessionFromContext.
+//
+// Because requests on a connection are handled serially, handlers can use the Session
+// to implement stateful protocols, like logins and leases, on top of KMIP's request/response
+// model.  The methods of Session are safe for concurrent use.
+type Session struct {
+	// ID uniquely identifies the session.
+	ID string
+	// RemoteAddr and LocalAddr are the addresses of the client and server ends of the connection.
+	RemoteAddr string
+	LocalAddr  string
+	// TLS holds the TLS state of the connection, or nil if the connection isn't a TLS connection.
+	TLS *tls.ConnectionState
+	// StartTime is the time the session was created.
+	StartTime time.Time
+
+	mu        sync.Mutex
+	principal interface{}
+	// credential is the digest of the credentials the principal was authenticated with,
+	// if authenticated is set
+	credential      [sha256.Size]byte
+	authenticated   bool
+	protocolVersion ProtocolVersion
+	values          map[interface{}]interface{}
+	onClose         []func()
+}
+
+// NewSession returns a new Session for a connection.  Servers create sessions for
+// each connection, so this is only needed by other implementations of servers, or tests.
+func NewSession(remoteAddr, localAddr string, tlsState *tls.ConnectionState) *Session {
+	return &Session{
+		ID:         uuid.New().String(),
+		RemoteAddr: remoteAddr,
+		LocalAddr:  localAddr,
+		TLS:        tlsState,
+		StartTime:  time.Now(),
+	}
+}
+
+// Principal returns the authenticated identity of the client, or nil if the client
+// hasn't been authenticated.
+func (s *Session) Principal() interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.principal
+}
+
+// SetPrincipal sets the authenticated identity of the client.  Set it to nil to log
+// the client out.  The client is authenticated again by the next request.
+func (s *Session) SetPrincipal(p interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.principal = p
+	s.authenticated = false
+}
+
+// authenticatedPrincipal returns the principal, if it was authenticated with the
+// credentials whose digest is credential.
+func (s *Session) authenticatedPrincipal(credential [sha256.Size]byte) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.authenticated || s.credential != credential {
+		return nil, false
+	}
+
+	return s.principal, true
+}
+
+// setAuthenticatedPrincipal sets the principal, which was authenticated with the
+// credentials whose digest is credential.
+func (s *Session) setAuthenticatedPrincipal(p interface{}, credential [sha256.Size]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.principal = p
+	s.credential = credential
+	s.authenticated = true
+}
+
+// ProtocolVersion returns the protocol version negotiated with the client.  It is set
+// by the StandardProtocolHandler when it accepts a request, to the highest version
+// supported by both the client and server.  It is zero until then.
+func (s *Session) ProtocolVersion() ProtocolVersion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.protocolVersion
+}
+
+// SetProtocolVersion sets the protocol version negotiated with the client.
+func (s *Session) SetProtocolVersion(v ProtocolVersion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.protocolVersion = v
+}
+
+// Value returns the value stored in the session under key, or nil.  Like context keys,
+// keys should be of unexported types defined by the package storing the value, to avoid
+// collisions.
+func (s *Session) Value(key interface{}) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.values[key]
+}
+
+// SetValue stores val in the session under key.  A nil val deletes the key.
+func (s *Session) SetValue(key, val interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if val == nil {
+		delete(s.values, key)
+		return
+	}
+
+	if s.values == nil {
+		s.values = map[interface{}]interface{}{}
+	}
+
+	s.values[key] = val
+}
+
+// OnClose registers f to be called when the session ends, after the connection is closed.
+// Functions are called in the reverse order of their registration, before Server.OnClose.
+// They can be used to release resources held by the session, like leases.
+func (s *Session) OnClose(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onClose = append(s.onClose, f)
+}
+
+// close calls the functions registered with OnClose.
+func (s *Session) close() {
+	s.mu.Lock()
+	fns := s.onClose
+	s.onClose = nil
+	s.mu.Unlock()
+
+	for i := len(fns) - 1; i >= 0; i-- {
+		fns[i]()
+	}
+}
+
+// negotiateProtocolVersion returns the highest version supported by both the client and
+// server, which must have the same major version.
+func negotiateProtocolVersion(client, server ProtocolVersion) ProtocolVersion {
+	v := server
+	if client.ProtocolVersionMinor < server.ProtocolVersionMinor {
+		v.ProtocolVersionMinor = client.ProtocolVersionMinor
+	}
+
+	return v
+}
+
+// view returns the view of ttlv.DefaultRegistry scoped to the version.
+func (v ProtocolVersion) view() *ttlv.View {
+	return ttlv.DefaultRegistry.ForVersion(ttlv.Version{Major: v.ProtocolVersionMajor, Minor: v.ProtocolVersionMinor})
+}
+
+type contextKey int
+
+const sessionContextKey contextKey = iota
+
+// WithSession returns a copy of ctx which carries s.
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionContextKey, s)
+}
+
+// SessionFromContext returns the Session carried by ctx, or nil.  The contexts passed to
+// handlers by a Server carry the Session of the connection.
+func SessionFromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionContextKey).(*Session)
+	return s
+}
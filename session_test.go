@@ -0,0 +1,199 @@
+package kmip
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveTest starts srv on a local listener, and returns the listener's address.
+func serveTest(t *testing.T, srv *Server) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		_ = srv.Serve(l)
+	}()
+
+	t.Cleanup(func() {
+		_ = srv.Close()
+	})
+
+	return l.Addr().String()
+}
+
+// roundTrip sends msg on conn and decodes the response.
+func roundTrip(t *testing.T, conn net.Conn, msg RequestMessage) ResponseMessage {
+	t.Helper()
+
+	req, err := ttlv.Marshal(msg)
+	require.NoError(t, err)
+
+	_, err = conn.Write(req)
+	require.NoError(t, err)
+
+	respTTLV, err := ttlv.NewDecoder(conn).NextTTLV()
+	require.NoError(t, err)
+
+	var resp ResponseMessage
+	require.NoError(t, ttlv.Unmarshal(respTTLV, &resp))
+
+	return resp
+}
+
+type testSessionKey struct{}
+
+func TestServer_Session(t *testing.T) {
+	connected := make(chan *Session, 1)
+	closed := make(chan *Session, 1)
+
+	var order []string
+
+	mux := &OperationMux{}
+	mux.Handle(kmip14.OperationGet, ItemHandlerFunc(func(ctx context.Context, req *Request) (*ResponseBatchItem, error) {
+		s := SessionFromContext(ctx)
+		require.NotNil(t, s)
+		assert.Same(t, s, req.Session)
+
+		// count the requests on the session
+		n, _ := s.Value(testSessionKey{}).(int)
+		s.SetValue(testSessionKey{}, n+1)
+
+		if n == 0 {
+			s.SetPrincipal("alice")
+			s.OnClose(func() { order = append(order, "first") })
+			s.OnClose(func() { order = append(order, "second") })
+		}
+
+		payload := testGetResponsePayload()
+
+		return &ResponseBatchItem{
+			ResultStatus:    kmip14.ResultStatusSuccess,
+			ResponsePayload: &payload,
+		}, nil
+	}))
+
+	srv := &Server{
+		Handler: &StandardProtocolHandler{
+			MessageHandler:  mux,
+			ProtocolVersion: ProtocolVersion{ProtocolVersionMajor: 1, ProtocolVersionMinor: 4},
+		},
+		OnConnect: func(ctx context.Context, s *Session) error {
+			assert.Same(t, s, SessionFromContext(ctx))
+			connected <- s
+			return nil
+		},
+		OnClose: func(s *Session) {
+			order = append(order, "server")
+			closed <- s
+		},
+	}
+
+	conn, err := net.Dial("tcp", serveTest(t, srv))
+	require.NoError(t, err)
+
+	msg := testRequestMessage()
+	msg.RequestHeader.ProtocolVersion.ProtocolVersionMinor = 2
+
+	for i := 0; i < 2; i++ {
+		resp := roundTrip(t, conn, msg)
+		require.Len(t, resp.BatchItem, 1)
+		assert.Equal(t, kmip14.ResultStatusSuccess, resp.BatchItem[0].ResultStatus, resp.BatchItem[0].ResultMessage)
+	}
+
+	var s *Session
+	select {
+	case s = <-connected:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "OnConnect wasn't called")
+	}
+
+	assert.NotEmpty(t, s.ID)
+	assert.Equal(t, conn.LocalAddr().String(), s.RemoteAddr)
+	assert.Equal(t, conn.RemoteAddr().String(), s.LocalAddr)
+	assert.Nil(t, s.TLS)
+	assert.Equal(t, 2, s.Value(testSessionKey{}))
+	assert.Equal(t, "alice", s.Principal())
+	assert.Equal(t, ProtocolVersion{ProtocolVersionMajor: 1, ProtocolVersionMinor: 2}, s.ProtocolVersion())
+
+	require.NoError(t, conn.Close())
+
+	select {
+	case cs := <-closed:
+		assert.Same(t, s, cs)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "OnClose wasn't called")
+	}
+
+	assert.Equal(t, []string{"second", "first", "server"}, order)
+}
+
+func TestServer_OnConnectError(t *testing.T) {
+	closed := make(chan *Session, 1)
+
+	srv := &Server{
+		OnConnect: func(ctx context.Context, s *Session) error {
+			return errors.New("go away")
+		},
+		OnClose: func(s *Session) {
+			closed <- s
+		},
+	}
+
+	conn, err := net.Dial("tcp", serveTest(t, srv))
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	// the server closes the connection without reading the request
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	_, err = conn.Read(make([]byte, 1))
+	require.Error(t, err)
+
+	var ne net.Error
+	assert.False(t, errors.As(err, &ne) && ne.Timeout(), "connection should have been closed: %v", err)
+
+	select {
+	case <-closed:
+		assert.Fail(t, "OnClose shouldn't be called for rejected connections")
+	default:
+	}
+}
+
+func TestSession_Value(t *testing.T) {
+	s := NewSession("remote", "local", nil)
+
+	assert.Nil(t, s.Value("a"))
+
+	s.SetValue("a", 1)
+	assert.Equal(t, 1, s.Value("a"))
+
+	s.SetValue("a", nil)
+	assert.Nil(t, s.Value("a"))
+}
+
+func TestStandardProtocolHandler_ServeKMIP_sessionFromContext(t *testing.T) {
+	h := testProtocolHandler()
+
+	req, err := ttlv.Marshal(testRequestMessage())
+	require.NoError(t, err)
+
+	s := NewSession("remote", "local", nil)
+	r := &Request{TTLV: req}
+
+	h.ServeKMIP(WithSession(context.Background(), s), r, io.Discard)
+
+	assert.Same(t, s, r.Session)
+	assert.Equal(t, ProtocolVersion{ProtocolVersionMajor: 1, ProtocolVersionMinor: 4}, s.ProtocolVersion())
+}
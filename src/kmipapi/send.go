@@ -7,6 +7,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"log/slog"
+	"sync"
 
 	"github.com/Seagate/kmip-go"
 	"github.com/Seagate/kmip-go/kmip14"
@@ -19,6 +20,26 @@ const (
 	DefaultBufferSize = 4096
 )
 
+// requestBufferPool holds the buffers request messages are encoded into. Requests
+// are written to the connection before returning, so the buffers can be reused.
+var requestBufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, DefaultBufferSize)
+		return &b
+	},
+}
+
+// maxPooledRequestSize is the largest request buffer kept by requestBufferPool, so
+// a few large requests don't pin their buffers.
+const maxPooledRequestSize = 64 * 1024
+
+func releaseRequestBuffer(b *[]byte) {
+	if cap(*b) > maxPooledRequestSize {
+		return
+	}
+	requestBufferPool.Put(b)
+}
+
 func BatchCmdGenerateMessage(ctx context.Context, settings *ConfigurationSettings, payload []kmip.RequestBatchItem) (kmip.RequestMessage, error) {
 	logger := ctx.Value(common.LoggerKey).(*slog.Logger)
 
@@ -49,8 +70,11 @@ func SendRequestMessage(ctx context.Context, connection *tls.Conn, settings *Con
 	var err error
 	// var msg kmip.RequestMessage
 
+	reqBuf := requestBufferPool.Get().(*[]byte)
+	defer releaseRequestBuffer(reqBuf)
+
 	if dobatch {
-		kmipreq, err = ttlv.Marshal(payload)
+		kmipreq, err = ttlv.MarshalAppend((*reqBuf)[:0], payload)
 		if err != nil {
 			return nil, nil, fmt.Errorf("dobatch - failed to marshal message, error: %v", err)
 		}
@@ -77,11 +101,12 @@ func SendRequestMessage(ctx context.Context, connection *tls.Conn, settings *Con
 		}
 
 		logger.Debug("(2) marshal message and print request")
-		kmipreq, err = ttlv.Marshal(msg)
+		kmipreq, err = ttlv.MarshalAppend((*reqBuf)[:0], msg)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to marshal message, error: %v", err)
 		}
 	}
+	*reqBuf = kmipreq
 	logger.Debug("KMIP message", "request", kmipreq)
 
 	if connection != nil {
@@ -0,0 +1,93 @@
+package kmipapi
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Seagate/kmip-go"
+	"github.com/Seagate/kmip-go/kmip14"
+	"github.com/Seagate/kmip-go/pkg/common"
+	"github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/require"
+)
+
+// serverConn returns a TLS connection to a server, which answers every request with
+// a successful Get response.
+func serverConn(tb testing.TB) *tls.Conn {
+	tb.Helper()
+
+	cert, err := tls.LoadX509KeyPair("../../pykmip-server/server.cert", "../../pykmip-server/server.key")
+	require.NoError(tb, err)
+
+	resp, err := ttlv.Marshal(kmip.ResponseMessage{
+		ResponseHeader: kmip.ResponseHeader{
+			ProtocolVersion: kmip.ProtocolVersion{ProtocolVersionMajor: 1, ProtocolVersionMinor: 4},
+			TimeStamp:       time.Now(),
+			BatchCount:      1,
+		},
+		BatchItem: []kmip.ResponseBatchItem{
+			{
+				Operation:    kmip14.OperationGet,
+				ResultStatus: kmip14.ResultStatusSuccess,
+				ResponsePayload: ttlv.Value{Tag: kmip14.TagResponsePayload, Value: ttlv.Values{
+					{Tag: kmip14.TagObjectType, Value: kmip14.ObjectTypeSymmetricKey},
+					{Tag: kmip14.TagUniqueIdentifier, Value: "key1"},
+				}},
+			},
+		},
+	})
+	require.NoError(tb, err)
+
+	client, server := net.Pipe()
+	srv := tls.Server(server, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+	conn := tls.Client(client, &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12}) //nolint:gosec
+
+	go func() {
+		dec := ttlv.NewDecoder(srv)
+
+		for {
+			if _, err := dec.NextTTLV(); err != nil {
+				return
+			}
+
+			if _, err := srv.Write(resp); err != nil {
+				return
+			}
+		}
+	}()
+
+	tb.Cleanup(func() {
+		_ = conn.Close()
+		_ = srv.Close()
+	})
+
+	return conn
+}
+
+// BenchmarkSendRequestMessage measures sending a Get request, and decoding the response.
+// Requests are encoded into pooled buffers.
+func BenchmarkSendRequestMessage(b *testing.B) {
+	conn := serverConn(b)
+	ctx := context.WithValue(context.Background(), common.LoggerKey, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	settings := &ConfigurationSettings{ProtocolVersionMajor: 1, ProtocolVersionMinor: 4}
+	payload := kmip.GetRequestPayload{UniqueIdentifier: "key1"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, item, err := SendRequestMessage(ctx, conn, settings, uint32(kmip14.OperationGet), &payload, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if item.ResultStatus != kmip14.ResultStatusSuccess {
+			b.Fatal(item.ResultStatus)
+		}
+	}
+}
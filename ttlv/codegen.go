@@ -0,0 +1,211 @@
+package ttlv
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The declarations in this file support the MarshalTTLV and UnmarshalTTLV methods
+// generated by cmd/ttlvgen.  Generated methods encode and decode most values directly,
+// and use these to fall back on the reflection based encoder and decoder for
+// everything else, so they behave the same as Marshal and Unmarshal.
+
+// generatedMarker is implemented by the types ttlvgen generates MarshalTTLV and
+// UnmarshalTTLV methods for.  TTLVGenerated returns the type the methods were generated
+// for.  The methods, and the marker, are promoted to structs embedding the type, so the
+// Encoder and Decoder only call them for values of that type.  The fields of the
+// embedded struct are flattened into the embedding struct instead, as they are without
+// the generated methods.
+//
+// A struct which embeds a type with generated methods, and declares its own MarshalTTLV
+// or UnmarshalTTLV, must declare TTLVGenerated too, returning nil, or its methods are
+// ignored.
+type generatedMarker interface {
+	TTLVGenerated() reflect.Type
+}
+
+var generatedMarkerType = reflect.TypeOf((*generatedMarker)(nil)).Elem()
+
+// generatedTypes caches generatedFor, keyed by type.
+var generatedTypes sync.Map
+
+// generatedFor returns the type the MarshalTTLV and UnmarshalTTLV methods of typ, or
+// the type typ points to, were generated for, or nil if they weren't generated.
+func generatedFor(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if g, ok := generatedTypes.Load(typ); ok {
+		g, _ := g.(reflect.Type)
+		return g
+	}
+
+	var g reflect.Type
+
+	// the generated markers have pointer receivers, which don't dereference embedded
+	// pointers, so they can be called on a new value
+	if reflect.PointerTo(typ).Implements(generatedMarkerType) {
+		g = reflect.New(typ).Interface().(generatedMarker).TTLVGenerated() //nolint:forcetypeassert
+	}
+
+	generatedTypes.Store(typ, g)
+
+	return g
+}
+
+// isGenerated returns true if typ, or the type it points to, has generated MarshalTTLV and
+// UnmarshalTTLV methods of its own.
+func isGenerated(typ reflect.Type) bool {
+	g := generatedFor(typ)
+	if g == nil {
+		return false
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return g == typ
+}
+
+// implementsOwn returns true if typ implements iface, and the Encoder or Decoder using
+// Registry r should call the method.  Generated methods aren't called when they're
+// promoted to an embedding struct, or when r isn't DefaultRegistry, since they encode
+// and decode the tags DefaultRegistry defined when they were generated.
+func implementsOwn(typ, iface reflect.Type, r *Registry) bool {
+	if !typ.Implements(iface) {
+		return false
+	}
+
+	if generatedFor(typ) == nil {
+		return true
+	}
+
+	return isGenerated(typ) && r == &DefaultRegistry
+}
+
+// Field describes a struct field to EncodeField and DecodeField.
+type Field struct {
+	// Struct is the type of the struct containing the field.
+	Struct reflect.Type
+	// Name is the name of the field.
+	Name string
+	// Tag is the tag Marshal and Unmarshal infer for the field.
+	Tag Tag
+	// Flags are the flags of the field's "ttlv" struct tag, like "omitempty,enum".
+	Flags string
+}
+
+// EncodeField encodes v as if it were the value of struct field f.  To encode the
+// value the way Marshal would, v should be a pointer to the field, or the field's value
+// if the field is a pointer or interface.
+func (e *Encoder) EncodeField(f Field, v interface{}) error {
+	fi := fieldInfo{
+		name:  f.Name,
+		tag:   f.Tag,
+		flags: parseFieldFlags(f.Flags),
+	}
+
+	currStruct, currField := e.currStruct, e.currField
+	e.currStruct, e.currField = f.Struct.Name(), f.Name
+	err := e.encode(TagNone, reflect.ValueOf(v), &fi)
+	e.currStruct, e.currField = currStruct, currField
+
+	return err
+}
+
+// MarshalingError returns a *MarshalerError for a value of type t, with the given cause.
+func (e *Encoder) MarshalingError(tag Tag, t reflect.Type, cause error) error {
+	return e.marshalingError(tag, t, cause)
+}
+
+// EnumForTag returns the enum registered for the tag in the encoder's Registry.
+func (e *Encoder) EnumForTag(t Tag) EnumMap {
+	return e.registry().EnumForTag(t)
+}
+
+// DecodeField decodes ttlv into struct field f, as Unmarshal would.  v must be
+// a pointer to the field.
+func (dec *Decoder) DecodeField(f Field, v interface{}, ttlv TTLV) error {
+	currStruct, currField := dec.currStruct, dec.currField
+	dec.currStruct, dec.currField = f.Struct, f.Name
+	err := dec.unmarshal(reflect.ValueOf(v).Elem(), ttlv)
+	dec.currStruct, dec.currField = currStruct, currField
+
+	if err != nil {
+		return prependFieldPath(err, f.Name)
+	}
+
+	return nil
+}
+
+// FieldError adds struct field f, or element i of the field if i >= 0, to the start
+// of the FieldPath of the *UnmarshalerError in err, for errors returned by the
+// UnmarshalTTLV methods of fields.  Errors returned by DecodeField already include
+// the field.
+func (dec *Decoder) FieldError(f Field, i int, err error) error {
+	if i >= 0 {
+		err = prependFieldPath(err, "["+strconv.Itoa(i)+"]")
+	}
+
+	return prependFieldPath(err, f.Name)
+}
+
+// EnterStructure must be called before decoding the values of Structure t into
+// a value of type typ, and LeaveStructure after, unless it returns an error.  It returns
+// an *UnmarshalerError with cause ErrMaxDepthExceeded if t is nested deeper than
+// MaxDepth, and records t as the parent of its values for the TypeResolver.
+func (dec *Decoder) EnterStructure(t TTLV, typ reflect.Type) error {
+	// bound the recursion, in case the value wasn't read from the stream
+	if dec.MaxDepth > 0 && len(dec.parents) >= dec.MaxDepth {
+		return dec.newUnmarshalerError(t, typ, ErrMaxDepthExceeded)
+	}
+
+	dec.parents = append(dec.parents, t)
+
+	return nil
+}
+
+// LeaveStructure undoes EnterStructure.
+func (dec *Decoder) LeaveStructure() {
+	dec.parents[len(dec.parents)-1] = nil
+	dec.parents = dec.parents[:len(dec.parents)-1]
+}
+
+// UnmarshalingError returns an *UnmarshalerError for decoding ttlv into a value of
+// type t, with the given cause.
+func (dec *Decoder) UnmarshalingError(ttlv TTLV, t reflect.Type, cause error) error {
+	return dec.newUnmarshalerError(ttlv, t, cause)
+}
+
+// NewValidationError returns a *ValidationError for field of struct type s, with
+// the given cause.
+func NewValidationError(s reflect.Type, field string, tag Tag, cause error) error {
+	return validationError(s, field, tag, cause)
+}
+
+// parseFieldFlags parses the flags which affect encoding from a list of "ttlv" struct
+// tag flags.
+func parseFieldFlags(s string) fieldFlags {
+	var flags fieldFlags
+
+	for _, flag := range strings.Split(s, ",") {
+		switch strings.ToLower(flag) {
+		case "enum":
+			flags |= fEnum
+		case "omitempty":
+			flags |= fOmitEmpty
+		case "datetimeextended":
+			flags |= fDateTimeExtended
+		case "bitmask":
+			flags |= fBitBask
+		case "any":
+			flags |= fAny
+		}
+	}
+
+	return flags
+}
@@ -0,0 +1,212 @@
+package ttlv_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/Seagate/kmip-go/kmip14"
+	. "github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// corpus returns the messages in testdata/corpus, keyed by file name.  The files are
+// in the text format written by Print.  They hold messages from the examples in the
+// KMIP 1.4 test cases, and the edge values of each type.  Files holding more than one
+// value are split, keyed by file name and index.
+func corpus(tb testing.TB) map[string]TTLV {
+	tb.Helper()
+
+	files, err := filepath.Glob(filepath.Join("testdata", "corpus", "*.txt"))
+	require.NoError(tb, err)
+	require.NotEmpty(tb, files)
+
+	messages := map[string]TTLV{}
+
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		require.NoError(tb, err)
+
+		t, err := ParseText(bytes.NewReader(b))
+		require.NoError(tb, err, "%s: %s", file, Details(err))
+
+		name := strings.TrimSuffix(filepath.Base(file), ".txt")
+		if t.Next() == nil {
+			messages[name] = t
+			continue
+		}
+
+		for i := 0; len(t) > 0; i++ {
+			messages[name+"/"+strconv.Itoa(i)] = t[:t.FullLen()]
+			t = t.Next()
+		}
+	}
+
+	// times outside the years ISO8601 can represent, which the text format can't
+	// represent either
+	times, err := Marshal(Value{TagRequestPayload, Values{
+		{TagTimeStamp, time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{TagTimeStamp, time.Date(-1, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{TagArchiveDate, DateTimeExtended{Time: time.Date(10000, 1, 1, 0, 0, 0, 1000, time.UTC)}},
+		{TagArchiveDate, DateTimeExtended{Time: time.Date(-1, 12, 31, 23, 59, 59, 999999000, time.UTC)}},
+		{TagTimeStamp, TTLV(Hex2bytes("420092 09 00000008 7fffffffffffffff"))},
+		{TagArchiveDate, TTLV(Hex2bytes("420005 0b 00000008 8000000000000000"))},
+	}})
+	require.NoError(tb, err)
+
+	messages["times_out_of_range"] = times
+
+	return messages
+}
+
+func TestCorpus_roundTrip(t *testing.T) {
+	for name, msg := range corpus(t) {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, msg.Valid())
+
+			t.Run("json", func(t *testing.T) {
+				b, err := json.Marshal(msg)
+				require.NoError(t, err)
+
+				var parsed TTLV
+				require.NoError(t, json.Unmarshal(b, &parsed), string(b))
+				assert.Equal(t, msg, parsed, Diff(msg, parsed))
+
+				// ...and through the encoder and decoder
+				var buf bytes.Buffer
+				require.NoError(t, NewJSONEncoder(&buf).Encode(msg))
+
+				parsed, err = NewJSONDecoder(&buf).NextTTLV()
+				require.NoError(t, err)
+				assert.Equal(t, msg, parsed, Diff(msg, parsed))
+			})
+
+			t.Run("xml", func(t *testing.T) {
+				b, err := xml.Marshal(msg)
+				require.NoError(t, err)
+
+				var parsed TTLV
+				require.NoError(t, xml.Unmarshal(b, &parsed), string(b))
+				assert.Equal(t, msg, parsed, Diff(msg, parsed))
+
+				var buf bytes.Buffer
+				require.NoError(t, NewXMLEncoder(&buf).Encode(msg))
+
+				parsed, err = NewXMLDecoder(&buf).NextTTLV()
+				require.NoError(t, err)
+				assert.Equal(t, msg, parsed, Diff(msg, parsed))
+			})
+		})
+	}
+}
+
+func FuzzTTLV_Valid(f *testing.F) {
+	for _, msg := range corpus(f) {
+		f.Add([]byte(msg))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		v := TTLV(b)
+		if v.Valid() != nil {
+			return
+		}
+
+		// valid values can be printed, and transcoded to JSON and XML
+		var sb strings.Builder
+		require.NoError(t, Print(&sb, "", "  ", v))
+
+		_, err := json.Marshal(v)
+		require.NoError(t, err)
+
+		_, err = xml.Marshal(v)
+		require.NoError(t, err)
+	})
+}
+
+func FuzzDecoder_NextTTLV(f *testing.F) {
+	for _, msg := range corpus(f) {
+		f.Add([]byte(msg), 0)
+		f.Add([]byte(msg), 16)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte, threshold int) {
+		dec := NewDecoder(bytes.NewReader(b))
+		dec.MaxMessageSize = 1 << 16
+		dec.MaxDepth = 32
+
+		if threshold > 0 {
+			dec.ByteStreamThreshold = threshold
+			dec.ByteStreamWriter = func(Tag, int) (io.Writer, error) {
+				return io.Discard, nil
+			}
+		}
+
+		for {
+			v, err := dec.NextTTLV()
+			if err != nil {
+				return
+			}
+
+			// the enclosed values are checked when decoded
+			require.NoError(t, v.ValidHeader())
+			require.Len(t, v, v.FullLen())
+		}
+	})
+}
+
+func FuzzTTLV_UnmarshalJSON(f *testing.F) {
+	for _, msg := range corpus(f) {
+		b, err := json.Marshal(msg)
+		require.NoError(f, err)
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var v TTLV
+		if json.Unmarshal(b, &v) != nil {
+			return
+		}
+
+		require.NoError(t, v.Valid())
+
+		// anything which can be decoded must round trip
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+
+		var parsed TTLV
+		require.NoError(t, json.Unmarshal(b, &parsed), string(b))
+		require.Equal(t, v, parsed, Diff(v, parsed))
+	})
+}
+
+func FuzzTTLV_UnmarshalXML(f *testing.F) {
+	for _, msg := range corpus(f) {
+		b, err := xml.Marshal(msg)
+		require.NoError(f, err)
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var v TTLV
+		if xml.Unmarshal(b, &v) != nil {
+			return
+		}
+
+		require.NoError(t, v.Valid())
+
+		b, err := xml.Marshal(v)
+		require.NoError(t, err)
+
+		var parsed TTLV
+		require.NoError(t, xml.Unmarshal(b, &parsed), string(b))
+		require.Equal(t, v, parsed, Diff(v, parsed))
+	})
+}
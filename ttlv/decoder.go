@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"reflect"
+	"strconv"
 
 	"github.com/ansel1/merry"
 )
@@ -20,19 +21,24 @@ var ErrUnexpectedValue = errors.New("no field was found to unmarshal value into"
 //
 // Unmarshal will allocate values to store the result in, similar to the
 // json.Marshal.  Generally, the destination value can be a pointer or
-// or a direct value.  Currently, Unmarshal does not support anonymous fields.
-// They will be ignored.  Private fields are ignored.
+// or a direct value.  Private fields are ignored.
 //
 // Unmarshal maps TTLV values to golang values according to the following
 // rules:
 //
 //  1. If the destination value is interface{}, it will be set to the result
-//     of TTLV.Value()
+//     of TTLV.Value(), or the value decoded into the type chosen by the Decoder's
+//     TypeResolver.
 //  2. If the destination implements Unmarshaler, that will be called.
 //  3. If the destination is a slice (except for []byte), append the
 //     unmarshalled value to the slice
 //  4. Structure unmarshals into a struct.  See rules
 //     below for matching struct fields to the values in the Structure.
+//     Structure also unmarshals into a map with Tag or string keys, keyed
+//     by the tag of each value, or the tag's name.  Repeated tags are
+//     collected into a []interface{} if the map's values are interface{}, or
+//     appended if they are slices.  Structures unmarshaled into interface{}
+//     map values are unmarshaled into maps of the same type.
 //  5. Interval unmarshals into an int64
 //  6. DateTime and DateTimeExtended ummarshal into time.Time
 //  7. ByteString unmarshals to a []byte
@@ -48,7 +54,9 @@ var ErrUnexpectedValue = errors.New("no field was found to unmarshal value into"
 //
 // If the destination value is not a supported type,  an *UnmarshalerError with
 // cause ErrUnsupportedTypeError is returned.  If the source value's type is not recognized,
-// *UnmarshalerError with cause ErrInvalidType is returned.
+// *UnmarshalerError with cause ErrInvalidType is returned.  An *UnmarshalerError records
+// where the value is in the message, and which field it was decoded into, like
+// "ResponseMessage/BatchItem[2]/ResponsePayload" and "ResponseMessage.BatchItem[2].ResponsePayload".
 //
 // # Unmarshaling Structure
 //
@@ -120,6 +128,27 @@ var ErrUnexpectedValue = errors.New("no field was found to unmarshal value into"
 // *cannot* map to the same KMIP tag.  If they do, an ErrTagConflict will
 // be returned.
 //
+// The fields of an embedded struct, or pointer to struct, are treated as if they
+// were fields of the enclosing struct, unless the embedded field has a tag in its
+// "ttlv" struct tag.  Embedded fields follow the same rules as encoding/json: a field
+// hides fields with the same tag which are nested more deeply, and of the promoted fields
+// with the same tag at the same depth, one with the tag in its "ttlv" struct tag hides
+// the others.  Other fields with the same tag at the same depth cause an ErrTagConflict.
+// The TTLVTag field of an embedded struct is ignored, and so are the MarshalTTLV and
+// UnmarshalTTLV methods ttlvgen generated for it, which are promoted to the enclosing
+// struct.  Nil pointers to embedded structs are allocated as needed when unmarshaling,
+// and skipped when marshaling.
+//
+//	type Header struct {
+//	  ProtocolVersion ProtocolVersion
+//	  BatchCount      int
+//	}
+//
+//	type Foo struct {
+//	  Header                 // ProtocolVersion and BatchCount are values of the Foo Structure
+//	  BatchCount int         // hides Header.BatchCount
+//	}
+//
 // Each value in the Structure will be matched against the first field
 // in the struct with the same inferred tag.
 //
@@ -135,6 +164,28 @@ var ErrUnexpectedValue = errors.New("no field was found to unmarshal value into"
 //	}
 //
 // If after applying these rules no destination field is found, the KMIP value is ignored.
+//
+// # Validation
+//
+// After unmarshaling a Structure, the struct's fields are checked against these struct
+// tag flags.  Violations return a *ValidationError:
+//
+//	type Foo struct {
+//	    // the Structure must contain a UniqueIdentifier, or the cause is ErrMissingValue
+//	    UniqueIdentifier string `ttlv:",required"`
+//	    // bool and number fields, other than enums, must be pointers to be required,
+//	    // as Marshal can't tell 0 or false from a missing value.
+//	    Sensitive *bool `ttlv:",required"`
+//	    // if present, the value must be between 1 and 10, or the cause is ErrValueOutOfRange.
+//	    // Strings, byte strings, and slices are measured by their length.  Marshal checks
+//	    // the range of 0 too, as it would marshal the field.
+//	    BatchCount int `ttlv:",min=1,max=10"`
+//	    // exactly one field in a oneof group must be present.  If none are, the cause
+//	    // is ErrMissingValue.  If several are, ErrMultipleValues.  "oneof=name" names
+//	    // the group, so a struct can have several groups.
+//	    Certificate  *Certificate  `ttlv:",oneof"`
+//	    SymmetricKey *SymmetricKey `ttlv:",oneof"`
+//	}
 func Unmarshal(ttlv TTLV, v interface{}) error {
 	return NewDecoder(bytes.NewReader(ttlv)).Decode(v)
 }
@@ -147,44 +198,194 @@ type Unmarshaler interface {
 }
 
 // Decoder reads KMIP values from a stream, and decodes them into golang values.
-// It currently only decodes TTLV encoded KMIP values.
-// TODO: support decoding XML and JSON, so their decoding can be configured
+// By default, it decodes TTLV encoded KMIP values.  Decoders created with NewJSONDecoder
+// or NewXMLDecoder read the KMIP JSON or XML encodings instead, and apply the same
+// mapping rules to each value as it is read.
 //
 // If DisallowExtraValues is true, the decoder will return an error when decoding
 // Structures into structs and a matching field can't get found for every value.
+//
+// If View is set, Decode returns an error with cause ErrNotInVersion when a value
+// read from the stream contains tags or enum values which aren't defined in the view's
+// version of the KMIP spec.
+//
+// MaxMessageSize, MaxDepth, and MaxItemsPerStructure limit the values the decoder
+// will read from the stream.  Zero means no limit.  See NextTTLV.
+//
+// If TypeResolver is set, it chooses the types of values decoded into interfaces.
+//
+// If ByteStreamWriter is set, large ByteStrings are streamed to writers instead of being
+// read into memory.
 type Decoder struct {
 	r                   io.Reader
 	bufr                *bufio.Reader
+	stream              textStream
+	text                *textReader
+	format              format
 	DisallowExtraValues bool
+	View                *View
+
+	// MaxMessageSize is the maximum length, in bytes, of a TTLV value, including
+	// its header.
+	MaxMessageSize int
+	// MaxDepth is the maximum nesting depth of Structures.  A Structure which isn't
+	// enclosed in another Structure has depth 1.
+	MaxDepth int
+	// MaxItemsPerStructure is the maximum number of values a Structure may contain.
+	MaxItemsPerStructure int
+
+	TypeResolver TypeResolver
+
+	// Registry, if set, is used instead of DefaultRegistry to match tags to the names
+	// of types and fields, and to parse the JSON and XML encodings.
+	Registry *Registry
+
+	// ByteStreamWriter, if set, is called with the tag and length of each ByteString
+	// longer than ByteStreamThreshold bytes read from the stream.  If it returns a writer,
+	// the value is copied to the writer rather than read into memory, and left empty in the
+	// value returned by NextTTLV.  ByteStream fields are set to the length of the value,
+	// with a nil Reader.  If it returns nil, the value is read as usual.  Streamed values
+	// don't count towards MaxMessageSize.
+	ByteStreamWriter    func(tag Tag, n int) (io.Writer, error)
+	ByteStreamThreshold int
+
+	// streamed are the lengths of the values streamed to ByteStreamWriter, keyed by
+	// the first byte of the empty ByteStrings left in their place
+	streamed map[*byte]int
 
 	currStruct reflect.Type
 	currField  string
+	// parents are the Structures being decoded, outermost first
+	parents []TTLV
+	// root is the outermost value being decoded, which errors report positions in
+	root TTLV
 }
 
+// TypeResolver chooses the go type to decode a value into, when the destination is
+// an interface which doesn't already hold a pointer, like the interface{} fields
+// of structs which can hold several types of values.  v is the value being decoded,
+// and parents are the Structures which enclose it, outermost first, so the type can
+// be chosen according to v's siblings, or the siblings of its parents.  The last
+// parent is the Structure which contains v.
+//
+// The type must be assignable to the destination.  If it is a pointer type, a new
+// value is allocated.  If the TypeResolver returns nil, v is decoded into a TTLV or
+// TTLV.Value() as usual.  parents must not be retained or modified.
+//
+//	dec.TypeResolver = func(v ttlv.TTLV, parents []ttlv.TTLV) (reflect.Type, error) {
+//	    if v.Tag() == kmip14.TagRequestPayload && len(parents) > 0 {
+//	        op, _ := parents[len(parents)-1].Find("Operation")
+//	        if op != nil && kmip14.Operation(op.ValueEnumeration()) == kmip14.OperationGet {
+//	            return reflect.TypeFor[*kmip.GetRequestPayload](), nil
+//	        }
+//	    }
+//	    return nil, nil
+//	}
+type TypeResolver func(v TTLV, parents []TTLV) (reflect.Type, error)
+
+// format identifies the KMIP encoding read by a Decoder or written by an Encoder.
+type format int
+
+const (
+	formatTTLV format = iota
+	formatJSON
+	formatXML
+)
+
 func NewDecoder(r io.Reader) *Decoder {
-	return &Decoder{
-		r:    r,
-		bufr: bufio.NewReader(r),
+	return &Decoder{r: r}
+}
+
+// NewJSONDecoder returns a Decoder which reads KMIP values encoded in the
+// KMIP JSON encoding.  The reader may contain a stream of JSON values, which
+// are decoded one at a time by successive calls to Decode.
+//
+// Decode reads the JSON tokens of a value one at a time, and sets the fields of
+// the destination as they are read, without buffering the value.  Only the values
+// decoded into Unmarshalers, maps, or interfaces holding Structures are transcoded to
+// TTLV first, and so is the whole value if TypeResolver is set, as it is passed the
+// enclosing Structures.  Views and limits are checked as each value is read.
+func NewJSONDecoder(r io.Reader) *Decoder {
+	dec := &Decoder{
+		r:      r,
+		format: formatJSON,
+	}
+	dec.text = &textReader{dec: dec, r: r}
+	dec.stream = newJSONStream(dec, dec.text)
+
+	return dec
+}
+
+// NewXMLDecoder returns a Decoder which reads KMIP values encoded in the
+// KMIP XML encoding.  The reader may contain a stream of XML elements, which
+// are decoded one at a time by successive calls to Decode.  Like NewJSONDecoder,
+// each value is decoded as its tokens are read.
+func NewXMLDecoder(r io.Reader) *Decoder {
+	dec := &Decoder{
+		r:      r,
+		format: formatXML,
 	}
+	dec.text = &textReader{dec: dec, r: r}
+	dec.stream = newXMLStream(dec, dec.text)
+
+	return dec
 }
 
-// Reset resets the internal state of the decoder for reuse.
+// Reset resets the internal state of the decoder for reuse.  The decoder
+// keeps reading the same encoding, and keeps its View, Registry, and limits.
 func (dec *Decoder) Reset(r io.Reader) {
 	*dec = Decoder{
-		r:    r,
-		bufr: dec.bufr,
+		r:                    r,
+		bufr:                 dec.bufr,
+		format:               dec.format,
+		View:                 dec.View,
+		MaxMessageSize:       dec.MaxMessageSize,
+		MaxDepth:             dec.MaxDepth,
+		MaxItemsPerStructure: dec.MaxItemsPerStructure,
+		TypeResolver:         dec.TypeResolver,
+		Registry:             dec.Registry,
+		ByteStreamWriter:     dec.ByteStreamWriter,
+		ByteStreamThreshold:  dec.ByteStreamThreshold,
+		parents:              dec.parents[:0],
 	}
-	dec.bufr.Reset(r)
+
+	switch dec.format {
+	case formatJSON:
+		dec.text = &textReader{dec: dec, r: r}
+		dec.stream = newJSONStream(dec, dec.text)
+	case formatXML:
+		dec.text = &textReader{dec: dec, r: r}
+		dec.stream = newXMLStream(dec, dec.text)
+	default:
+		if dec.bufr != nil {
+			dec.bufr.Reset(r)
+		}
+	}
+}
+
+// registry returns the decoder's Registry, or DefaultRegistry.
+func (dec *Decoder) registry() *Registry {
+	return registryOrDefault(dec.Registry)
 }
 
 // Decode the first KMIP value from the reader into v.
 // See Unmarshal for decoding rules.
 func (dec *Decoder) Decode(v interface{}) error {
+	if dec.stream != nil && dec.TypeResolver == nil {
+		return dec.decodeText(v)
+	}
+
 	ttlv, err := dec.NextTTLV()
 	if err != nil {
 		return err
 	}
 
+	if dec.View != nil {
+		if err := dec.View.Validate(ttlv); err != nil {
+			return err
+		}
+	}
+
 	return dec.DecodeValue(v, ttlv)
 }
 
@@ -197,7 +398,22 @@ func (dec *Decoder) DecodeValue(v interface{}, ttlv TTLV) error {
 		return merry.New("non-pointer passed to Decode")
 	}
 
-	return dec.unmarshal(val, ttlv)
+	if dec.root != nil {
+		// called by an Unmarshaler, while decoding an enclosing value
+		return dec.unmarshal(val, ttlv)
+	}
+
+	dec.root = ttlv
+	defer func() { dec.root = nil }()
+
+	err := dec.unmarshal(val, ttlv)
+	if err != nil {
+		if name := val.Type().Elem().Name(); name != "" {
+			err = prependFieldPath(err, name)
+		}
+	}
+
+	return err
 }
 
 func (dec *Decoder) unmarshal(val reflect.Value, ttlv TTLV) error {
@@ -222,19 +438,24 @@ func (dec *Decoder) unmarshal(val reflect.Value, ttlv TTLV) error {
 		val = val.Elem()
 	}
 
-	if val.Type().Implements(unmarshalerType) {
-		return val.Interface().(Unmarshaler).UnmarshalTTLV(dec, ttlv) //nolint:forcetypeassert
+	uv := val
+	if !implementsOwn(val.Type(), unmarshalerType, dec.registry()) && val.CanAddr() && val.Addr().CanInterface() {
+		uv = val.Addr()
 	}
 
-	if val.CanAddr() {
-		valAddr := val.Addr()
-		if valAddr.CanInterface() && valAddr.Type().Implements(unmarshalerType) {
-			return valAddr.Interface().(Unmarshaler).UnmarshalTTLV(dec, ttlv) //nolint:forcetypeassert
-		}
+	if implementsOwn(uv.Type(), unmarshalerType, dec.registry()) {
+		return uv.Interface().(Unmarshaler).UnmarshalTTLV(dec, ttlv) //nolint:forcetypeassert
 	}
 
 	switch val.Kind() {
 	case reflect.Interface:
+		if dec.TypeResolver != nil {
+			resolved, err := dec.resolve(val, ttlv)
+			if resolved || err != nil {
+				return err
+			}
+		}
+
 		if ttlv.Type() == TypeStructure {
 			// if the value is a structure, set the whole TTLV
 			// as the value.
@@ -260,7 +481,7 @@ func (dec *Decoder) unmarshal(val reflect.Value, ttlv TTLV) error {
 		// Recur to read element into slice.
 		if err := dec.unmarshal(val.Index(n), ttlv); err != nil {
 			val.SetLen(n)
-			return err
+			return prependFieldPath(err, "["+strconv.Itoa(n)+"]")
 		}
 
 		return nil
@@ -275,6 +496,7 @@ func (dec *Decoder) unmarshal(val reflect.Value, ttlv TTLV) error {
 			Type:   ttlv.Type(),
 			Val:    val.Type(),
 		}
+		dec.locate(e, ttlv)
 		err := merry.WrapSkipping(e, 1).WithCause(ErrUnsupportedTypeError)
 
 		return err
@@ -282,6 +504,10 @@ func (dec *Decoder) unmarshal(val reflect.Value, ttlv TTLV) error {
 
 	switch ttlv.Type() {
 	case TypeStructure:
+		if val.Kind() == reflect.Map {
+			return dec.unmarshalMap(ttlv, val)
+		}
+
 		if val.Kind() != reflect.Struct {
 			return typeMismatchErr()
 		}
@@ -385,7 +611,7 @@ func (dec *Decoder) unmarshal(val reflect.Value, ttlv TTLV) error {
 }
 
 func (dec *Decoder) unmarshalStructure(ttlv TTLV, val reflect.Value) error {
-	ti, err := getTypeInfo(val.Type())
+	ti, err := getTypeInfo(val.Type(), dec.registry())
 	if err != nil {
 		return dec.newUnmarshalerError(ttlv, val.Type(), err)
 	}
@@ -396,49 +622,126 @@ func (dec *Decoder) unmarshalStructure(ttlv TTLV, val reflect.Value) error {
 
 	fields := ti.valueFields
 
+	// if the struct has validation flags, track which fields were found
+	var present []bool
+	if ti.validate {
+		present = make([]bool, len(fields))
+	}
+
+	if err := dec.EnterStructure(ttlv, val.Type()); err != nil {
+		return err
+	}
+	defer dec.LeaveStructure()
+
 	// push currStruct (caller will pop)
 	dec.currStruct = val.Type()
 
 	for n := ttlv.ValueStructure(); n != nil; n = n.Next() {
-		fldIdx := -1
-
-		for i := range fields {
-			if fields[i].flags.any() {
-				// if this is the first any field found, keep track
-				// of it as the current candidate match, but
-				// keep looking for a tag match
-				if fldIdx == -1 {
-					fldIdx = i
-				}
-			} else if fields[i].tag == n.Tag() {
-				// tag match found
-				// we can stop looking
-				fldIdx = i
-				break
+		fldIdx := ti.fieldFor(n.Tag())
+		if fldIdx > -1 {
+			if present != nil {
+				present[fldIdx] = true
 			}
-		}
 
-		if fldIdx > -1 {
 			// push currField
 			currField := dec.currField
 			dec.currField = fields[fldIdx].name
-			err := dec.unmarshal(val.FieldByIndex(fields[fldIdx].index), n)
+			var err error
+			if fv, ok := allocFieldByIndex(val, fields[fldIdx].index); ok {
+				err = dec.unmarshal(fv, n)
+			} else {
+				err = dec.newUnmarshalerError(n, fv.Type(), ErrUnsupportedTypeError)
+			}
 			// restore currField
 			dec.currField = currField
 
 			if err != nil {
-				return err
+				return prependFieldPath(err, fields[fldIdx].name)
 			}
 		} else if dec.DisallowExtraValues {
 			return dec.newUnmarshalerError(ttlv, val.Type(), ErrUnexpectedValue)
 		}
 	}
 
+	if present != nil {
+		return validateFields(val, ti, present, present)
+	}
+
 	return nil
 }
 
-// NextTTLV reads the next, full KMIP value off the reader.
+// resolve decodes ttlv into interface val, if the TypeResolver chooses a type for it.
+// Returns false if it doesn't.
+func (dec *Decoder) resolve(val reflect.Value, ttlv TTLV) (bool, error) {
+	typ, err := dec.TypeResolver(ttlv, dec.parents)
+	if err != nil {
+		return false, dec.newUnmarshalerError(ttlv, val.Type(), err)
+	}
+
+	if typ == nil {
+		return false, nil
+	}
+
+	if typ.Kind() == reflect.Interface || !typ.AssignableTo(val.Type()) {
+		return false, merry.Prependf(dec.newUnmarshalerError(ttlv, typ, ErrUnsupportedTypeError), "resolved type isn't assignable to %v", val.Type())
+	}
+
+	v := reflect.New(typ).Elem()
+	if err := dec.unmarshal(v, ttlv); err != nil {
+		return false, err
+	}
+
+	val.Set(v)
+
+	return true, nil
+}
+
+// allocFieldByIndex returns the nested field of val corresponding to index,
+// allocating nil pointers to embedded structs along the way.  It returns false,
+// and the pointer, if a pointer to an unexported struct type can't be allocated.
+func allocFieldByIndex(val reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				if !val.CanSet() {
+					// pointer to an unexported struct type
+					return val, false
+				}
+
+				val.Set(reflect.New(val.Type().Elem()))
+			}
+
+			val = val.Elem()
+		}
+
+		val = val.Field(x)
+	}
+
+	return val, true
+}
+
+// NextTTLV reads the next, full KMIP value off the reader.  If the decoder
+// reads JSON or XML, the value is transcoded into a new TTLV buffer.
+//
+// If the value exceeds the decoder's limits, NextTTLV returns an error with cause
+// ErrMessageTooLarge, ErrMaxDepthExceeded, or ErrTooManyItems.  When reading TTLV,
+// the message size is checked against the length in the header, before the value is
+// read, so an oversized message is never buffered.  The stream is left positioned at
+// the start of the rejected value, so the decoder can't be used to read further values.
+// When reading JSON or XML, the text of a value may be up to 16 times MaxMessageSize,
+// and longer text is rejected as it is read.
 func (dec *Decoder) NextTTLV() (TTLV, error) {
+	if dec.stream != nil {
+		return dec.nextText()
+	}
+
+	dec.streamed = nil
+
+	// the buffered reader is only needed to read from the stream, not by DecodeValue
+	if dec.bufr == nil {
+		dec.bufr = bufio.NewReader(dec.r)
+	}
+
 	// first, read the header
 	header, err := dec.bufr.Peek(8)
 	if err != nil {
@@ -450,8 +753,16 @@ func (dec *Decoder) NextTTLV() (TTLV, error) {
 		return TTLV(header), merry.Prependf(err, "invalid header: %v", TTLV(header))
 	}
 
+	if dec.ByteStreamWriter != nil {
+		return dec.nextStreamingTTLV()
+	}
+
 	// allocate a buffer large enough for the entire message
 	fullLen := TTLV(header).FullLen()
+	if dec.MaxMessageSize > 0 && fullLen > dec.MaxMessageSize {
+		return TTLV(header), merry.Here(ErrMessageTooLarge).Appendf("message length %d exceeds limit of %d", fullLen, dec.MaxMessageSize)
+	}
+
 	buf := make([]byte, fullLen)
 
 	var totRead int
@@ -459,13 +770,13 @@ func (dec *Decoder) NextTTLV() (TTLV, error) {
 	for {
 		n, err := dec.bufr.Read(buf[totRead:])
 		if err != nil {
-			return TTLV(buf), merry.Wrap(err)
+			return TTLV(buf), dec.withPosition(err, buf, totRead+n)
 		}
 
 		totRead += n
 		if totRead >= fullLen {
 			// we've read off a single full message
-			return buf, nil
+			return buf, dec.checkLimits(buf)
 		} // else keep reading
 	}
 }
@@ -478,10 +789,24 @@ func (dec *Decoder) newUnmarshalerError(ttlv TTLV, valType reflect.Type, cause e
 		Type:   ttlv.Type(),
 		Val:    valType,
 	}
+	dec.locate(e, ttlv)
 
 	return merry.WrapSkipping(e, 1).WithCause(cause)
 }
 
+// locate sets the Path and Offset of e to the position of ttlv in the value being decoded.
+func (dec *Decoder) locate(e *UnmarshalerError, ttlv TTLV) {
+	root := dec.root
+	if root == nil && len(dec.parents) > 0 {
+		root = dec.parents[0]
+	}
+
+	e.Offset = offsetOf(root, ttlv)
+	if e.Offset >= 0 {
+		e.Path = pathAt(root, e.Offset, dec.registry())
+	}
+}
+
 type UnmarshalerError struct {
 	// Val is the type of the destination value
 	Val reflect.Type
@@ -491,6 +816,15 @@ type UnmarshalerError struct {
 	Field string
 	Tag   Tag
 	Type  Type
+	// Path is the path of the value from the outermost value being decoded, like
+	// "ResponseMessage/BatchItem[1]/ResponsePayload/UniqueIdentifier".  See ErrorPath.
+	Path string
+	// FieldPath is the path of the destination value, from the type of the outermost
+	// value being decoded, like "ResponseMessage.BatchItem[1].ResponsePayload".
+	FieldPath string
+	// Offset is the offset in bytes of the value from the start of the outermost value
+	// being decoded, or -1 if the value isn't part of it, or was read from JSON or XML.
+	Offset int
 }
 
 func (e *UnmarshalerError) Error() string {
@@ -499,5 +833,17 @@ func (e *UnmarshalerError) Error() string {
 		msg += " in struct field " + e.Struct.Name() + "." + e.Field
 	}
 
+	if e.FieldPath != "" {
+		msg += " (" + e.FieldPath + ")"
+	}
+
+	switch {
+	case e.Offset >= 0:
+		msg += " at offset " + strconv.Itoa(e.Offset) + " in " + e.Path
+	case e.Path != "":
+		msg += " in " + e.Path
+	default:
+	}
+
 	return msg
 }
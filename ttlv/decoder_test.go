@@ -2,11 +2,15 @@ package ttlv_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -513,6 +517,112 @@ func TestUnmarshal_tagPrecedence(t *testing.T) {
 	assert.True(t, merry.Is(err, ErrTagConflict))
 }
 
+func TestUnmarshal_embedded(t *testing.T) {
+	type Name struct {
+		NameValue string
+		NameType  NameType
+	}
+
+	type Header struct {
+		Comment string
+		*Name
+	}
+
+	type A struct {
+		Header
+		NameType int
+	}
+
+	b, err := Marshal(Value{TagAlternativeName, Values{
+		{TagComment, "red"},
+		{TagNameValue, "blue"},
+		{TagNameType, NameTypeURI},
+	}})
+	require.NoError(t, err)
+
+	var a A
+
+	require.NoError(t, Unmarshal(b, &a))
+	require.Equal(t, A{
+		Header: Header{
+			Comment: "red",
+			Name:    &Name{NameValue: "blue"},
+		},
+		NameType: int(NameTypeURI),
+	}, a)
+
+	// round trip
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, NewEncoder(buf).EncodeValue(TagAlternativeName, a))
+	require.Equal(t, b, TTLV(buf.Bytes()))
+
+	// untagged fields with the same tag at the same depth are ambiguous
+	type B struct {
+		Comment string
+	}
+
+	err = Unmarshal(b, &struct {
+		Header
+		B
+	}{})
+	require.Error(t, err)
+	assert.True(t, merry.Is(err, ErrTagConflict), "%+v", err)
+
+	// so are tagged ones
+	type C struct {
+		C string `ttlv:"Comment"`
+	}
+
+	type D struct {
+		D string `ttlv:"Comment"`
+	}
+
+	err = Unmarshal(b, &struct {
+		C
+		D
+	}{})
+	require.Error(t, err)
+	assert.True(t, merry.Is(err, ErrTagConflict), "%+v", err)
+}
+
+func TestUnmarshal_embeddedDominance(t *testing.T) {
+	type A struct {
+		Comment string
+	}
+
+	type B struct {
+		C string `ttlv:"Comment"`
+	}
+
+	b, err := Marshal(Value{TagAlternativeName, Values{
+		{TagComment, "red"},
+	}})
+	require.NoError(t, err)
+
+	// the shallowest field wins
+	var top struct {
+		TTLVTag struct{} `ttlv:"AlternativeName"`
+		A
+		B
+		Comment string
+	}
+
+	require.NoError(t, Unmarshal(b, &top))
+	assert.Equal(t, "red", top.Comment)
+	assert.Empty(t, top.A.Comment)
+	assert.Empty(t, top.B.C)
+
+	// of the fields at the same depth, the tagged field wins
+	var tagged struct {
+		A
+		B
+	}
+
+	require.NoError(t, Unmarshal(b, &tagged))
+	assert.Equal(t, "red", tagged.B.C)
+	assert.Empty(t, tagged.A.Comment)
+}
+
 func TestDecoder_DisallowUnknownFields(t *testing.T) {
 	type A struct {
 		Comment    string
@@ -580,3 +690,610 @@ func TestDecoder_DisallowUnknownFields(t *testing.T) {
 		})
 	}
 }
+
+func TestDecoder_JSONAndXML(t *testing.T) {
+	type A struct {
+		Comment    string
+		BatchCount int
+		ObjectType ObjectType
+	}
+
+	b1, err := Marshal(Value{TagAlternativeName, Values{
+		{TagComment, "red"},
+		{TagBatchCount, 5},
+		{TagObjectType, ObjectTypeSymmetricKey},
+	}})
+	require.NoError(t, err)
+
+	b2, err := Marshal(Value{TagAlternativeName, Values{
+		{TagComment, "blue"},
+		{TagArchiveDate, "green"},
+	}})
+	require.NoError(t, err)
+
+	j1, err := json.Marshal(b1)
+	require.NoError(t, err)
+	j2, err := json.Marshal(b2)
+	require.NoError(t, err)
+	x1, err := xml.Marshal(b1)
+	require.NoError(t, err)
+	x2, err := xml.Marshal(b2)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		newDec func(r io.Reader) *Decoder
+		input  string
+	}{
+		{name: "json", newDec: NewJSONDecoder, input: string(j1) + "\n" + string(j2)},
+		{name: "xml", newDec: NewXMLDecoder, input: string(x1) + "\n" + string(x2)},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.name, func(t *testing.T) {
+			dec := testcase.newDec(strings.NewReader(testcase.input))
+
+			var a A
+			require.NoError(t, dec.Decode(&a))
+			require.Equal(t, A{"red", 5, ObjectTypeSymmetricKey}, a)
+
+			dec.DisallowExtraValues = true
+			err := dec.Decode(&a)
+			require.Error(t, err)
+			require.True(t, merry.Is(err, ErrUnexpectedValue))
+
+			err = dec.Decode(&a)
+			require.True(t, errors.Is(err, io.EOF), "%+v", err)
+
+			// after reset, the decoder should still read the same format
+			dec.Reset(strings.NewReader(testcase.input))
+
+			var tv TTLV
+			require.NoError(t, dec.Decode(&tv))
+			require.Equal(t, b1, tv)
+		})
+	}
+}
+
+func TestDecoder_JSONAndXMLDirect(t *testing.T) {
+	type Attr struct {
+		AttributeName  string
+		AttributeValue interface{}
+	}
+
+	type B struct {
+		BatchCount     []int
+		ActivationDate time.Time
+		Attribute      []Attr
+		Name           TTLV
+		ArchiveDate    DateTimeExtended
+	}
+
+	type A struct {
+		Comment string
+		B       *B `ttlv:"AlternativeName"`
+	}
+
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	b, err := Marshal(Value{TagKeyValue, Values{
+		{TagComment, "red"},
+		{TagAlternativeName, Values{
+			{TagBatchCount, 1},
+			{TagBatchCount, 2},
+			{TagActivationDate, now},
+			{TagAttribute, Values{
+				{TagAttributeName, "Object Type"},
+				{TagAttributeValue, ObjectTypeSymmetricKey},
+			}},
+			{TagName, Values{
+				{TagNameValue, "blue"},
+			}},
+			{TagArchiveDate, DateTimeExtended{now}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	var expected A
+	require.NoError(t, Unmarshal(b, &expected))
+	require.Equal(t, EnumValue(ObjectTypeSymmetricKey), expected.B.Attribute[0].AttributeValue)
+	// Unmarshal leaves the rest of the message in TTLV fields
+	expected.B.Name = expected.B.Name[:expected.B.Name.FullLen()]
+
+	// the second message has a BatchCount which doesn't fit into an int8
+	bad, err := Marshal(Value{TagKeyValue, Values{
+		{TagComment, "red"},
+		{TagAlternativeName, Values{
+			{TagBatchCount, 1},
+			{TagBatchCount, 300},
+			{TagName, Values{}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	type Bad struct {
+		Comment string
+		B       struct {
+			BatchCount []int8
+		} `ttlv:"AlternativeName"`
+	}
+
+	j, err := json.Marshal(b)
+	require.NoError(t, err)
+	jBad, err := json.Marshal(bad)
+	require.NoError(t, err)
+	x, err := xml.Marshal(b)
+	require.NoError(t, err)
+	xBad, err := xml.Marshal(bad)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		newDec func(r io.Reader) *Decoder
+		input  string
+	}{
+		{name: "json", newDec: NewJSONDecoder, input: string(j) + string(jBad) + string(j)},
+		{name: "xml", newDec: NewXMLDecoder, input: string(x) + string(xBad) + string(x)},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.name, func(t *testing.T) {
+			dec := testcase.newDec(strings.NewReader(testcase.input))
+
+			var a A
+			require.NoError(t, dec.Decode(&a))
+			assert.Equal(t, expected, a)
+
+			// errors have the path of the value, and the rest of the message is skipped
+			var v Bad
+			err := dec.Decode(&v)
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrIntOverflow), "%+v", err)
+			assert.Equal(t, "KeyValue/AlternativeName/BatchCount[1]", ErrorPath(err))
+			assert.Equal(t, -1, ErrorOffset(err))
+
+			var a2 A
+			require.NoError(t, dec.Decode(&a2))
+			assert.Equal(t, expected, a2)
+		})
+	}
+}
+
+func TestUnmarshal_validation(t *testing.T) {
+	type A struct {
+		Comment        string   `ttlv:",required"`
+		BatchCount     int      `ttlv:",min=1,max=10"`
+		NameValue      []string `ttlv:",max=2"`
+		ArchiveDate    string   `ttlv:",oneof"`
+		ActivationDate string   `ttlv:",oneof"`
+	}
+
+	tests := []struct {
+		name  string
+		input Values
+		err   error
+	}{
+		{
+			name:  "valid",
+			input: Values{{TagComment, "red"}, {TagBatchCount, 10}, {TagNameValue, "a"}, {TagNameValue, "b"}, {TagArchiveDate, "blue"}},
+		},
+		{
+			name:  "zerovaluepresent",
+			input: Values{{TagComment, ""}, {TagActivationDate, ""}},
+		},
+		{
+			name:  "missingrequired",
+			input: Values{{TagBatchCount, 5}, {TagArchiveDate, "blue"}},
+			err:   ErrMissingValue,
+		},
+		{
+			name:  "outofrange",
+			input: Values{{TagComment, "red"}, {TagBatchCount, 11}, {TagArchiveDate, "blue"}},
+			err:   ErrValueOutOfRange,
+		},
+		{
+			name:  "toolong",
+			input: Values{{TagComment, "red"}, {TagNameValue, "a"}, {TagNameValue, "b"}, {TagNameValue, "c"}, {TagArchiveDate, "blue"}},
+			err:   ErrValueOutOfRange,
+		},
+		{
+			name:  "missingoneof",
+			input: Values{{TagComment, "red"}},
+			err:   ErrMissingValue,
+		},
+		{
+			name:  "multipleoneof",
+			input: Values{{TagComment, "red"}, {TagArchiveDate, "blue"}, {TagActivationDate, "green"}},
+			err:   ErrMultipleValues,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := Marshal(Value{TagAlternativeName, tc.input})
+			require.NoError(t, err)
+
+			err = Unmarshal(b, &A{})
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			require.True(t, merry.Is(err, tc.err), "%+v", err)
+
+			var verr *ValidationError
+			require.True(t, errors.As(err, &verr), "%+v", err)
+			require.Equal(t, reflect.TypeOf(A{}), verr.Struct)
+		})
+	}
+}
+
+func TestDecoder_limits(t *testing.T) {
+	nested := func(depth int) Value {
+		v := Value{TagComment, "red"}
+		for i := 0; i < depth; i++ {
+			v = Value{TagAlternativeName, Values{v}}
+		}
+
+		return v
+	}
+
+	b3, err := Marshal(nested(3))
+	require.NoError(t, err)
+
+	items, err := Marshal(Value{TagAlternativeName, Values{
+		{TagComment, "red"},
+		{TagComment, "blue"},
+		{TagComment, "green"},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		input  TTLV
+		limits Decoder
+		err    error
+	}{
+		{name: "nolimits", input: b3},
+		{name: "size", input: b3, limits: Decoder{MaxMessageSize: len(b3)}},
+		{name: "sizeexceeded", input: b3, limits: Decoder{MaxMessageSize: len(b3) - 1}, err: ErrMessageTooLarge},
+		{name: "depth", input: b3, limits: Decoder{MaxDepth: 3}},
+		{name: "depthexceeded", input: b3, limits: Decoder{MaxDepth: 2}, err: ErrMaxDepthExceeded},
+		{name: "items", input: items, limits: Decoder{MaxItemsPerStructure: 3}},
+		{name: "itemsexceeded", input: items, limits: Decoder{MaxItemsPerStructure: 2}, err: ErrTooManyItems},
+	}
+
+	for _, tc := range tests {
+		j, err := json.Marshal(tc.input)
+		require.NoError(t, err)
+		x, err := xml.Marshal(tc.input)
+		require.NoError(t, err)
+
+		formats := []struct {
+			name   string
+			newDec func(r io.Reader) *Decoder
+			input  []byte
+		}{
+			{name: "ttlv", newDec: NewDecoder, input: tc.input},
+			{name: "json", newDec: NewJSONDecoder, input: j},
+			{name: "xml", newDec: NewXMLDecoder, input: x},
+		}
+
+		for _, f := range formats {
+			t.Run(tc.name+"/"+f.name, func(t *testing.T) {
+				dec := f.newDec(bytes.NewReader(nil))
+				dec.MaxMessageSize = tc.limits.MaxMessageSize
+				dec.MaxDepth = tc.limits.MaxDepth
+				dec.MaxItemsPerStructure = tc.limits.MaxItemsPerStructure
+
+				// limits should survive a reset
+				dec.Reset(bytes.NewReader(f.input))
+
+				_, err := dec.NextTTLV()
+				if tc.err == nil {
+					require.NoError(t, err)
+					return
+				}
+
+				require.Error(t, err)
+				require.True(t, merry.Is(err, tc.err), "%+v", err)
+			})
+		}
+	}
+}
+
+func TestDecoder_MaxMessageSizeHeader(t *testing.T) {
+	// the header claims a value of almost 4GB.  It should be rejected before the decoder
+	// tries to allocate a buffer for it, or read the rest of it.
+	dec := NewDecoder(bytes.NewReader([]byte{0x42, 0x00, 0x04, 0x08, 0xff, 0xff, 0xff, 0xf0}))
+	dec.MaxMessageSize = 1 << 20
+
+	_, err := dec.NextTTLV()
+	require.Error(t, err)
+	require.True(t, merry.Is(err, ErrMessageTooLarge), "%+v", err)
+}
+
+// endlessReader returns head, followed by item repeated forever.  It records the number
+// of bytes read.
+type endlessReader struct {
+	head, item []byte
+	n          int
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		if r.n < len(r.head) {
+			p[i] = r.head[r.n]
+		} else {
+			p[i] = r.item[(r.n-len(r.head))%len(r.item)]
+		}
+
+		r.n++
+	}
+
+	return len(p), nil
+}
+
+func TestDecoder_MaxMessageSizeText(t *testing.T) {
+	const maxMessageSize = 1 << 10
+
+	// the text never ends.  It should be rejected once it exceeds the limit, rather
+	// than read into memory
+	formats := []struct {
+		name   string
+		newDec func(r io.Reader) *Decoder
+		input  *endlessReader
+	}{
+		{name: "json", newDec: NewJSONDecoder, input: &endlessReader{
+			head: []byte(`{"tag":"AlternativeName","value":[`),
+			item: []byte(`{"tag":"Comment","type":"TextString","value":"red"},`),
+		}},
+		{name: "xml", newDec: NewXMLDecoder, input: &endlessReader{
+			head: []byte(`<AlternativeName>`),
+			item: []byte(`<Comment type="TextString" value="red"/>`),
+		}},
+	}
+
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			dec := f.newDec(f.input)
+			dec.MaxMessageSize = maxMessageSize
+
+			_, err := dec.NextTTLV()
+			require.Error(t, err)
+			require.True(t, merry.Is(err, ErrMessageTooLarge), "%+v", err)
+			assert.LessOrEqual(t, f.input.n, 16*maxMessageSize)
+		})
+	}
+
+	// the limit applies to each value in a stream, not the whole stream
+	b, err := Marshal(Value{TagComment, "red"})
+	require.NoError(t, err)
+
+	j, err := json.Marshal(b)
+	require.NoError(t, err)
+
+	stream := bytes.Repeat(j, 20*16*len(b)/len(j))
+
+	dec := NewJSONDecoder(bytes.NewReader(stream))
+	dec.MaxMessageSize = len(b)
+
+	for i := 0; i < 20*16*len(b)/len(j); i++ {
+		_, err := dec.NextTTLV()
+		require.NoError(t, err)
+	}
+}
+
+func TestDecoder_DecodeValueMaxDepth(t *testing.T) {
+	type node struct {
+		AlternativeName *node
+	}
+
+	b, err := Marshal(Value{TagAlternativeName, Values{
+		{TagAlternativeName, Values{
+			{TagAlternativeName, Values{}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	dec := NewDecoder(nil)
+	dec.MaxDepth = 2
+
+	var n node
+	err = dec.DecodeValue(&n, b)
+	require.Error(t, err)
+	require.True(t, merry.Is(err, ErrMaxDepthExceeded), "%+v", err)
+
+	dec.MaxDepth = 3
+	require.NoError(t, dec.DecodeValue(&n, b))
+}
+
+func TestDecoder_TypeResolver(t *testing.T) {
+	type keyMaterial struct {
+		P int
+	}
+
+	type keyValue struct {
+		KeyMaterial interface{}
+	}
+
+	type keyBlock struct {
+		KeyFormatType KeyFormatType
+		KeyValue      keyValue
+	}
+
+	b, err := Marshal(Value{TagKeyBlock, Values{
+		{TagKeyFormatType, KeyFormatTypeTransparentRSAPrivateKey},
+		{TagKeyValue, Values{
+			{TagKeyMaterial, Values{
+				{TagP, 5},
+			}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	var parents []Tag
+
+	resolver := func(v TTLV, p []TTLV) (reflect.Type, error) {
+		parents = parents[:0]
+		for _, s := range p {
+			parents = append(parents, s.Tag())
+		}
+
+		// choose the type from the KeyFormatType in the grandparent
+		if f, _ := p[0].Find("KeyFormatType"); f != nil && KeyFormatType(f.ValueEnumeration()) == KeyFormatTypeTransparentRSAPrivateKey {
+			return reflect.TypeFor[*keyMaterial](), nil
+		}
+
+		return nil, nil
+	}
+
+	dec := NewDecoder(nil)
+	dec.TypeResolver = resolver
+
+	var kb keyBlock
+	require.NoError(t, dec.DecodeValue(&kb, b))
+	assert.Equal(t, &keyMaterial{P: 5}, kb.KeyValue.KeyMaterial)
+	assert.Equal(t, []Tag{TagKeyBlock, TagKeyValue}, parents)
+
+	// values are decoded as usual if the resolver returns nil
+	dec.TypeResolver = func(TTLV, []TTLV) (reflect.Type, error) {
+		return nil, nil
+	}
+
+	kb = keyBlock{}
+	require.NoError(t, dec.DecodeValue(&kb, b))
+	assert.IsType(t, TTLV{}, kb.KeyValue.KeyMaterial)
+
+	// resolved types must be assignable to the destination
+	type stringer struct {
+		KeyMaterial fmt.Stringer
+	}
+
+	dec.TypeResolver = func(TTLV, []TTLV) (reflect.Type, error) {
+		return reflect.TypeFor[keyMaterial](), nil
+	}
+
+	var s stringer
+	err = dec.DecodeValue(&s, b.ValueStructure().Next())
+	require.Error(t, err)
+	require.True(t, merry.Is(err, ErrUnsupportedTypeError), "%+v", err)
+
+	// resolver errors are returned
+	errResolve := errors.New("boom")
+	dec.TypeResolver = func(TTLV, []TTLV) (reflect.Type, error) {
+		return nil, errResolve
+	}
+
+	err = dec.DecodeValue(&kb, b)
+	require.ErrorIs(t, err, errResolve)
+}
+
+func TestDecoder_errorPosition(t *testing.T) {
+	type capabilities struct {
+		UnwrapMode UnwrapMode
+	}
+
+	type payload struct {
+		CapabilityInformation []capabilities
+	}
+
+	type batchItem struct {
+		ResponsePayload payload
+	}
+
+	type message struct {
+		TTLVTag   struct{} `ttlv:"ResponseMessage"`
+		BatchItem []batchItem
+	}
+
+	b, err := Marshal(Value{TagResponseMessage, Values{
+		{TagBatchItem, Values{}},
+		{TagBatchItem, Values{}},
+		{TagBatchItem, Values{
+			{TagResponsePayload, Values{
+				{TagCapabilityInformation, Values{
+					{TagUnwrapMode, UnwrapModeProcessed},
+				}},
+				{TagCapabilityInformation, Values{
+					{TagUnwrapMode, "processed"},
+				}},
+			}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	assertPosition := func(t *testing.T, err error, fieldPath string) {
+		t.Helper()
+
+		require.Error(t, err)
+
+		var ue *UnmarshalerError
+		require.True(t, errors.As(err, &ue), "expected *UnmarshalerError, got %v", err)
+		assert.Equal(t, "ResponseMessage/BatchItem[2]/ResponsePayload/CapabilityInformation[1]/UnwrapMode", ue.Path)
+		assert.Equal(t, fieldPath, ue.FieldPath)
+		assert.Equal(t, ue.Path, ErrorPath(err))
+		assert.Equal(t, ue.Offset, ErrorOffset(err))
+		assert.Contains(t, err.Error(), ue.Path)
+
+		// the offset and path both locate the bad value
+		require.Positive(t, ue.Offset)
+		v := TTLV(b[ue.Offset:])
+		assert.Equal(t, TagUnwrapMode, v.Tag())
+		assert.Equal(t, TypeTextString, v.Type())
+
+		// the rest of the path selects the value from the message
+		root, rest, _ := strings.Cut(ue.Path, "/")
+		assert.Equal(t, "ResponseMessage", root)
+
+		p, err := ParsePath(rest)
+		require.NoError(t, err)
+		assert.Equal(t, v[:v.FullLen()], p.Find(b))
+	}
+
+	var m message
+
+	assertPosition(t, Unmarshal(b, &m), "message.BatchItem[2].ResponsePayload.CapabilityInformation[1].UnwrapMode")
+
+	type typedMap map[string][]map[Tag]map[string][]capabilities
+
+	var tm typedMap
+
+	assertPosition(t, Unmarshal(b, &tm), "typedMap[BatchItem][2][ResponsePayload][CapabilityInformation][1].UnwrapMode")
+}
+
+func TestDecoder_NextTTLV_truncatedPosition(t *testing.T) {
+	b, err := Marshal(Value{TagRequestMessage, Values{
+		{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+		}},
+		{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+			{TagRequestPayload, Values{
+				{TagUniqueIdentifier, "key1"},
+			}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	truncated := b[:len(b)-6]
+
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprint("streaming=", streaming), func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader(truncated))
+			if streaming {
+				dec.ByteStreamWriter = func(Tag, int) (io.Writer, error) { return nil, nil }
+			}
+
+			_, err := dec.NextTTLV()
+			require.Error(t, err)
+			assert.Equal(t, len(truncated), ErrorOffset(err))
+			assert.Equal(t, "RequestMessage/BatchItem[1]/RequestPayload/UniqueIdentifier", ErrorPath(err))
+			assert.Contains(t, err.Error(), "RequestMessage/BatchItem[1]/RequestPayload/UniqueIdentifier")
+		})
+	}
+
+	// errors without a position
+	assert.Equal(t, -1, ErrorOffset(io.EOF))
+	assert.Empty(t, ErrorPath(io.EOF))
+}
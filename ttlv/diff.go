@@ -0,0 +1,234 @@
+package ttlv
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DifferenceKind describes how a value differs between two TTLV values.
+type DifferenceKind int
+
+const (
+	// Added values are only in the second value.
+	Added DifferenceKind = iota
+	// Removed values are only in the first value.
+	Removed
+	// Changed values are in both, but have different types or values.
+	Changed
+)
+
+func (k DifferenceKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	default:
+		return "DifferenceKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Difference is a single difference between two TTLV values, reported by Diff.
+type Difference struct {
+	Kind DifferenceKind
+	// Path locates the value, relative to the values passed to Diff, in the syntax
+	// of Path, e.g. "BatchItem[1]/ResultStatus".  Values which share a tag with other
+	// values in the same Structure are indexed.  The Path of the values passed to Diff is "".
+	Path string
+	// A is the value in the first TTLV value.  It is nil if Kind is Added.
+	A TTLV
+	// B is the value in the second TTLV value.  It is nil if Kind is Removed.
+	B TTLV
+}
+
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "."
+	}
+
+	switch d.Kind {
+	case Added:
+		return "+ " + path + ": " + describeValue(d.B)
+	case Removed:
+		return "- " + path + ": " + describeValue(d.A)
+	default:
+		return "~ " + path + ": " + describeValue(d.A) + " -> " + describeValue(d.B)
+	}
+}
+
+// describeValue formats a value for Difference.String(), without its tag or any
+// nested values.
+func describeValue(t TTLV) string {
+	if err := t.ValidHeader(); err != nil {
+		return fmt.Sprintf("%#x (%v)", []byte(t), err)
+	}
+
+	if t.Type() == TypeStructure {
+		return t.Tag().String() + " (Structure)"
+	}
+
+	// Print formats the value after the header description
+	var sb strings.Builder
+
+	_ = Print(&sb, "", "", t)
+
+	s := sb.String()
+	if i := strings.Index(s, ": "); i >= 0 {
+		s = s[i+2:]
+	}
+
+	return s + " (" + t.Type().String() + ")"
+}
+
+// DiffOption configures Diff.
+type DiffOption func(d *differ)
+
+// IgnoreTags makes Diff ignore values with any of the tags, wherever they
+// appear.
+func IgnoreTags(tags ...Tag) DiffOption {
+	return func(d *differ) {
+		for _, t := range tags {
+			d.ignore[t] = true
+		}
+	}
+}
+
+// IgnoreVolatile makes Diff ignore the values which are expected to be different
+// in every message: TimeStamp, ServerCorrelationValue, and UniqueBatchItemID.
+func IgnoreVolatile() DiffOption {
+	return IgnoreTags(tagTimeStamp, tagServerCorrelationValue, tagUniqueBatchItemID)
+}
+
+type differ struct {
+	ignore map[Tag]bool
+	diffs  []Difference
+}
+
+// Diff compares two TTLV values, and returns their differences.  Returns nil if the
+// values are equal.  Within each Structure, differences are reported in the order of the
+// values in a, followed by the values which were added in b.
+//
+// The values of Structures are aligned by tag and position: the first value with a
+// particular tag in a Structure in a is compared to the first value with that
+// tag in the corresponding Structure in b, the second to the second, and so on.
+// Unmatched values are reported as Added or Removed.  Changes to the order of values with
+// different tags aren't reported.
+//
+//	for _, d := range ttlv.Diff(vendorResp, pykmipResp, ttlv.IgnoreVolatile()) {
+//	    fmt.Println(d)
+//	}
+func Diff(a, b TTLV, opts ...DiffOption) []Difference {
+	d := differ{ignore: map[Tag]bool{}}
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	d.diff("", a, b)
+
+	return d.diffs
+}
+
+func (d *differ) diff(path string, a, b TTLV) {
+	if a.ValidHeader() != nil || b.ValidHeader() != nil {
+		// compare malformed values as raw bytes
+		if !bytes.Equal(a, b) {
+			d.diffs = append(d.diffs, Difference{Kind: Changed, Path: path, A: a, B: b})
+		}
+
+		return
+	}
+
+	if a.Tag() != b.Tag() || a.Type() != b.Type() {
+		d.diffs = append(d.diffs, Difference{Kind: Changed, Path: path, A: a, B: b})
+		return
+	}
+
+	if a.Type() != TypeStructure {
+		if !bytes.Equal(a.ValueRaw(), b.ValueRaw()) {
+			d.diffs = append(d.diffs, Difference{Kind: Changed, Path: path, A: a, B: b})
+		}
+
+		return
+	}
+
+	as, bs := d.children(a), d.children(b)
+
+	// count the values with each tag, to decide which need an index in their path
+	counts := map[Tag]int{}
+	for _, c := range as {
+		counts[c.Tag()]++
+	}
+
+	bCounts := map[Tag]int{}
+	for _, c := range bs {
+		bCounts[c.Tag()]++
+	}
+
+	for t, n := range bCounts {
+		counts[t] = max(counts[t], n)
+	}
+
+	childPath := func(t Tag, i int) string {
+		p := DefaultRegistry.FormatTag(t)
+		if counts[t] > 1 {
+			p += "[" + strconv.Itoa(i) + "]"
+		}
+
+		if path != "" {
+			p = path + "/" + p
+		}
+
+		return p
+	}
+
+	// walk a, matching each value to the value in b with the same tag and index
+	bByTag := map[Tag][]TTLV{}
+	for _, c := range bs {
+		bByTag[c.Tag()] = append(bByTag[c.Tag()], c)
+	}
+
+	aIndexes := map[Tag]int{}
+
+	for _, c := range as {
+		t := c.Tag()
+		i := aIndexes[t]
+		aIndexes[t]++
+
+		if i < len(bByTag[t]) {
+			d.diff(childPath(t, i), c, bByTag[t][i])
+		} else {
+			d.diffs = append(d.diffs, Difference{Kind: Removed, Path: childPath(t, i), A: c})
+		}
+	}
+
+	// the values left in b are added
+	bIndexes := map[Tag]int{}
+
+	for _, c := range bs {
+		t := c.Tag()
+		i := bIndexes[t]
+		bIndexes[t]++
+
+		if i >= aIndexes[t] {
+			d.diffs = append(d.diffs, Difference{Kind: Added, Path: childPath(t, i), B: c})
+		}
+	}
+}
+
+// children returns the values in Structure t, minus the ignored values.
+func (d *differ) children(t TTLV) []TTLV {
+	var values []TTLV
+
+	eachValue(t, func(v TTLV) {
+		if !d.ignore[v.Tag()] {
+			values = append(values, v)
+		}
+	})
+
+	return values
+}
@@ -0,0 +1,106 @@
+package ttlv_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/Seagate/kmip-go/kmip14"
+	. "github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	resp := func(ts time.Time, items ...Value) Value {
+		return Value{TagResponseMessage, append(Values{
+			{TagResponseHeader, Values{
+				{TagTimeStamp, ts},
+				{TagServerCorrelationValue, ts.String()},
+				{TagBatchCount, len(items)},
+			}},
+		}, items...)}
+	}
+
+	t1 := time.Date(2021, 4, 12, 15, 30, 0, 0, time.UTC)
+	t2 := t1.Add(time.Second)
+
+	a := resp(t1,
+		Value{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+			{TagUniqueBatchItemID, []byte{0x01}},
+			{TagResultStatus, ResultStatusSuccess},
+			{TagResponsePayload, Values{
+				{TagUniqueIdentifier, "key1"},
+			}},
+		}},
+		Value{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+			{TagResultStatus, ResultStatusSuccess},
+			{TagResultMessage, "ok"},
+		}},
+	)
+
+	b := resp(t2,
+		Value{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+			{TagUniqueBatchItemID, []byte{0x02}},
+			{TagResultStatus, ResultStatusOperationFailed},
+			{TagResultReason, ResultReasonItemNotFound},
+		}},
+		Value{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+			{TagResultStatus, ResultStatusSuccess},
+			{TagResultMessage, 5},
+		}},
+		Value{TagBatchItem, Values{
+			{TagOperation, OperationLocate},
+		}},
+	)
+
+	at, err := Marshal(a)
+	require.NoError(t, err)
+	bt, err := Marshal(b)
+	require.NoError(t, err)
+
+	assert.Empty(t, Diff(at, at))
+
+	var strs []string
+	for _, d := range Diff(at, bt, IgnoreVolatile()) {
+		strs = append(strs, d.String())
+	}
+
+	assert.Equal(t, []string{
+		"~ ResponseHeader/BatchCount: 2 (Integer) -> 3 (Integer)",
+		"~ BatchItem[0]/ResultStatus: Success (Enumeration) -> OperationFailed (Enumeration)",
+		"- BatchItem[0]/ResponsePayload: ResponsePayload (Structure)",
+		"+ BatchItem[0]/ResultReason: ItemNotFound (Enumeration)",
+		"~ BatchItem[1]/ResultMessage: ok (TextString) -> 5 (Integer)",
+		"+ BatchItem[2]: BatchItem (Structure)",
+	}, strs)
+
+	// without ignoring the volatile tags, those should be reported too
+	diffs := Diff(at, bt)
+	require.Len(t, diffs, 9)
+
+	assert.Equal(t, Changed, diffs[0].Kind)
+	assert.Equal(t, "ResponseHeader/TimeStamp", diffs[0].Path)
+	assert.Equal(t, "ResponseHeader/ServerCorrelationValue", diffs[1].Path)
+	assert.Equal(t, "BatchItem[0]/UniqueBatchItemID", diffs[3].Path)
+
+	// the values should be slices of the inputs
+	assert.Equal(t, TypeByteString, diffs[3].A.Type())
+	assert.Equal(t, []byte{0x01}, diffs[3].A.ValueByteString())
+	assert.Equal(t, []byte{0x02}, diffs[3].B.ValueByteString())
+
+	diffs = Diff(at, bt, IgnoreVolatile(), IgnoreTags(TagBatchItem, TagBatchCount))
+	assert.Empty(t, diffs)
+
+	// differences in the root value
+	op, err := Marshal(Value{TagOperation, OperationGet})
+	require.NoError(t, err)
+
+	diffs = Diff(at, op)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, Difference{Kind: Changed, Path: "", A: at, B: op}, diffs[0])
+	assert.Equal(t, "~ .: ResponseMessage (Structure) -> Get (Enumeration)", diffs[0].String())
+}
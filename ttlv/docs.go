@@ -12,7 +12,11 @@
 // in a way similar to the json or xml packages.
 //
 // See Marshal() and Unmarshal() for the rules about how golang values map to KMIP TTLVs.
-// Encoder and Decoder can be used to process streams of KMIP values.
+// Encoder and Decoder can be used to process streams of KMIP values.  NewJSONDecoder and
+// NewXMLDecoder create Decoders which read the JSON and XML encodings into golang values,
+// and NewJSONEncoder and NewXMLEncoder create Encoders which write them.  The Decoders set
+// the golang values as the JSON or XML tokens are read, and the Encoders write the tokens
+// as the golang values are encoded, without encoding them to TTLV first.
 //
 // This package holds a registry of type, tag, and enum value names, which are used to transcode
 // strings into these values. KMIP 1.4 names will be automatically loaded into the
@@ -9,7 +9,10 @@ import (
 	"math"
 	"math/big"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ansel1/merry"
@@ -29,8 +32,10 @@ var (
 //
 // An error will be returned if v is an invalid pointer.
 //
-// Currently, Marshal does not support anonymous fields.
-// Private fields are ignored.
+// Private fields are ignored.  The fields of embedded (anonymous) structs are
+// flattened into the enclosing Structure, unless the embedded field has a
+// tag in its "ttlv" struct tag, in which case it is treated like any other
+// field.  See Unmarshal for the rules applied when fields conflict.
 //
 // Marshal maps the golang value to a KMIP tag, type, and value
 // encoding.  To determine the KMIP tag, Marshal uses the same rules
@@ -103,17 +108,90 @@ var (
 //
 // 17. structs marshal to Structure.  Each field of the struct will be marshaled into the
 // values of the Structure according to the above rules.
+// Before a struct is marshaled, its fields are checked against the "required", "min", "max",
+// and "oneof" struct tag flags, as described in Unmarshal.  When marshaling, a field is
+// considered present if its value is not empty, so these flags mean the value must not be
+// zero.  As 0 and false are valid KMIP values, fields of bool and number types, other than
+// enums, must be pointers to use the "required" and "oneof" flags.  Fields in a oneof group
+// which aren't pointers are omitted when empty.  The "min" and "max" flags
+// are checked on every value which is marshaled, including 0, so a field with "min=1" must
+// be a pointer or use "omitempty" to be left out.
+//
+// 18. maps with Tag or string keys marshal to Structure.  Each value is marshaled with the
+// tag of its key, in order of the tags.  String keys are parsed with ParseTag.  Values
+// which are slices marshal to repeated values with the same tag.  nil maps are skipped.
+//
+//	attrs := map[string]interface{}{
+//	  "CryptographicAlgorithm": kmip14.CryptographicAlgorithmAES,
+//	  "CryptographicLength":    256,
+//	  "Name":                   []interface{}{name1, name2},
+//	}
 //
 // Any other golang type will return *MarshalerError with cause ErrUnsupportedTypeError.
 func Marshal(v interface{}) (TTLV, error) {
-	buf := bytes.NewBuffer(nil)
+	return MarshalAppend(nil, v)
+}
 
-	err := NewEncoder(buf).Encode(v)
-	if err != nil {
-		return nil, err
+// maxPooledBufferSize is the largest buffer kept by the pool of encoders, so a few
+// large messages don't pin memory.
+const maxPooledBufferSize = 64 * 1024
+
+// appendEncoder is an Encoder which appends to a byte slice.  They are pooled by
+// MarshalAppend.
+type appendEncoder struct {
+	Encoder
+	dst appendWriter
+}
+
+// appendWriter is an io.Writer which appends to b.
+type appendWriter struct {
+	b []byte
+}
+
+func (w *appendWriter) Write(p []byte) (int, error) {
+	w.b = append(w.b, p...)
+	return len(p), nil
+}
+
+var encoderPool = sync.Pool{
+	New: func() interface{} {
+		return &appendEncoder{}
+	},
+}
+
+// MarshalAppend encodes v like Marshal, and appends the encoding to dst, which
+// may be nil.  It returns the extended buffer.  The encoders are pooled, so
+// encoding into a reused dst only allocates what the golang types of v need to
+// marshal themselves:
+//
+//	buf, err = ttlv.MarshalAppend(buf[:0], msg)
+//
+// If there is an error, dst is returned unchanged.
+func MarshalAppend(dst []byte, v interface{}) (TTLV, error) {
+	e, _ := encoderPool.Get().(*appendEncoder)
+	defer putEncoder(e)
+
+	e.dst.b = dst
+	e.Reset(&e.dst)
+
+	if err := e.Encode(v); err != nil {
+		return dst, err
 	}
 
-	return buf.Bytes(), nil
+	return e.dst.b, nil
+}
+
+// putEncoder resets e and returns it to the pool, unless its buffer has grown too
+// large to keep.
+func putEncoder(e *appendEncoder) {
+	e.dst.b = nil
+
+	if e.encBuf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	e.Reset(nil)
+	encoderPool.Put(e)
 }
 
 // Marshaler knows how to encode itself to TTLV.
@@ -130,6 +208,46 @@ func NewEncoder(w io.Writer) *Encoder {
 	return &Encoder{w: w}
 }
 
+// NewJSONEncoder returns an Encoder which writes KMIP values in the KMIP
+// JSON encoding.  Values are encoded with the same rules as Marshal.  Each
+// value is written to w when it is flushed, followed by a newline.
+//
+// The JSON tokens are written as the golang values are encoded, without encoding
+// them to TTLV first.  Only values which are TTLV already, and sensitive Structures,
+// which Redact must measure, are transcoded from TTLV.  Views are checked as each
+// value is encoded, and Flush returns the first error, without writing anything.
+func NewJSONEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, text: newTextEncoder(formatJSON)}
+}
+
+// NewXMLEncoder returns an Encoder which writes KMIP values in the KMIP
+// XML encoding.  Values are encoded with the same rules as Marshal.  Each
+// value is written to w when it is flushed, followed by a newline.  Like
+// NewJSONEncoder, the XML tokens are written as the values are encoded.
+func NewXMLEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, text: newTextEncoder(formatXML)}
+}
+
+// Reset discards any buffered values, and resets the encoder to write to w,
+// so it can be reused without allocating a new buffer.  The encoder keeps writing
+// the same encoding, and keeps its View, Redact, and Registry settings.
+func (e *Encoder) Reset(w io.Writer) {
+	e.encBuf.Reset()
+	e.w = w
+	e.encodeDepth = 0
+	e.currStruct = ""
+	e.currField = ""
+
+	if e.text != nil {
+		e.text.reset()
+	}
+}
+
+// registry returns the encoder's Registry, or DefaultRegistry.
+func (e *Encoder) registry() *Registry {
+	return registryOrDefault(e.Registry)
+}
+
 // Encode a single value and flush to the writer.  The tag will be inferred from
 // the value.  If no tag can be inferred, an error is returned.
 // See Marshal for encoding rules.
@@ -154,9 +272,17 @@ func (e *Encoder) EncodeValue(tag Tag, v interface{}) error {
 // Call Flush() to write the data to the writer.
 func (e *Encoder) EncodeStructure(tag Tag, f func(e *Encoder) error) error {
 	e.encodeDepth++
-	i := e.encBuf.begin(tag, TypeStructure)
-	err := f(e)
-	e.encBuf.end(i)
+
+	var err error
+
+	if e.writesText() {
+		err = e.encodeTextStructure(tag, f)
+	} else {
+		i := e.encBuf.begin(tag, TypeStructure)
+		err = f(e)
+		e.encBuf.end(i)
+	}
+
 	e.encodeDepth--
 
 	return err
@@ -166,53 +292,152 @@ func (e *Encoder) EncodeStructure(tag Tag, f func(e *Encoder) error) error {
 // single KMIP value with the given tag to an internal buffer.  These methods
 // do not flush the data to the writer: call Flush() to flush the buffer.
 func (e *Encoder) EncodeEnumeration(tag Tag, v uint32) {
+	if e.writesText() {
+		e.writeText(textValue{tag: tag, typ: TypeEnumeration, n: int64(v)})
+		return
+	}
+
 	e.encBuf.encodeEnum(tag, v)
 }
 
 func (e *Encoder) EncodeInteger(tag Tag, v int32) {
+	if e.writesText() {
+		e.writeText(textValue{tag: tag, typ: TypeInteger, n: int64(v)})
+		return
+	}
+
 	e.encBuf.encodeInt(tag, v)
 }
 
 func (e *Encoder) EncodeLongInteger(tag Tag, v int64) {
+	if e.writesText() {
+		e.writeText(textValue{tag: tag, typ: TypeLongInteger, n: v})
+		return
+	}
+
 	e.encBuf.encodeLongInt(tag, v)
 }
 
 func (e *Encoder) EncodeInterval(tag Tag, v time.Duration) {
+	if e.writesText() {
+		e.writeText(textValue{tag: tag, typ: TypeInterval, n: int64(uint32(v / time.Second))})
+		return
+	}
+
 	e.encBuf.encodeInterval(tag, v)
 }
 
 func (e *Encoder) EncodeDateTime(tag Tag, v time.Time) {
+	if e.writesText() {
+		e.writeText(textValue{tag: tag, typ: TypeDateTime, n: v.Unix()})
+		return
+	}
+
 	e.encBuf.encodeDateTime(tag, v)
 }
 
 func (e *Encoder) EncodeDateTimeExtended(tag Tag, v time.Time) {
+	if e.writesText() {
+		e.writeText(textValue{tag: tag, typ: TypeDateTimeExtended, n: dateTimeExtended(v)})
+		return
+	}
+
 	e.encBuf.encodeDateTimeExtended(tag, v)
 }
 
 func (e *Encoder) EncodeBigInteger(tag Tag, v *big.Int) {
+	if e.writesText() {
+		if v != nil {
+			e.writeText(textValue{tag: tag, typ: TypeBigInteger, i: v})
+		}
+
+		return
+	}
+
 	e.encBuf.encodeBigInt(tag, v)
 }
 
 func (e *Encoder) EncodeBoolean(tag Tag, v bool) {
+	if e.writesText() {
+		e.writeText(textValue{tag: tag, typ: TypeBoolean, n: boolInt(v)})
+		return
+	}
+
 	e.encBuf.encodeBool(tag, v)
 }
 
 func (e *Encoder) EncodeTextString(tag Tag, v string) {
+	if e.writesText() {
+		e.writeText(textValue{tag: tag, typ: TypeTextString, s: v})
+		return
+	}
+
 	e.encBuf.encodeTextString(tag, v)
 }
 
 func (e *Encoder) EncodeByteString(tag Tag, v []byte) {
+	if e.writesText() {
+		if v != nil {
+			e.writeText(textValue{tag: tag, typ: TypeByteString, b: v})
+		}
+
+		return
+	}
+
 	e.encBuf.encodeByteString(tag, v)
 }
 
-// Flush flushes the internal encoding buffer to the writer.
+// EncodeByteStream encodes a ByteString with the given tag, whose value is the next n
+// bytes read from r.  r isn't read until Flush, which copies the value to the writer
+// without buffering it.  If r has fewer than n bytes, Flush returns an error with cause
+// io.ErrUnexpectedEOF, after writing part of the value.  If n is negative, or too large
+// for a TTLV length, Flush returns an error with cause ErrInvalidLen, without writing.  Encoders
+// with a View read the value into memory when they are flushed, and JSON and XML encoders when
+// the value is encoded.
+func (e *Encoder) EncodeByteStream(tag Tag, r io.Reader, n int) {
+	if e.text == nil {
+		e.encBuf.beginStream(tag, r, n)
+		return
+	}
+
+	b, err := readStream(tag, r, n)
+	if err != nil {
+		e.text.fail(err)
+		return
+	}
+
+	e.EncodeByteString(tag, b)
+}
+
+// Flush flushes the internal encoding buffer to the writer.  JSON and XML encoders
+// write the text of the buffered values.
 func (e *Encoder) Flush() error {
 	if e.encodeDepth > 0 {
 		return nil
 	}
 
+	if e.text != nil {
+		return e.text.flush(e.w)
+	}
+
+	defer e.encBuf.Reset()
+
+	if e.encBuf.streamErr != nil {
+		return e.encBuf.streamErr
+	}
+
+	if e.View != nil {
+		// validating needs the streamed values in memory
+		if err := e.encBuf.readStreams(); err != nil {
+			return err
+		}
+
+		if err := e.View.Validate(e.encBuf.Bytes()); err != nil {
+			return err
+		}
+	}
+
 	_, err := e.encBuf.WriteTo(e.w)
-	e.encBuf.Reset()
 
 	return err
 }
@@ -295,6 +520,30 @@ func indirect(v reflect.Value) reflect.Value {
 	return v
 }
 
+// zeroIsValid returns true if the zero value of typ marshals to a valid KMIP value, like
+// 0 or false.  When marshaling, fields with these types would always be present, so
+// they can't be validated with the "required" or "oneof" flags.  Enums, and types with
+// their own MarshalTTLV method, like the kmip14 enum types, are assumed to have no
+// valid zero value.
+func zeroIsValid(typ reflect.Type, flags fieldFlags) bool {
+	if flags.enum() || typ.Implements(marshalerType) || reflect.PointerTo(typ).Implements(marshalerType) {
+		return false
+	}
+
+	if typ == bigIntType {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
 var zeroBigInt = big.Int{}
 
 func isEmptyValue(v reflect.Value) bool {
@@ -335,12 +584,18 @@ func (e *Encoder) encode(tag Tag, v reflect.Value, fi *fieldInfo) error {
 	typ := v.Type()
 
 	if typ == ttlvType {
+		if e.writesText() {
+			e.writeTextTTLV(v.Bytes())
+			return nil
+		}
+
 		// fast path: if the value is TTLV, we write it directly to the output buffer
 		_, err := e.encBuf.Write(v.Bytes())
+
 		return err
 	}
 
-	typeInfo, err := getTypeInfo(typ)
+	typeInfo, err := getTypeInfo(typ, e.registry())
 	if err != nil {
 		return err
 	}
@@ -355,29 +610,22 @@ func (e *Encoder) encode(tag Tag, v reflect.Value, fi *fieldInfo) error {
 	}
 
 	// check for Marshaler
-	switch {
-	case typ.Implements(marshalerType):
+	mv := v
+	if !implementsOwn(typ, marshalerType, e.registry()) && v.CanAddr() {
+		mv = v.Addr()
+	}
+
+	if implementsOwn(mv.Type(), marshalerType, e.registry()) {
 		if flags.omitEmpty() && isEmptyValue(v) {
 			return nil
 		}
 
-		return v.Interface().(Marshaler).MarshalTTLV(e, tag) //nolint:forcetypeassert
-	case v.CanAddr():
-		pv := v.Addr()
-
-		pvtyp := pv.Type()
-		if pvtyp.Implements(marshalerType) {
-			if flags.omitEmpty() && isEmptyValue(v) {
-				return nil
-			}
-
-			return pv.Interface().(Marshaler).MarshalTTLV(e, tag) //nolint:forcetypeassert
-		}
+		return mv.Interface().(Marshaler).MarshalTTLV(e, tag) //nolint:forcetypeassert
 	}
 
 	// If the type doesn't implement Marshaler, then validate the value is a supported kind
 	switch v.Kind() {
-	case reflect.Chan, reflect.Map, reflect.Func, reflect.Ptr, reflect.UnsafePointer, reflect.Uintptr, reflect.Float32,
+	case reflect.Chan, reflect.Func, reflect.Ptr, reflect.UnsafePointer, reflect.Uintptr, reflect.Float32,
 		reflect.Float64,
 		reflect.Complex64,
 		reflect.Complex128,
@@ -434,16 +682,16 @@ func (e *Encoder) encode(tag Tag, v reflect.Value, fi *fieldInfo) error {
 	//
 	// If the field is explicitly flag, return an error if the value can't be interpreted.  Otherwise
 	// ignore errors and let processing fallthrough to the type-based encoding.
-	enumMap := DefaultRegistry.EnumForTag(tag)
+	enumMap := e.registry().EnumForTag(tag)
 	if flags.enum() || flags.bitmask() || enumMap != nil {
 		switch typ.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
 			i := v.Int()
 
 			if flags.bitmask() || (enumMap != nil && enumMap.Bitmask()) {
-				e.encBuf.encodeInt(tag, int32(i))
+				e.EncodeInteger(tag, int32(i))
 			} else {
-				e.encBuf.encodeEnum(tag, uint32(i))
+				e.EncodeEnumeration(tag, uint32(i))
 			}
 
 			return nil
@@ -451,9 +699,9 @@ func (e *Encoder) encode(tag Tag, v reflect.Value, fi *fieldInfo) error {
 			i := v.Uint()
 
 			if flags.bitmask() || (enumMap != nil && enumMap.Bitmask()) {
-				e.encBuf.encodeInt(tag, int32(i))
+				e.EncodeInteger(tag, int32(i))
 			} else {
-				e.encBuf.encodeEnum(tag, uint32(i))
+				e.EncodeEnumeration(tag, uint32(i))
 			}
 
 			return nil
@@ -463,7 +711,7 @@ func (e *Encoder) encode(tag Tag, v reflect.Value, fi *fieldInfo) error {
 			if flags.bitmask() || (enumMap != nil && enumMap.Bitmask()) {
 				i, err := ParseInt(s, enumMap)
 				if err == nil {
-					e.encBuf.encodeInt(tag, i)
+					e.EncodeInteger(tag, i)
 					return nil
 				}
 				// only throw an error if the field is explicitly marked as a bitmask
@@ -475,7 +723,7 @@ func (e *Encoder) encode(tag Tag, v reflect.Value, fi *fieldInfo) error {
 			} else {
 				i, err := ParseEnum(s, enumMap)
 				if err == nil {
-					e.encBuf.encodeEnum(tag, i)
+					e.EncodeEnumeration(tag, i)
 					return nil
 				}
 				// only throw an error if the field is explicitly marked as an enum
@@ -496,35 +744,47 @@ func (e *Encoder) encode(tag Tag, v reflect.Value, fi *fieldInfo) error {
 	switch typ {
 	case timeType:
 		if flags.dateTimeExt() {
-			e.encBuf.encodeDateTimeExtended(tag, v.Interface().(time.Time)) //nolint:forcetypeassert
+			e.EncodeDateTimeExtended(tag, v.Interface().(time.Time)) //nolint:forcetypeassert
 		} else {
-			e.encBuf.encodeDateTime(tag, v.Interface().(time.Time)) //nolint:forcetypeassert
+			e.EncodeDateTime(tag, v.Interface().(time.Time)) //nolint:forcetypeassert
 		}
 
 		return nil
 	case bigIntType:
 		bi := v.Interface().(big.Int) //nolint:forcetypeassert
-		e.encBuf.encodeBigInt(tag, &bi)
+		e.EncodeBigInteger(tag, &bi)
 
 		return nil
 	case bigIntPtrType:
-		e.encBuf.encodeBigInt(tag, v.Interface().(*big.Int)) //nolint:forcetypeassert
+		e.EncodeBigInteger(tag, v.Interface().(*big.Int)) //nolint:forcetypeassert
 		return nil
 	case durationType:
-		e.encBuf.encodeInterval(tag, time.Duration(v.Int()))
+		e.EncodeInterval(tag, time.Duration(v.Int()))
 		return nil
 	}
 
 	// handle the rest of the kinds
 	switch typ.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		return e.encodeMap(tag, v)
 	case reflect.Struct:
+		if typeInfo.validate {
+			if err := validateForMarshal(v, typeInfo); err != nil {
+				return err
+			}
+		}
+
 		// push current struct onto stack
 		currStruct := e.currStruct
 		e.currStruct = typ.Name()
 
 		err = e.EncodeStructure(tag, func(e *Encoder) error {
 			for _, field := range typeInfo.valueFields {
-				fv := v.FieldByIndex(field.index)
+				fv := fieldByIndex(v, field.index)
 
 				// note: we're staying in reflection world here instead of
 				// converting back to an interface{} value and going through
@@ -576,19 +836,19 @@ func (e *Encoder) encode(tag Tag, v reflect.Value, fi *fieldInfo) error {
 
 		return err
 	case reflect.String:
-		e.encBuf.encodeTextString(tag, v.String())
+		e.EncodeTextString(tag, v.String())
 	case reflect.Slice:
 		// special case, encode as a ByteString
 		// all slices which aren't []byte should have been handled above
 		// the call to v.Bytes() will panic if this assumption is wrong
-		e.encBuf.encodeByteString(tag, v.Bytes())
+		e.EncodeByteString(tag, v.Bytes())
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
 		i := v.Int()
 		if i > math.MaxInt32 {
 			return e.marshalingError(tag, typ, ErrIntOverflow)
 		}
 
-		e.encBuf.encodeInt(tag, int32(i))
+		e.EncodeInteger(tag, int32(i))
 
 		return nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
@@ -597,19 +857,19 @@ func (e *Encoder) encode(tag Tag, v reflect.Value, fi *fieldInfo) error {
 			return e.marshalingError(tag, typ, ErrIntOverflow)
 		}
 
-		e.encBuf.encodeInt(tag, int32(u))
+		e.EncodeInteger(tag, int32(u))
 
 		return nil
 	case reflect.Uint64:
 		u := v.Uint()
-		e.encBuf.encodeLongInt(tag, int64(u))
+		e.EncodeLongInteger(tag, int64(u))
 
 		return nil
 	case reflect.Int64:
-		e.encBuf.encodeLongInt(tag, v.Int())
+		e.EncodeLongInteger(tag, v.Int())
 		return nil
 	case reflect.Bool:
-		e.encBuf.encodeBool(tag, v.Bool())
+		e.EncodeBoolean(tag, v.Bool())
 	default:
 		// all kinds should have been handled by now
 		panic(errors.New("should never get here"))
@@ -644,6 +904,11 @@ func tagForMarshal(v reflect.Value, ti typeInfo, fi *fieldInfo) Tag {
 // encBuf encodes basic KMIP types into TTLV.
 type encBuf struct {
 	bytes.Buffer
+	// streams are the values of ByteStrings which are copied from readers when
+	// the buffer is written, in order of their offsets.
+	streams []pendingStream
+	// streamErr is the error from a stream with an invalid length, returned by Flush.
+	streamErr error
 }
 
 func (h *encBuf) begin(tag Tag, typ Type) int {
@@ -657,7 +922,7 @@ func (h *encBuf) begin(tag Tag, typ Type) int {
 }
 
 func (h *encBuf) end(i int) {
-	n := h.Len() - i
+	n := h.Len() - i + h.streamedLen(i)
 	if m := n % 8; m > 0 {
 		_, _ = h.Write(zeros[:8-m])
 	}
@@ -755,6 +1020,11 @@ func (h *encBuf) encodeDateTime(tag Tag, t time.Time) {
 }
 
 func (h *encBuf) encodeDateTimeExtended(tag Tag, t time.Time) {
+	h.writeLongIntVal(tag, TypeDateTimeExtended, dateTimeExtended(t))
+}
+
+// dateTimeExtended returns the value of a DateTimeExtended: the microseconds since the epoch.
+func dateTimeExtended(t time.Time) int64 {
 	// take unix seconds, times a million, to get microseconds, then
 	// add nanoseconds remainder/1000
 	//
@@ -763,8 +1033,7 @@ func (h *encBuf) encodeDateTimeExtended(tag Tag, t time.Time) {
 	//
 	// this is limited to max(int64) *microseconds* from epoch, rather than
 	// max(int64) nanoseconds like UnixNano().
-	m := (t.Unix() * 1000000) + int64(t.Nanosecond()/1000)
-	h.writeLongIntVal(tag, TypeDateTimeExtended, m)
+	return (t.Unix() * 1000000) + int64(t.Nanosecond()/1000)
 }
 
 func (h *encBuf) encodeInterval(tag Tag, d time.Duration) {
@@ -791,21 +1060,29 @@ func (h *encBuf) encodeByteString(tag Tag, b []byte) {
 	h.end(i)
 }
 
-func getTypeInfo(typ reflect.Type) (ti typeInfo, err error) {
-	ti.inferredTag, _ = DefaultRegistry.ParseTag(typ.Name())
+// getTypeInfo returns the type info of typ, inferring tags from names with registry r.
+// Type infos are cached by the registry.
+func getTypeInfo(typ reflect.Type, r *Registry) (typeInfo, error) {
+	return r.typeInfo(typ)
+}
+
+// newTypeInfo computes the type info of typ, inferring tags from names with registry r.
+func newTypeInfo(typ reflect.Type, r *Registry) (ti typeInfo, err error) {
+	ti.inferredTag, _ = r.ParseTag(typ.Name())
 	ti.typ = typ
-	err = ti.getFieldsInfo()
+	err = ti.getFieldsInfo(r)
 
 	return ti, err
 }
 
 var errSkip = errors.New("skip")
 
-func getFieldInfo(typ reflect.Type, sf reflect.StructField) (fieldInfo, error) {
+func getFieldInfo(typ reflect.Type, sf reflect.StructField, r *Registry) (fieldInfo, error) {
 	var fi fieldInfo
 
-	// skip anonymous and unexported fields
-	if sf.Anonymous || /*unexported:*/ sf.PkgPath != "" {
+	// skip unexported fields.  Embedded structs are flattened by getFieldsInfo
+	// before getting here.
+	if /*unexported:*/ sf.PkgPath != "" {
 		return fi, errSkip
 	}
 
@@ -827,13 +1104,15 @@ func getFieldInfo(typ reflect.Type, sf reflect.StructField) (fieldInfo, error) {
 			default:
 				var err error
 
-				fi.explicitTag, err = DefaultRegistry.ParseTag(value)
+				fi.explicitTag, err = r.ParseTag(value)
 				if err != nil {
 					return fi, err
 				}
 			}
 		} else {
-			switch strings.ToLower(value) {
+			key, arg, _ := strings.Cut(value, "=")
+
+			switch strings.ToLower(key) {
 			case "enum":
 				fi.flags |= fEnum
 			case "omitempty":
@@ -845,6 +1124,24 @@ func getFieldInfo(typ reflect.Type, sf reflect.StructField) (fieldInfo, error) {
 			case "any":
 				anyField = true
 				fi.flags |= fAny
+			case "required":
+				fi.flags |= fRequired
+			case "oneof":
+				fi.flags |= fOneOf
+				fi.oneOf = arg
+			case "min", "max":
+				n, err := strconv.ParseInt(arg, 10, 64)
+				if err != nil {
+					return fi, merry.Prependf(err, "invalid %s value in tag of %s.%s", key, typ.Name(), sf.Name)
+				}
+
+				if strings.EqualFold(key, "min") {
+					fi.flags |= fMin
+					fi.min = n
+				} else {
+					fi.flags |= fMax
+					fi.max = n
+				}
 			}
 		}
 	}
@@ -853,12 +1150,22 @@ func getFieldInfo(typ reflect.Type, sf reflect.StructField) (fieldInfo, error) {
 		return fi, merry.Here(ErrTagConflict).Appendf(`field %s.%s may not specify a TTLV tag and the "any" flag`, fi.structType.Name(), fi.name)
 	}
 
+	if fi.flags&(fRequired|fOneOf) != 0 && zeroIsValid(sf.Type, fi.flags) {
+		return fi, merry.Here(ErrUnsupportedTypeError).Appendf(`field %s.%s may not use the "required" or "oneof" flags: its zero value is a valid value, so use a pointer instead`, fi.structType.Name(), fi.name)
+	}
+
+	// the fields of a oneof group which aren't set must not be marshaled, or
+	// Unmarshal would find more than one value in the group
+	if fi.flags&fOneOf != 0 && sf.Type.Kind() != reflect.Ptr && sf.Type.Kind() != reflect.Interface {
+		fi.flags |= fOmitEmpty
+	}
+
 	// extract type info for the field.  The KMIP tag
 	// for this field is derived from either the field name,
 	// the field tags, or the field type.
 	var err error
 
-	fi.ti, err = getTypeInfo(sf.Type)
+	fi.ti, err = getTypeInfo(sf.Type, r)
 	if err != nil {
 		return fi, err
 	}
@@ -880,19 +1187,137 @@ func getFieldInfo(typ reflect.Type, sf reflect.StructField) (fieldInfo, error) {
 	}
 
 	if fi.tag == TagNone {
-		fi.tag, _ = DefaultRegistry.ParseTag(fi.name)
+		fi.tag, _ = r.ParseTag(fi.name)
 	}
 
 	return fi, nil
 }
 
-func (ti *typeInfo) getFieldsInfo() error {
+func (ti *typeInfo) getFieldsInfo(r *Registry) error {
 	if ti.typ.Kind() != reflect.Struct {
 		return nil
 	}
 
-	for i := 0; i < ti.typ.NumField(); i++ {
-		fi, err := getFieldInfo(ti.typ, ti.typ.Field(i))
+	err := ti.collectFields(ti.typ, nil, map[reflect.Type]bool{ti.typ: true}, r)
+	if err != nil {
+		return err
+	}
+
+	// Resolve fields which map to the same tag.  These follow the rules encoding/json
+	// uses for field names: of the fields with the same tag, those nested least deeply
+	// in embedded structs hide the others.  If several promoted fields are left, a field
+	// with the tag in its struct tag hides the others.  Fields of the struct itself
+	// must not share a tag.  Any other fields left with the same tag are ambiguous.
+	byTag := map[Tag][]fieldInfo{}
+
+	for _, f := range ti.valueFields {
+		if f.flags.any() || f.tag == TagNone {
+			// ignore any fields
+			continue
+		}
+
+		byTag[f.tag] = append(byTag[f.tag], f)
+	}
+
+	fields := ti.valueFields[:0]
+
+	for _, f := range ti.valueFields {
+		if f.flags.any() || f.tag == TagNone {
+			fields = append(fields, f)
+			continue
+		}
+
+		dominant, err := dominantField(byTag[f.tag])
+		if err != nil {
+			return err
+		}
+
+		if slices.Equal(f.index, dominant.index) {
+			fields = append(fields, f)
+		}
+	}
+
+	ti.valueFields = fields
+
+	for i := range ti.valueFields {
+		if ti.valueFields[i].flags&fValidation != 0 {
+			ti.validate = true
+		}
+	}
+
+	return nil
+}
+
+// dominantField returns the field which hides the other fields with the same tag, or an
+// ErrTagConflict if none does.
+func dominantField(fields []fieldInfo) (fieldInfo, error) {
+	depth := len(fields[0].index)
+	for _, f := range fields[1:] {
+		depth = min(depth, len(f.index))
+	}
+
+	var shallowest, tagged []fieldInfo
+
+	for _, f := range fields {
+		if len(f.index) == depth {
+			shallowest = append(shallowest, f)
+
+			if f.explicitTag != TagNone {
+				tagged = append(tagged, f)
+			}
+		}
+	}
+
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0], nil
+	case depth > 1 && len(tagged) == 1:
+		return tagged[0], nil
+	case depth > 1 && len(tagged) > 1:
+		shallowest = tagged
+	}
+
+	return fieldInfo{}, merry.Here(ErrTagConflict).Appendf("field %s resolves to the same tag (%s) as other field (%s)", shallowest[1].name, shallowest[1].tag, shallowest[0].name)
+}
+
+// collectFields appends the fields of typ to the type info.  The fields of embedded
+// structs without an explicit tag are flattened into the list, as encoding/json does.
+// index is the index sequence of typ within the top level struct, and visited guards
+// against recursively embedded types.
+func (ti *typeInfo) collectFields(typ reflect.Type, index []int, visited map[reflect.Type]bool, r *Registry) error {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		if sf.Anonymous {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			name, _, _ := strings.Cut(sf.Tag.Get(structFieldTag), ",")
+
+			if name == "" && ft.Kind() == reflect.Struct {
+				if visited[ft] {
+					continue
+				}
+
+				visited[ft] = true
+				err := ti.collectFields(ft, fieldIndex, visited, r)
+				delete(visited, ft)
+
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		fi, err := getFieldInfo(typ, sf, r)
 
 		switch {
 		case err == errSkip: //nolint:errorlint
@@ -900,32 +1325,59 @@ func (ti *typeInfo) getFieldsInfo() error {
 		case err != nil:
 			return err
 		case fi.name == "TTLVTag":
-			ti.tagField = &fi
+			// only the top level struct's TTLVTag field is significant
+			if len(index) == 0 {
+				ti.tagField = &fi
+			}
 		default:
+			fi.index = fieldIndex
 			ti.valueFields = append(ti.valueFields, fi)
 		}
 	}
 
-	// verify that multiple fields don't have the same tag
-	names := map[Tag]string{}
+	return nil
+}
 
-	for _, f := range ti.valueFields {
-		if f.flags.any() {
-			// ignore any fields
-			continue
+// fieldFor returns the index of the value field to unmarshal a value with tag into:
+// the first field with the tag, or else the first field with the "any" flag.  Returns
+// -1 if there is none.
+func (ti *typeInfo) fieldFor(tag Tag) int {
+	fldIdx := -1
+
+	for i := range ti.valueFields {
+		if ti.valueFields[i].flags.any() {
+			// if this is the first any field found, keep track
+			// of it as the current candidate match, but
+			// keep looking for a tag match
+			if fldIdx == -1 {
+				fldIdx = i
+			}
+		} else if ti.valueFields[i].tag == tag {
+			// tag match found
+			return i
 		}
+	}
 
-		tag := f.tag
-		if tag != TagNone {
-			if fname, ok := names[tag]; ok {
-				return merry.Here(ErrTagConflict).Appendf("field resolves to the same tag (%s) as other field (%s)", tag, fname)
+	return fldIdx
+}
+
+// fieldByIndex returns the nested field of v corresponding to index.  It
+// returns an invalid value if the path to the field passes through a nil
+// embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return invalidValue
 			}
 
-			names[tag] = f.name
+			v = v.Elem()
 		}
+
+		v = v.Field(x)
 	}
 
-	return nil
+	return v
 }
 
 type typeInfo struct {
@@ -933,6 +1385,8 @@ type typeInfo struct {
 	inferredTag Tag
 	tagField    *fieldInfo
 	valueFields []fieldInfo
+	// validate is true if any of the value fields have validation flags
+	validate bool
 }
 
 const (
@@ -941,6 +1395,12 @@ const (
 	fDateTimeExtended
 	fAny
 	fBitBask
+	fRequired
+	fOneOf
+	fMin
+	fMax
+
+	fValidation = fRequired | fOneOf | fMin | fMax
 )
 
 type fieldFlags int
@@ -972,4 +1432,8 @@ type fieldInfo struct {
 	index            []int
 	flags            fieldFlags
 	ti               typeInfo
+
+	// validation arguments
+	min, max int64
+	oneOf    string
 }
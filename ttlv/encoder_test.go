@@ -3,6 +3,8 @@ package ttlv_test
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
@@ -345,7 +347,8 @@ func TestEncoder_EncodeValue_errors(t *testing.T) {
 
 	tests := []testCase{
 		{
-			v:      map[string]string{},
+			// maps must have Tag or string keys
+			v:      map[int]string{},
 			expErr: ErrUnsupportedTypeError,
 		},
 		{
@@ -691,7 +694,7 @@ func TestEncoder_EncodeValue(t *testing.T) {
 			},
 		},
 		{
-			name: "skipstructanonfield",
+			name: "flattenstructanonfield",
 			v: struct {
 				AttributeName string
 				Attribute
@@ -700,6 +703,47 @@ func TestEncoder_EncodeValue(t *testing.T) {
 				Tag: TagCancellationResult,
 				Value: Values{
 					Value{Tag: TagAttributeName, Value: "red"},
+					Value{Tag: TagAttributeValue, Value: "green"},
+				},
+			},
+		},
+		{
+			name: "flattennilptranonfield",
+			v: struct {
+				AttributeName string
+				*Attribute
+			}{AttributeName: "red"},
+			expected: Value{
+				Tag: TagCancellationResult,
+				Value: Values{
+					Value{Tag: TagAttributeName, Value: "red"},
+				},
+			},
+		},
+		{
+			name: "anonfieldhidden",
+			v: struct {
+				AttributeValue string
+				Attribute
+			}{"red", Attribute{"green"}},
+			expected: Value{
+				Tag: TagCancellationResult,
+				Value: Values{
+					Value{Tag: TagAttributeValue, Value: "red"},
+				},
+			},
+		},
+		{
+			name: "taggedanonfield",
+			v: struct {
+				Attribute `ttlv:"ArchiveDate"`
+			}{Attribute{"green"}},
+			expected: Value{
+				Tag: TagCancellationResult,
+				Value: Values{
+					Value{Tag: TagArchiveDate, Value: Values{
+						Value{Tag: TagAttributeValue, Value: "green"},
+					}},
 				},
 			},
 		},
@@ -1368,6 +1412,88 @@ func TestEncoder_EncodeValue(t *testing.T) {
 	}
 }
 
+func TestMarshal_embeddedTagConflict(t *testing.T) {
+	type A struct {
+		AttributeValue string
+	}
+
+	type B struct {
+		AttributeValue string
+	}
+
+	_, err := Marshal(struct {
+		TTLVTag struct{} `ttlv:"Attribute"`
+		A
+		*B
+	}{A: A{"red"}, B: &B{"blue"}})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTagConflict), Details(err))
+
+	type C struct {
+		V string `ttlv:"AttributeValue"`
+	}
+
+	type D struct {
+		V string `ttlv:"AttributeValue"`
+	}
+
+	_, err = Marshal(struct {
+		TTLVTag struct{} `ttlv:"Attribute"`
+		C
+		D
+	}{C: C{"red"}, D: D{"blue"}})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTagConflict), Details(err))
+}
+
+func TestMarshal_embeddedDominance(t *testing.T) {
+	type A struct {
+		AttributeValue string
+	}
+
+	type B struct {
+		V string `ttlv:"AttributeValue"`
+	}
+
+	tests := []struct {
+		name string
+		v    interface{}
+	}{
+		{
+			// the shallowest field wins
+			name: "depth",
+			v: struct {
+				TTLVTag struct{} `ttlv:"Attribute"`
+				A
+				B
+				AttributeValue string
+			}{A: A{"red"}, B: B{"blue"}, AttributeValue: "green"},
+		},
+		{
+			// of the fields at the same depth, the tagged field wins
+			name: "tagged",
+			v: struct {
+				TTLVTag struct{} `ttlv:"Attribute"`
+				A
+				*B
+			}{A: A{"red"}, B: &B{"green"}},
+		},
+	}
+
+	expected, err := Marshal(Value{TagAttribute, Values{
+		{TagAttributeValue, "green"},
+	}})
+	require.NoError(t, err)
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := Marshal(tc.v)
+			require.NoError(t, err, Details(err))
+			assert.Equal(t, expected, b, Diff(expected, b))
+		})
+	}
+}
+
 func TestEncoder_EncodeStructure(t *testing.T) {
 	type testCase struct {
 		name     string
@@ -1426,6 +1552,160 @@ func TestEncoder_EncodeStructure(t *testing.T) {
 	}
 }
 
+func TestEncoder_JSONAndXML(t *testing.T) {
+	ttlvName, err := Marshal(Value{TagName, Values{
+		{TagNameValue, "red"},
+		{TagNameType, NameTypeUninterpretedTextString},
+	}})
+	require.NoError(t, err)
+
+	type A struct {
+		TTLVTag        struct{} `ttlv:"AlternativeName"`
+		Comment        string   `ttlv:",omitempty"`
+		BatchCount     int      `ttlv:",enum"`
+		ObjectType     ObjectType
+		ActivationDate time.Time `ttlv:",datetimeextended"`
+	}
+
+	values := []interface{}{
+		A{
+			BatchCount:     3,
+			ObjectType:     ObjectTypeSymmetricKey,
+			ActivationDate: parseTime("2008-03-14T11:56:40Z"),
+		},
+		MarshalerStruct{},
+		// attribute values are named by the preceding attribute names, and values which
+		// are TTLV already are transcoded
+		Value{TagTemplateAttribute, Values{
+			{TagAttribute, Values{
+				{TagAttributeName, "Cryptographic Algorithm"},
+				{TagAttributeValue, CryptographicAlgorithmAES},
+			}},
+			{TagAttribute, Values{
+				{TagAttributeName, "Cryptographic Usage Mask"},
+				{TagAttributeValue, CryptographicUsageMaskEncrypt | CryptographicUsageMaskDecrypt},
+			}},
+			{TagAttribute, Values{
+				{TagAttributeName, "Cryptographic Length"},
+				{TagAttributeValue, 256},
+			}},
+			{TagModulus, big.NewInt(-12345)},
+			{TagNone, ttlvName},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		newEnc    func(w io.Writer) *Encoder
+		marshaler func(TTLV) ([]byte, error)
+	}{
+		{name: "json", newEnc: NewJSONEncoder, marshaler: func(t TTLV) ([]byte, error) { return json.Marshal(t) }},
+		{name: "xml", newEnc: NewXMLEncoder, marshaler: func(t TTLV) ([]byte, error) { return xml.Marshal(t) }},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			enc := testcase.newEnc(buf)
+
+			var expected []byte
+
+			for _, v := range values {
+				require.NoError(t, enc.Encode(v))
+
+				b, err := Marshal(v)
+				require.NoError(t, err)
+
+				s, err := testcase.marshaler(b)
+				require.NoError(t, err)
+
+				expected = append(expected, s...)
+				expected = append(expected, '\n')
+			}
+
+			require.Equal(t, string(expected), buf.String())
+
+			// struct tag flags are honored
+			assert.Contains(t, buf.String(), "DateTimeExtended")
+			assert.Contains(t, buf.String(), "Enumeration")
+			assert.NotContains(t, buf.String(), "Comment")
+		})
+	}
+}
+
+func TestMarshalAppend(t *testing.T) {
+	v := Value{TagRequestPayload, Values{
+		{TagComment, "red"},
+		{TagBatchCount, 5},
+	}}
+
+	expected, err := Marshal(v)
+	require.NoError(t, err)
+
+	prefix := []byte{0x01, 0x02}
+
+	b, err := MarshalAppend(prefix, v)
+	require.NoError(t, err)
+	assert.Equal(t, prefix, []byte(b[:2]))
+	assert.Equal(t, expected, b[2:])
+
+	// reusing the buffer
+	b, err = MarshalAppend(b[:0], Value{TagComment, "blue"})
+	require.NoError(t, err)
+
+	expected, err = Marshal(Value{TagComment, "blue"})
+	require.NoError(t, err)
+	assert.Equal(t, expected, b)
+
+	// on error, dst is returned unchanged, and the pooled encoder doesn't keep the
+	// partially encoded value
+	b, err = MarshalAppend(prefix, Value{TagRequestPayload, Values{
+		{TagComment, "red"},
+		{TagBatchCount, complex64(1)},
+	}})
+	require.Error(t, err)
+	assert.Equal(t, prefix, []byte(b))
+
+	b, err = MarshalAppend(nil, Value{TagComment, "blue"})
+	require.NoError(t, err)
+	assert.Equal(t, expected, b)
+}
+
+func TestEncoder_Reset(t *testing.T) {
+	var buf1, buf2 bytes.Buffer
+
+	enc := NewJSONEncoder(&buf1)
+
+	// buffered values are discarded
+	enc.EncodeInteger(TagBatchCount, 5)
+	enc.Reset(&buf2)
+
+	require.NoError(t, enc.Encode(Value{TagComment, "red"}))
+	assert.Empty(t, buf1.String())
+
+	expected, err := Marshal(Value{TagComment, "red"})
+	require.NoError(t, err)
+
+	expectedJSON, err := json.Marshal(expected)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expectedJSON), buf2.String())
+
+	// after a failed encoding, the partially encoded value is discarded
+	buf2.Reset()
+
+	enc = NewEncoder(&buf1)
+	err = enc.EncodeStructure(TagRequestPayload, func(e *Encoder) error {
+		e.EncodeInteger(TagBatchCount, 5)
+		return errors.New("boom")
+	})
+	require.Error(t, err)
+
+	enc.Reset(&buf2)
+	require.NoError(t, enc.Encode(Value{TagComment, "red"}))
+	assert.Empty(t, buf1.Bytes())
+	assert.Equal(t, expected, TTLV(buf2.Bytes()))
+}
+
 func TestTaggedValue_UnmarshalTTLV(t *testing.T) {
 	var tv Value
 
@@ -1641,3 +1921,148 @@ func BenchmarkEncoder_EncodeInt(b *testing.B) {
 		require.NoError(b, enc.Flush())
 	}
 }
+
+func TestMarshal_validation(t *testing.T) {
+	type A struct {
+		TTLVTag        struct{} `ttlv:"AlternativeName"`
+		Comment        string   `ttlv:",required"`
+		BatchCount     int      `ttlv:",max=10"`
+		PrimeFieldSize *big.Int `ttlv:",min=2,omitempty"`
+		ArchiveDate    string   `ttlv:",oneof=date"`
+		ActivationDate string   `ttlv:",oneof=date"`
+	}
+
+	tests := []struct {
+		name string
+		v    A
+		err  error
+	}{
+		{
+			name: "valid",
+			v:    A{Comment: "red", BatchCount: 10, PrimeFieldSize: big.NewInt(2), ArchiveDate: "blue"},
+		},
+		{
+			name: "missingrequired",
+			v:    A{ArchiveDate: "blue"},
+			err:  ErrMissingValue,
+		},
+		{
+			name: "outofrange",
+			v:    A{Comment: "red", BatchCount: 11, ArchiveDate: "blue"},
+			err:  ErrValueOutOfRange,
+		},
+		{
+			name: "bigintoutofrange",
+			v:    A{Comment: "red", PrimeFieldSize: big.NewInt(1), ArchiveDate: "blue"},
+			err:  ErrValueOutOfRange,
+		},
+		{
+			name: "missingoneof",
+			v:    A{Comment: "red"},
+			err:  ErrMissingValue,
+		},
+		{
+			name: "multipleoneof",
+			v:    A{Comment: "red", ArchiveDate: "blue", ActivationDate: "green"},
+			err:  ErrMultipleValues,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Marshal(tc.v)
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			require.True(t, errors.Is(err, tc.err), Details(err))
+
+			var verr *ValidationError
+			require.True(t, errors.As(err, &verr), Details(err))
+		})
+	}
+}
+
+func TestMarshal_validationSymmetry(t *testing.T) {
+	// Marshal must never write a value which Unmarshal would reject
+	type A struct {
+		TTLVTag        struct{} `ttlv:"AlternativeName"`
+		Comment        string   `ttlv:",required"`
+		BatchCount     int      `ttlv:",min=1,max=10"`
+		BatchOrder     *int     `ttlv:",min=1"`
+		MaximumItems   int      `ttlv:",min=1,omitempty"`
+		NameValue      []string `ttlv:",min=1,max=2"`
+		Nonce          []byte   `ttlv:",min=1"`
+		ArchiveDate    string   `ttlv:",oneof"`
+		ActivationDate string   `ttlv:",oneof"`
+	}
+
+	zero := 0
+	valid := A{Comment: "red", BatchCount: 1, Nonce: []byte{1}, ArchiveDate: "blue"}
+
+	tests := []struct {
+		name string
+		v    func(a *A)
+	}{
+		{name: "valid", v: func(a *A) {}},
+		{name: "zeronumber", v: func(a *A) { a.BatchCount = 0 }},
+		{name: "zeropointer", v: func(a *A) { a.BatchOrder = &zero }},
+		{name: "omitempty", v: func(a *A) { a.MaximumItems = 0 }},
+		{name: "emptyslice", v: func(a *A) { a.NameValue = nil }},
+		{name: "longslice", v: func(a *A) { a.NameValue = []string{"a", "b", "c"} }},
+		{name: "emptybytes", v: func(a *A) { a.Nonce = nil }},
+		{name: "emptyrequired", v: func(a *A) { a.Comment = "" }},
+		{name: "missingoneof", v: func(a *A) { a.ArchiveDate = "" }},
+		{name: "multipleoneof", v: func(a *A) { a.ActivationDate = "green" }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v := valid
+			tc.v(&v)
+
+			b, err := Marshal(v)
+			if err != nil {
+				var verr *ValidationError
+				require.True(t, errors.As(err, &verr), Details(err))
+
+				return
+			}
+
+			require.NoError(t, Unmarshal(b, &A{}), "unmarshaling %s", b)
+		})
+	}
+}
+
+func TestMarshal_requiredZeroValue(t *testing.T) {
+	// 0 is a valid Integer, so a required int field couldn't tell it from a missing value
+	type A struct {
+		TTLVTag    struct{} `ttlv:"AlternativeName"`
+		BatchCount int      `ttlv:",required"`
+	}
+
+	_, err := Marshal(A{BatchCount: 1})
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrUnsupportedTypeError), Details(err))
+
+	type B struct {
+		TTLVTag    struct{} `ttlv:"AlternativeName"`
+		BatchCount *int     `ttlv:",required"`
+	}
+
+	zero := 0
+
+	b, err := Marshal(B{BatchCount: &zero})
+	require.NoError(t, err)
+
+	var v B
+
+	require.NoError(t, Unmarshal(b, &v))
+	require.NotNil(t, v.BatchCount)
+	assert.Equal(t, 0, *v.BatchCount)
+
+	_, err = Marshal(B{})
+	require.True(t, errors.Is(err, ErrMissingValue), Details(err))
+}
@@ -1,8 +1,159 @@
 package ttlv
 
-import "github.com/ansel1/merry"
+import (
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/ansel1/merry"
+)
 
 // Details prints details from the error, including a stacktrace when available.
 func Details(err error) string {
 	return merry.Details(err)
 }
+
+type errKey int
+
+const (
+	errorKeyPath errKey = iota
+	errorKeyOffset
+)
+
+// ErrorPath returns the path of the value which caused an error returned by a Decoder,
+// like "ResponseMessage/BatchItem[1]/ResponsePayload".  The first step is the tag of
+// the message, and the rest of the path selects the value from the message, in the syntax
+// of ParsePath.  Indexes are only included for tags which are repeated in the enclosing
+// Structure.  Returns "" if the position of the error is unknown.
+func ErrorPath(err error) string {
+	var ue *UnmarshalerError
+	if errors.As(err, &ue) && ue.Path != "" {
+		return ue.Path
+	}
+
+	s, _ := merry.Value(err, errorKeyPath).(string)
+
+	return s
+}
+
+// ErrorOffset returns the offset in bytes, from the start of the message, of the value
+// which caused an error returned by a Decoder.  When a message is truncated, it's the
+// offset at which the message ends.  Returns -1 if the position of the error is unknown.
+func ErrorOffset(err error) int {
+	var ue *UnmarshalerError
+	if errors.As(err, &ue) && ue.Offset >= 0 {
+		return ue.Offset
+	}
+
+	if off, ok := merry.Value(err, errorKeyOffset).(int); ok {
+		return off
+	}
+
+	return -1
+}
+
+// withPosition annotates err with the position of the value at offset off in message t.
+func (dec *Decoder) withPosition(err error, t TTLV, off int) error {
+	path := pathAt(t, off, dec.registry())
+
+	return merry.WithValue(err, errorKeyPath, path).
+		WithValue(errorKeyOffset, off).
+		Appendf("at offset %d in %s", off, path)
+}
+
+// offsetOf returns the offset of value t in root, or -1 if t isn't part of root.
+func offsetOf(root, t TTLV) int {
+	if len(root) == 0 || len(t) == 0 {
+		return -1
+	}
+
+	// values are slices of the message, so their capacity shrinks with their offset
+	off := cap(root) - cap(t)
+	if off < 0 || off >= len(root) || &root[off] != &t[0] {
+		return -1
+	}
+
+	return off
+}
+
+// pathAt returns the path from root to the innermost value containing offset off of
+// root.  root may be truncated.  Returns "" if root's header is invalid.
+func pathAt(root TTLV, off int, r *Registry) string {
+	if root.ValidHeader() != nil {
+		return ""
+	}
+
+	path := r.FormatTag(root.Tag())
+
+	// start is the offset of t, the innermost value found so far
+	t, start := root, 0
+
+	for start < off && t.Type() == TypeStructure {
+		values := structureValues(t)
+
+		i := slices.IndexFunc(values, func(v TTLV) bool {
+			o := start + cap(t) - cap(v)
+			return off >= o && off < o+v.FullLen()
+		})
+		if i < 0 {
+			break
+		}
+
+		c := values[i]
+
+		var index, count int
+
+		for j, v := range values {
+			if v.Tag() == c.Tag() {
+				if j < i {
+					index++
+				}
+
+				count++
+			}
+		}
+
+		path += "/" + r.FormatTag(c.Tag())
+		if count > 1 {
+			path += "[" + strconv.Itoa(index) + "]"
+		}
+
+		start += cap(t) - cap(c)
+		t = c
+	}
+
+	return path
+}
+
+// structureValues returns the values of Structure t, up to the first invalid header.
+// The last value may be truncated.
+func structureValues(t TTLV) []TTLV {
+	var values []TTLV
+
+	for c := t.ValueStructure(); len(c) > 0 && c.ValidHeader() == nil; c = c[c.FullLen():] {
+		values = append(values, c)
+
+		if c.FullLen() >= len(c) {
+			break
+		}
+	}
+
+	return values
+}
+
+// prependFieldPath adds s, a field name or an index like "[2]", to the start of the
+// FieldPath of the *UnmarshalerError in err, if any.
+func prependFieldPath(err error, s string) error {
+	var ue *UnmarshalerError
+	if !errors.As(err, &ue) {
+		return err
+	}
+
+	if ue.FieldPath != "" && ue.FieldPath[0] != '[' {
+		s += "."
+	}
+
+	ue.FieldPath = s + ue.FieldPath
+
+	return err
+}
@@ -0,0 +1,93 @@
+package ttlv
+
+import (
+	"errors"
+	"io"
+
+	"github.com/ansel1/merry"
+)
+
+var (
+	ErrMessageTooLarge  = errors.New("message exceeds maximum size")
+	ErrMaxDepthExceeded = errors.New("structures exceed maximum depth")
+	ErrTooManyItems     = errors.New("structure exceeds maximum number of items")
+)
+
+// maxTextSizeFactor is how many times MaxMessageSize the JSON or XML text of a value
+// may be.  The text encodings of typical messages are up to ten times the size of their
+// TTLV encoding, when indented.
+const maxTextSizeFactor = 16
+
+// textReader counts the bytes of JSON or XML text read for the current value, and fails
+// once more than maxTextSizeFactor times the decoder's MaxMessageSize have been read,
+// so oversized text is rejected while it is read, rather than after it is buffered.
+type textReader struct {
+	dec *Decoder
+	r   io.Reader
+	// n is the number of bytes read since the decoder started reading the current value
+	n int
+}
+
+func (tr *textReader) Read(p []byte) (int, error) {
+	if tr.dec.MaxMessageSize > 0 {
+		limit := maxTextSizeFactor * tr.dec.MaxMessageSize
+		if tr.n >= limit {
+			return 0, merry.Here(ErrMessageTooLarge).Appendf("message text exceeds limit of %d bytes", limit)
+		}
+
+		if len(p) > limit-tr.n {
+			p = p[:limit-tr.n]
+		}
+	}
+
+	n, err := tr.r.Read(p)
+	tr.n += n
+
+	return n, err
+}
+
+// checkLimits checks a value read from the stream against the decoder's limits.
+func (dec *Decoder) checkLimits(t TTLV) error {
+	if dec.MaxMessageSize > 0 && len(t) > dec.MaxMessageSize {
+		return merry.Here(ErrMessageTooLarge).Appendf("message length %d exceeds limit of %d", len(t), dec.MaxMessageSize)
+	}
+
+	if dec.MaxDepth <= 0 && dec.MaxItemsPerStructure <= 0 {
+		return nil
+	}
+
+	return dec.checkStructureLimits(t, 0)
+}
+
+// checkStructureLimits checks the values in t, which are enclosed in depth Structures.
+// It doesn't use Next(), which validates each value recursively, so deeply nested
+// values are rejected before anything recurses into them.  Malformed values are left
+// for the decoding to report.
+func (dec *Decoder) checkStructureLimits(t TTLV, depth int) error {
+	var items int
+
+	for len(t) > 0 {
+		if t.ValidHeader() != nil || len(t) < t.FullLen() {
+			return nil
+		}
+
+		items++
+		if dec.MaxItemsPerStructure > 0 && items > dec.MaxItemsPerStructure {
+			return merry.Here(ErrTooManyItems).Appendf("structure contains more than %d items", dec.MaxItemsPerStructure)
+		}
+
+		if t.Type() == TypeStructure {
+			if dec.MaxDepth > 0 && depth+1 > dec.MaxDepth {
+				return merry.Here(ErrMaxDepthExceeded).Appendf("%s is nested more than %d structures deep", t.Tag(), dec.MaxDepth)
+			}
+
+			if err := dec.checkStructureLimits(t.ValueStructure(), depth+1); err != nil {
+				return err
+			}
+		}
+
+		t = t[t.FullLen():]
+	}
+
+	return nil
+}
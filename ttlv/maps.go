@@ -0,0 +1,172 @@
+package ttlv
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+
+	"github.com/ansel1/merry"
+)
+
+// Maps marshal to and from Structures.  Each key is the tag of the values
+// stored under it.  Keys may be Tags, or strings, which are parsed with the registry,
+// so they can be tag names like "CryptographicAlgorithm", or hex values like "0x540001".
+
+// isMapKeyType returns true if maps with keys of type t can be marshaled.
+func isMapKeyType(t reflect.Type) bool {
+	return t == tagType || t.Kind() == reflect.String
+}
+
+type mapEntry struct {
+	tag   Tag
+	key   string
+	value reflect.Value
+}
+
+// encodeMap encodes map v as a Structure.  The values are encoded in order of their
+// tags, and values which are slices are encoded as repeated values with the same tag.
+func (e *Encoder) encodeMap(tag Tag, v reflect.Value) error {
+	typ := v.Type()
+	if !isMapKeyType(typ.Key()) {
+		return e.marshalingError(tag, typ, ErrUnsupportedTypeError).Appendf("map keys must be %v or string", tagType)
+	}
+
+	entries := make([]mapEntry, 0, v.Len())
+
+	iter := v.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+
+		if k.Type() == tagType {
+			entries = append(entries, mapEntry{tag: Tag(k.Uint()), value: iter.Value()})
+			continue
+		}
+
+		t, err := e.registry().ParseTag(k.String())
+		if err != nil {
+			return e.marshalingError(tag, typ, err).Appendf("invalid map key %q", k.String())
+		}
+
+		entries = append(entries, mapEntry{tag: t, key: k.String(), value: iter.Value()})
+	}
+
+	// different string keys can parse to the same tag, so break ties with the keys
+	slices.SortFunc(entries, func(a, b mapEntry) int {
+		return cmp.Or(cmp.Compare(a.tag, b.tag), cmp.Compare(a.key, b.key))
+	})
+
+	return e.EncodeStructure(tag, func(e *Encoder) error {
+		for _, entry := range entries {
+			if err := e.encode(entry.tag, entry.value, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// unmarshalMap decodes Structure ttlv into map val, keyed by the tags of its values.
+// Repeated tags are collected into slices.
+func (dec *Decoder) unmarshalMap(ttlv TTLV, val reflect.Value) error {
+	typ := val.Type()
+	if !isMapKeyType(typ.Key()) {
+		return merry.Prependf(dec.newUnmarshalerError(ttlv, typ, ErrUnsupportedTypeError), "map keys must be %v or string", tagType)
+	}
+
+	if err := dec.EnterStructure(ttlv, typ); err != nil {
+		return err
+	}
+	defer dec.LeaveStructure()
+
+	if val.IsNil() {
+		val.Set(reflect.MakeMap(typ))
+	}
+
+	elemTyp := typ.Elem()
+
+	// the keys of interface{} values which have been collected into slices
+	var collected map[Tag]bool
+
+	for n := ttlv.ValueStructure(); n != nil; n = n.Next() {
+		key := reflect.ValueOf(n.Tag())
+		if typ.Key() != tagType {
+			key = reflect.ValueOf(dec.registry().FormatTag(n.Tag())).Convert(typ.Key())
+		}
+
+		existing := val.MapIndex(key)
+		ev := reflect.New(elemTyp).Elem()
+
+		switch {
+		case elemTyp.Kind() == reflect.Slice && elemTyp.Elem() != byteType:
+			// append to the slice
+			if existing.IsValid() {
+				ev.Set(existing)
+			}
+
+			if err := dec.unmarshal(ev, n); err != nil {
+				return dec.mapValueError(err, n)
+			}
+		case !existing.IsValid():
+			if err := dec.unmarshalMapValue(ev, n, typ); err != nil {
+				return dec.mapValueError(err, n)
+			}
+		case elemTyp.Kind() == reflect.Interface && elemTyp.NumMethod() == 0:
+			// a repeated tag: collect the values into a []interface{}
+			values := existing.Elem()
+
+			if !collected[n.Tag()] {
+				if collected == nil {
+					collected = map[Tag]bool{}
+				}
+
+				collected[n.Tag()] = true
+				values = reflect.ValueOf([]interface{}{existing.Interface()})
+			}
+
+			item := reflect.New(elemTyp).Elem()
+			if err := dec.unmarshalMapValue(item, n, typ); err != nil {
+				return dec.mapValueError(err, n)
+			}
+
+			ev.Set(reflect.Append(values, item))
+		default:
+			// nowhere to put a repeated value
+			return merry.Prependf(dec.newUnmarshalerError(n, elemTyp, ErrUnexpectedValue), "repeated tag %v in map", n.Tag())
+		}
+
+		val.SetMapIndex(key, ev)
+	}
+
+	return nil
+}
+
+// mapValueError adds the key of map value ttlv to the FieldPath of err.
+func (dec *Decoder) mapValueError(err error, ttlv TTLV) error {
+	return prependFieldPath(err, "["+dec.registry().FormatTag(ttlv.Tag())+"]")
+}
+
+// unmarshalMapValue decodes ttlv into ev, an element of map type typ.  Structures
+// decoded into interface{} elements are decoded into maps of the same type, unless
+// the TypeResolver chooses another type.
+func (dec *Decoder) unmarshalMapValue(ev reflect.Value, ttlv TTLV, typ reflect.Type) error {
+	if ev.Kind() != reflect.Interface || ttlv.Type() != TypeStructure || !typ.AssignableTo(ev.Type()) {
+		return dec.unmarshal(ev, ttlv)
+	}
+
+	if dec.TypeResolver != nil {
+		resolved, err := dec.resolve(ev, ttlv)
+		if resolved || err != nil {
+			return err
+		}
+	}
+
+	m := reflect.New(typ).Elem()
+	if err := dec.unmarshalMap(ttlv, m); err != nil {
+		return err
+	}
+
+	ev.Set(m)
+
+	return nil
+}
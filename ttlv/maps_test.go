@@ -0,0 +1,147 @@
+package ttlv_test
+
+import (
+	"testing"
+
+	. "github.com/Seagate/kmip-go/kmip14"
+	. "github.com/Seagate/kmip-go/ttlv"
+	"github.com/ansel1/merry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal_map(t *testing.T) {
+	// map values are ordered by tag, so NameType comes before NameValue
+	expected, err := Marshal(Value{TagTemplateAttribute, Values{
+		{TagCryptographicAlgorithm, CryptographicAlgorithmAES},
+		{TagCryptographicLength, 256},
+		{TagName, Values{
+			{TagNameType, NameTypeUninterpretedTextString},
+			{TagNameValue, "red"},
+		}},
+		{TagName, Values{
+			{TagNameType, NameTypeUninterpretedTextString},
+			{TagNameValue, "blue"},
+		}},
+		{Tag(0x540001), "vendor"},
+	}})
+	require.NoError(t, err)
+
+	names := []interface{}{
+		map[Tag]interface{}{TagNameValue: "red", TagNameType: NameTypeUninterpretedTextString},
+		map[Tag]interface{}{TagNameValue: "blue", TagNameType: NameTypeUninterpretedTextString},
+	}
+
+	// values are ordered by tag, regardless of the order of the keys
+	b, err := Marshal(Value{TagTemplateAttribute, map[Tag]interface{}{
+		Tag(0x540001):             "vendor",
+		TagName:                   names,
+		TagCryptographicLength:    256,
+		TagCryptographicAlgorithm: CryptographicAlgorithmAES,
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, expected, b)
+
+	// string keys are parsed as tags
+	b, err = Marshal(Value{TagTemplateAttribute, map[string]interface{}{
+		"0x540001":               "vendor",
+		"Name":                   names,
+		"CryptographicLength":    256,
+		"CryptographicAlgorithm": "AES",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, expected, b)
+
+	// maps can be struct fields
+	type template struct {
+		TemplateAttribute map[string]interface{}
+		Nothing           map[string]interface{} `ttlv:"Comment"`
+	}
+
+	b, err = Marshal(Value{TagRequestPayload, template{TemplateAttribute: map[string]interface{}{
+		"CryptographicLength": 256,
+	}}})
+	require.NoError(t, err)
+
+	expected, err = Marshal(Value{TagRequestPayload, Values{
+		{TagTemplateAttribute, Values{
+			{TagCryptographicLength, 256},
+		}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, expected, b)
+}
+
+func TestMarshal_mapErrors(t *testing.T) {
+	_, err := Marshal(Value{TagTemplateAttribute, map[string]interface{}{"NotATag": 1}})
+	require.Error(t, err)
+
+	_, err = Marshal(Value{TagTemplateAttribute, map[int]interface{}{1: 1}})
+	require.Error(t, err)
+	require.True(t, merry.Is(err, ErrUnsupportedTypeError), "%+v", err)
+}
+
+func TestUnmarshal_map(t *testing.T) {
+	b, err := Marshal(Value{TagTemplateAttribute, Values{
+		{TagCryptographicAlgorithm, CryptographicAlgorithmAES},
+		{TagCryptographicLength, 256},
+		{TagName, Values{
+			{TagNameType, NameTypeUninterpretedTextString},
+			{TagNameValue, "red"},
+		}},
+		{TagName, Values{
+			{TagNameType, NameTypeUninterpretedTextString},
+			{TagNameValue, "blue"},
+		}},
+		{Tag(0x540001), "vendor"},
+	}})
+	require.NoError(t, err)
+
+	var m map[string]interface{}
+	require.NoError(t, Unmarshal(b, &m))
+	assert.Equal(t, map[string]interface{}{
+		"CryptographicAlgorithm": EnumValue(CryptographicAlgorithmAES),
+		"CryptographicLength":    int32(256),
+		"Name": []interface{}{
+			map[string]interface{}{"NameValue": "red", "NameType": EnumValue(NameTypeUninterpretedTextString)},
+			map[string]interface{}{"NameValue": "blue", "NameType": EnumValue(NameTypeUninterpretedTextString)},
+		},
+		"0x540001": "vendor",
+	}, m)
+
+	// and back again
+	b2, err := Marshal(Value{TagTemplateAttribute, m})
+	require.NoError(t, err)
+	assert.Equal(t, b, b2)
+
+	var tm map[Tag]interface{}
+	require.NoError(t, Unmarshal(b, &tm))
+	assert.Equal(t, "vendor", tm[Tag(0x540001)])
+	assert.Len(t, tm[TagName], 2)
+
+	// slice values collect all the values with the tag
+	var sm map[Tag][]interface{}
+	require.NoError(t, Unmarshal(b, &sm))
+	assert.Equal(t, []interface{}{int32(256)}, sm[TagCryptographicLength])
+	assert.Len(t, sm[TagName], 2)
+
+	// typed values
+	var strs map[string]string
+	b, err = Marshal(Value{TagName, Values{
+		{TagNameValue, "red"},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, Unmarshal(b, &strs))
+	assert.Equal(t, map[string]string{"NameValue": "red"}, strs)
+
+	// but repeated tags need somewhere to go
+	b, err = Marshal(Value{TagName, Values{
+		{TagNameValue, "red"},
+		{TagNameValue, "blue"},
+	}})
+	require.NoError(t, err)
+
+	err = Unmarshal(b, &strs)
+	require.Error(t, err)
+	require.True(t, merry.Is(err, ErrUnexpectedValue), "%+v", err)
+}
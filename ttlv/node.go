@@ -0,0 +1,329 @@
+package ttlv
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/ansel1/merry"
+)
+
+// SkipChildren can be returned by the function passed to Node.Walk to skip the
+// children of the current node.
+var SkipChildren = errors.New("skip children") //nolint:revive,stylecheck
+
+// Node is a mutable tree representation of a TTLV value.  Unlike Value, a Node keeps
+// the exact KMIP type of each value, so a TTLV value can be parsed into a tree of
+// Nodes, edited, and re-encoded without changing anything which wasn't edited.  Tags
+// which aren't registered, like vendor extensions, are kept as-is.
+//
+//	n, err := ttlv.ParseNode(resp)
+//	status, err := n.Find("BatchItem/ResultStatus")
+//	err = status.Set(kmip14.ResultStatusOperationFailed)
+//	resp, err = ttlv.Marshal(n)
+//
+// Node implements Marshaler and Unmarshaler, so it can also be used as the type of
+// struct fields.
+type Node struct {
+	Tag  Tag
+	Type Type
+	// Value is the value of nodes which aren't Structures.  It has the same go type
+	// TTLV.Value() returns for the node's Type: int32, int64, *big.Int, EnumValue,
+	// bool, string, []byte, time.Time, DateTimeExtended, or time.Duration.
+	Value interface{}
+	// Children are the values enclosed by Structure nodes.
+	Children []*Node
+}
+
+// NewNode returns a Node for v, encoded with tag according to the rules of Marshal.
+func NewNode(tag Tag, v interface{}) (*Node, error) {
+	n := &Node{Tag: tag}
+	if err := n.Set(v); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// ParseNode parses a TTLV value into a tree of Nodes.  The Nodes don't share any
+// memory with t.
+func ParseNode(t TTLV) (*Node, error) {
+	if err := t.Valid(); err != nil {
+		return nil, err
+	}
+
+	return parseNode(t), nil
+}
+
+// parseNode parses a valid TTLV value.
+func parseNode(t TTLV) *Node {
+	n := &Node{
+		Tag:  t.Tag(),
+		Type: t.Type(),
+	}
+
+	if n.Type != TypeStructure {
+		n.Value = t.Value()
+		if b, ok := n.Value.([]byte); ok {
+			// copy byte strings, so the node doesn't share memory with t
+			n.Value = slices.Clone(b)
+		}
+
+		return n
+	}
+
+	for c := t.ValueStructure(); len(c) > 0; c = c[c.FullLen():] {
+		n.Children = append(n.Children, parseNode(c))
+	}
+
+	return n
+}
+
+// UnmarshalTTLV implements Unmarshaler.
+func (n *Node) UnmarshalTTLV(_ *Decoder, ttlv TTLV) error {
+	if len(ttlv) == 0 {
+		return nil
+	}
+
+	p, err := ParseNode(ttlv)
+	if err != nil {
+		return err
+	}
+
+	*n = *p
+
+	return nil
+}
+
+// MarshalTTLV implements Marshaler.  If the node's Tag is set, it overrides the
+// suggested tag.
+func (n *Node) MarshalTTLV(e *Encoder, tag Tag) error {
+	if n.Tag != TagNone {
+		tag = n.Tag
+	}
+
+	if n.Type == TypeStructure {
+		return e.EncodeStructure(tag, func(e *Encoder) error {
+			for _, c := range n.Children {
+				if err := c.MarshalTTLV(e, TagNone); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	ok := true
+
+	switch n.Type {
+	case TypeInteger:
+		var v int32
+		if v, ok = n.Value.(int32); ok {
+			e.EncodeInteger(tag, v)
+		}
+	case TypeLongInteger:
+		var v int64
+		if v, ok = n.Value.(int64); ok {
+			e.EncodeLongInteger(tag, v)
+		}
+	case TypeBigInteger:
+		var v *big.Int
+		if v, ok = n.Value.(*big.Int); ok {
+			e.EncodeBigInteger(tag, v)
+		}
+	case TypeEnumeration:
+		var v EnumValue
+		if v, ok = n.Value.(EnumValue); ok {
+			e.EncodeEnumeration(tag, uint32(v))
+		}
+	case TypeBoolean:
+		var v bool
+		if v, ok = n.Value.(bool); ok {
+			e.EncodeBoolean(tag, v)
+		}
+	case TypeTextString:
+		var v string
+		if v, ok = n.Value.(string); ok {
+			e.EncodeTextString(tag, v)
+		}
+	case TypeByteString:
+		var v []byte
+		if v, ok = n.Value.([]byte); ok {
+			e.EncodeByteString(tag, v)
+		}
+	case TypeDateTime:
+		var v time.Time
+		if v, ok = n.Value.(time.Time); ok {
+			e.EncodeDateTime(tag, v)
+		}
+	case TypeDateTimeExtended:
+		var v DateTimeExtended
+		if v, ok = n.Value.(DateTimeExtended); ok {
+			e.EncodeDateTimeExtended(tag, v.Time)
+		}
+	case TypeInterval:
+		var v time.Duration
+		if v, ok = n.Value.(time.Duration); ok {
+			e.EncodeInterval(tag, v)
+		}
+	default:
+		return e.marshalingError(tag, reflect.TypeOf(n.Value), ErrInvalidType)
+	}
+
+	if !ok {
+		return merry.Prependf(e.marshalingError(tag, reflect.TypeOf(n.Value), ErrUnsupportedTypeError), "value doesn't match node type %v", n.Type)
+	}
+
+	return nil
+}
+
+// Set replaces the node's type and value, or children, with v, encoded according to
+// the rules of Marshal.  The node's Tag isn't changed.
+//
+//	err := n.Set(kmip14.ResultStatusOperationFailed)
+func (n *Node) Set(v interface{}) error {
+	tag := n.Tag
+	if tag == TagNone {
+		// Marshal needs a tag.  It's discarded below
+		tag = tagNodeValue
+	}
+
+	b, err := Marshal(Value{Tag: tag, Value: v})
+	if err != nil {
+		return err
+	}
+
+	p := parseNode(b)
+	n.Type, n.Value, n.Children = p.Type, p.Value, p.Children
+
+	return nil
+}
+
+// tagNodeValue is a placeholder tag used when setting the value of nodes without a tag.
+const tagNodeValue Tag = 0x540000
+
+// Clone returns a deep copy of the node.
+func (n *Node) Clone() *Node {
+	c := &Node{
+		Tag:   n.Tag,
+		Type:  n.Type,
+		Value: n.Value,
+	}
+
+	switch v := n.Value.(type) {
+	case []byte:
+		c.Value = slices.Clone(v)
+	case *big.Int:
+		c.Value = new(big.Int).Set(v)
+	}
+
+	if n.Children != nil {
+		c.Children = make([]*Node, len(n.Children))
+		for i, child := range n.Children {
+			c.Children[i] = child.Clone()
+		}
+	}
+
+	return c
+}
+
+// Child returns the first child with the tag, or nil.
+func (n *Node) Child(tag Tag) *Node {
+	for _, c := range n.Children {
+		if c.Tag == tag {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Append adds children to the end of the node's children.
+func (n *Node) Append(children ...*Node) {
+	n.Children = append(n.Children, children...)
+}
+
+// Insert inserts children into the node's children at index i.  Panics if i is
+// out of range.
+func (n *Node) Insert(i int, children ...*Node) {
+	n.Children = slices.Insert(n.Children, i, children...)
+}
+
+// Remove removes child from the node's children.  Returns false if child isn't
+// one of the node's children.
+func (n *Node) Remove(child *Node) bool {
+	i := slices.Index(n.Children, child)
+	if i < 0 {
+		return false
+	}
+
+	n.Children = slices.Delete(n.Children, i, i+1)
+
+	return true
+}
+
+// Walk calls fn for the node and each of its descendants, depth first, in the order
+// they are encoded.  parent is nil for the node Walk is called on.  If fn returns
+// SkipChildren, Walk skips the node's children.  If fn returns any other error, Walk
+// stops and returns it.  fn may modify the node it is passed, including its children.
+func (n *Node) Walk(fn func(node, parent *Node) error) error {
+	return n.walk(nil, fn)
+}
+
+func (n *Node) walk(parent *Node, fn func(node, parent *Node) error) error {
+	switch err := fn(n, parent); {
+	case errors.Is(err, SkipChildren):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	for _, c := range n.Children {
+		if err := c.walk(n, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Find returns the first node selected by the path expression, or nil if the path
+// doesn't select any nodes.  Returns an error if the expression can't be parsed.
+// See Path for the syntax of path expressions.
+func (n *Node) Find(path string) (*Node, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	found := p.FindNodes(n)
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	return found[0], nil
+}
+
+// FindAll returns all the nodes selected by the path expression.  Returns an error
+// if the expression can't be parsed.  See Path for the syntax of path expressions.
+func (n *Node) FindAll(path string) ([]*Node, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.FindNodes(n), nil
+}
+
+// String renders the node in the same format as TTLV.String().
+func (n *Node) String() string {
+	b, err := Marshal(n)
+	if err != nil {
+		return err.Error()
+	}
+
+	return b.String()
+}
@@ -0,0 +1,164 @@
+package ttlv_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/Seagate/kmip-go/kmip14"
+	. "github.com/Seagate/kmip-go/ttlv"
+	"github.com/ansel1/merry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNode_roundtrip(t *testing.T) {
+	b, err := Marshal(Value{TagResponseMessage, Values{
+		{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+			{TagResultStatus, ResultStatusSuccess},
+			{TagResponsePayload, Values{
+				{TagUniqueIdentifier, "key1"},
+				{TagCryptographicLength, 256},
+				{TagKeyMaterial, []byte{0x01, 0x02, 0x03}},
+				{TagTimeStamp, time.Date(2021, 4, 12, 15, 30, 0, 0, time.UTC)},
+				{TagArchiveDate, DateTimeExtended{time.Date(2021, 4, 12, 15, 30, 0, 123000, time.UTC)}},
+				{TagUsageLimitsCount, int64(10)},
+				{TagP, big.NewInt(12345)},
+				{TagSensitive, true},
+				{TagLeaseTime, 10 * time.Second},
+				// vendor extension tag
+				{Tag(0x540001), Values{
+					{Tag(0x540002), "vendor"},
+				}},
+				{TagAttribute, Values{}},
+			}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	n, err := ParseNode(b)
+	require.NoError(t, err)
+
+	b2, err := Marshal(n)
+	require.NoError(t, err)
+	require.Equal(t, b, b2)
+
+	// so should unmarshaling
+	var n2 Node
+	require.NoError(t, Unmarshal(b, &n2))
+	require.Equal(t, n, &n2)
+
+	// the nodes shouldn't share memory with the original
+	km, err := n.Find("BatchItem/ResponsePayload/KeyMaterial")
+	require.NoError(t, err)
+
+	km.Value.([]byte)[0] = 0xff //nolint:forcetypeassert
+	orig, err := b.Find("BatchItem/ResponsePayload/KeyMaterial")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x01, 0x02, 0x03}, orig.ValueByteString())
+}
+
+func TestNode_edit(t *testing.T) {
+	b, err := Marshal(Value{TagResponseMessage, Values{
+		{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+			{TagResultStatus, ResultStatusSuccess},
+			{TagResponsePayload, Values{
+				{TagUniqueIdentifier, "key1"},
+			}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	n, err := ParseNode(b)
+	require.NoError(t, err)
+
+	// change a nested value
+	status, err := n.Find("BatchItem/ResultStatus")
+	require.NoError(t, err)
+	require.NoError(t, status.Set(ResultStatusOperationFailed))
+
+	// insert and remove children
+	bi := n.Child(TagBatchItem)
+	require.NotNil(t, bi)
+
+	reason, err := NewNode(TagResultReason, ResultReasonItemNotFound)
+	require.NoError(t, err)
+
+	bi.Insert(2, reason)
+	require.True(t, bi.Remove(bi.Child(TagResponsePayload)))
+	require.False(t, bi.Remove(reason.Clone()))
+
+	msg, err := NewNode(TagResultMessage, "not found")
+	require.NoError(t, err)
+	bi.Append(msg)
+
+	b2, err := Marshal(n)
+	require.NoError(t, err)
+
+	expected, err := Marshal(Value{TagResponseMessage, Values{
+		{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+			{TagResultStatus, ResultStatusOperationFailed},
+			{TagResultReason, ResultReasonItemNotFound},
+			{TagResultMessage, "not found"},
+		}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, expected, b2)
+
+	// setting a structure value replaces the children
+	require.NoError(t, bi.Set(Values{{TagOperation, OperationLocate}}))
+	require.Len(t, bi.Children, 1)
+	assert.Equal(t, TypeStructure, bi.Type)
+	assert.Equal(t, EnumValue(OperationLocate), bi.Children[0].Value)
+}
+
+func TestNode_Walk(t *testing.T) {
+	n, err := NewNode(TagBatchItem, Values{
+		{TagOperation, OperationGet},
+		{TagResponsePayload, Values{
+			{TagUniqueIdentifier, "key1"},
+		}},
+		{TagResultStatus, ResultStatusSuccess},
+	})
+	require.NoError(t, err)
+
+	var tags []Tag
+
+	err = n.Walk(func(node, parent *Node) error {
+		tags = append(tags, node.Tag)
+		if node.Tag == TagResponsePayload {
+			require.Equal(t, n, parent)
+			return SkipChildren
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Tag{TagBatchItem, TagOperation, TagResponsePayload, TagResultStatus}, tags)
+
+	errStop := errors.New("stop")
+	tags = nil
+
+	err = n.Walk(func(node, _ *Node) error {
+		tags = append(tags, node.Tag)
+		if node.Tag == TagUniqueIdentifier {
+			return errStop
+		}
+
+		return nil
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, []Tag{TagBatchItem, TagOperation, TagResponsePayload, TagUniqueIdentifier}, tags)
+}
+
+func TestNode_MarshalTTLV_typeMismatch(t *testing.T) {
+	n := &Node{Tag: TagBatchCount, Type: TypeInteger, Value: "red"}
+
+	_, err := Marshal(n)
+	require.Error(t, err)
+	require.True(t, merry.Is(err, ErrUnsupportedTypeError), "%+v", err)
+}
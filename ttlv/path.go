@@ -0,0 +1,439 @@
+package ttlv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ansel1/merry"
+)
+
+// ErrInvalidPath is the cause of errors returned when a path expression can't be parsed.
+var ErrInvalidPath = errors.New("invalid path")
+
+// Path selects values nested inside a TTLV Structure.  Paths are parsed from
+// expressions like:
+//
+//	BatchItem/ResponsePayload/UniqueIdentifier
+//
+// Each step of the path is separated by "/", and names the tag of the values it selects
+// from the Structures selected by the previous step.  The first step selects from
+// the values of the Structure the path is applied to.  Tags may be names or hex
+// values, like "0x420094".  "*" selects values with any tag.
+//
+// Each step may be followed by predicates in square brackets, which filter the
+// values selected by the step:
+//
+//	BatchItem[0]                          the first BatchItem
+//	Attribute[AttributeName="Name"]       Attributes with an AttributeName of "Name"
+//	BatchItem[ResultStatus="Success"]     BatchItems with a ResultStatus of Success
+//	KeyBlock[KeyWrappingData]             KeyBlocks which contain a KeyWrappingData
+//
+// Indexes start at 0, and count the values selected from each Structure.
+// Values compared by a predicate are parsed according to the type of the value
+// they are compared to: enumeration values and bitmasks can be names or numbers, byte
+// strings are hex, date-times are RFC3339, and intervals are go durations, like "10s".
+// Predicate values may be quoted, and must be if they contain "]".
+//
+// Multiple predicates are applied in order, so BatchItem[Operation="Get"][1] is the
+// second Get BatchItem, and BatchItem[1][Operation="Get"] is the second BatchItem, if
+// it is a Get.
+type Path struct {
+	expr  string
+	steps []pathStep
+	r     *Registry
+}
+
+type pathStep struct {
+	tag        Tag
+	any        bool
+	predicates []pathPredicate
+}
+
+type pathPredicate struct {
+	index int // -1 if this predicate isn't an index
+	tag   Tag
+	value *string // nil if the predicate only tests whether the tag is present
+}
+
+// ParsePath parses a path expression, using DefaultRegistry to parse tag names
+// and predicate values.
+func ParsePath(s string) (*Path, error) {
+	return DefaultRegistry.ParsePath(s)
+}
+
+// MustParsePath is like ParsePath, but panics if the expression can't be parsed.
+func MustParsePath(s string) *Path {
+	p, err := ParsePath(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// ParsePath parses a path expression, using the registry to parse tag names and
+// predicate values.
+func (r *Registry) ParsePath(s string) (*Path, error) {
+	p := &Path{expr: s, r: r}
+
+	rest := s
+
+	for {
+		var step pathStep
+
+		// the tag ends at the first "[" or "/"
+		end := strings.IndexAny(rest, "[/")
+		if end < 0 {
+			end = len(rest)
+		}
+
+		name := strings.TrimSpace(rest[:end])
+		rest = rest[end:]
+
+		switch name {
+		case "":
+			return nil, merry.Here(ErrInvalidPath).Appendf("empty step in %q", s)
+		case "*":
+			step.any = true
+		default:
+			tag, err := r.ParseTag(name)
+			if err != nil {
+				return nil, merry.Here(ErrInvalidPath).WithCause(err).Appendf("unknown tag %q in %q", name, s)
+			}
+
+			step.tag = tag
+		}
+
+		for strings.HasPrefix(rest, "[") {
+			pred, n, err := r.parsePredicate(rest)
+			if err != nil {
+				return nil, merry.Prependf(err, "in %q", s)
+			}
+
+			step.predicates = append(step.predicates, pred)
+			rest = rest[n:]
+		}
+
+		p.steps = append(p.steps, step)
+
+		if rest == "" {
+			return p, nil
+		}
+
+		if rest[0] != '/' {
+			return nil, merry.Here(ErrInvalidPath).Appendf("unexpected %q in %q", rest, s)
+		}
+
+		rest = rest[1:]
+	}
+}
+
+// parsePredicate parses the predicate at the start of s, and returns the number of
+// bytes it consumed.
+func (r *Registry) parsePredicate(s string) (pathPredicate, int, error) {
+	pred := pathPredicate{index: -1}
+
+	// skip the "["
+	i := 1
+
+	// read up to the end of the predicate, or the "=", whichever comes first
+	end := strings.IndexAny(s[i:], "=]")
+	if end < 0 {
+		return pred, 0, merry.Here(ErrInvalidPath).Append("unterminated predicate")
+	}
+
+	key := strings.TrimSpace(s[i : i+end])
+	i += end
+
+	if s[i] == ']' {
+		if idx, err := strconv.Atoi(key); err == nil {
+			if idx < 0 {
+				return pred, 0, merry.Here(ErrInvalidPath).Appendf("negative index %d", idx)
+			}
+
+			pred.index = idx
+
+			return pred, i + 1, nil
+		}
+	}
+
+	if key == "" {
+		return pred, 0, merry.Here(ErrInvalidPath).Append("empty predicate")
+	}
+
+	tag, err := r.ParseTag(key)
+	if err != nil {
+		return pred, 0, merry.Here(ErrInvalidPath).WithCause(err).Appendf("unknown tag %q", key)
+	}
+
+	pred.tag = tag
+
+	if s[i] == ']' {
+		return pred, i + 1, nil
+	}
+
+	// skip the "=", and parse the value
+	i++
+	rest := strings.TrimLeft(s[i:], " ")
+	i += len(s[i:]) - len(rest)
+
+	var value string
+
+	if strings.HasPrefix(rest, `"`) {
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return pred, 0, merry.Here(ErrInvalidPath).WithCause(err).Append("invalid quoted value")
+		}
+
+		value, _ = strconv.Unquote(quoted)
+		i += len(quoted)
+		rest = strings.TrimLeft(s[i:], " ")
+		i += len(s[i:]) - len(rest)
+
+		if !strings.HasPrefix(rest, "]") {
+			return pred, 0, merry.Here(ErrInvalidPath).Append("unterminated predicate")
+		}
+	} else {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return pred, 0, merry.Here(ErrInvalidPath).Append("unterminated predicate")
+		}
+
+		value = strings.TrimSpace(rest[:end])
+		i += end
+	}
+
+	pred.value = &value
+
+	// skip the "]"
+	return pred, i + 1, nil
+}
+
+// String returns the expression the path was parsed from.
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Find returns the first value selected by the path, or nil if the path
+// doesn't select any values.  The returned value is a slice of t.
+func (p *Path) Find(t TTLV) TTLV {
+	found := p.find(t, true)
+	if len(found) == 0 {
+		return nil
+	}
+
+	return found[0]
+}
+
+// FindAll returns all the values selected by the path, in the order they
+// appear in t.  The returned values are slices of t.
+func (p *Path) FindAll(t TTLV) []TTLV {
+	return p.find(t, false)
+}
+
+func (p *Path) find(t TTLV, first bool) []TTLV {
+	if t.ValidHeader() != nil || len(t) < t.FullLen() {
+		return nil
+	}
+
+	return pathFind(p, t[:t.FullLen()], first, ttlvTree{})
+}
+
+// FindNodes returns all the nodes in the tree rooted at n which are selected by the path,
+// in the order they are encoded.
+func (p *Path) FindNodes(n *Node) []*Node {
+	return pathFind(p, n, false, nodeTree{})
+}
+
+// tree abstracts the trees a Path can be applied to: TTLV values, and Nodes.
+type tree[T any] interface {
+	// eachChild calls fn with each value enclosed by v, if v is a Structure.
+	eachChild(v T, fn func(c T))
+	tag(v T) Tag
+	// ttlv returns the encoding of v, which isn't a Structure.
+	ttlv(v T) TTLV
+}
+
+type ttlvTree struct{}
+
+func (ttlvTree) eachChild(v TTLV, fn func(c TTLV)) {
+	eachValue(v, fn)
+}
+
+func (ttlvTree) tag(v TTLV) Tag {
+	return v.Tag()
+}
+
+func (ttlvTree) ttlv(v TTLV) TTLV {
+	return v
+}
+
+type nodeTree struct{}
+
+func (nodeTree) eachChild(v *Node, fn func(c *Node)) {
+	for _, c := range v.Children {
+		fn(c)
+	}
+}
+
+func (nodeTree) tag(v *Node) Tag {
+	return v.Tag
+}
+
+func (nodeTree) ttlv(v *Node) TTLV {
+	b, _ := Marshal(v)
+	return b
+}
+
+func pathFind[T any](p *Path, root T, first bool, tr tree[T]) []T {
+	current := []T{root}
+
+	for i, step := range p.steps {
+		var next []T
+
+		for _, parent := range current {
+			next = append(next, pathSelect(p, parent, step, tr)...)
+
+			if first && i == len(p.steps)-1 && len(next) > 0 {
+				return next[:1]
+			}
+		}
+
+		if len(next) == 0 {
+			return nil
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+// pathSelect returns the values of the parent Structure selected by the step.
+func pathSelect[T any](p *Path, parent T, step pathStep, tr tree[T]) []T {
+	var selected []T
+
+	tr.eachChild(parent, func(v T) {
+		if step.any || tr.tag(v) == step.tag {
+			selected = append(selected, v)
+		}
+	})
+
+	for _, pred := range step.predicates {
+		if pred.index >= 0 {
+			if pred.index >= len(selected) {
+				return nil
+			}
+
+			selected = selected[pred.index : pred.index+1]
+
+			continue
+		}
+
+		filtered := selected[:0]
+
+		for _, v := range selected {
+			if pathMatches(p, v, pred, tr) {
+				filtered = append(filtered, v)
+			}
+		}
+
+		selected = filtered
+	}
+
+	return selected
+}
+
+// pathMatches returns true if v is a Structure which contains a value matching the predicate.
+func pathMatches[T any](p *Path, v T, pred pathPredicate, tr tree[T]) bool {
+	var found bool
+
+	tr.eachChild(v, func(c T) {
+		if !found && tr.tag(c) == pred.tag && (pred.value == nil || p.valueEquals(tr.ttlv(c), *pred.value)) {
+			found = true
+		}
+	})
+
+	return found
+}
+
+// valueEquals returns true if s, parsed according to the type of v, equals v's value.
+func (p *Path) valueEquals(v TTLV, s string) bool {
+	switch v.Type() {
+	case TypeTextString:
+		return v.ValueTextString() == s
+	case TypeEnumeration:
+		e, err := p.r.ParseEnum(v.Tag(), s)
+		return err == nil && e == uint32(v.ValueEnumeration())
+	case TypeInteger:
+		i, err := p.r.ParseInt(v.Tag(), s)
+		return err == nil && i == v.ValueInteger()
+	case TypeLongInteger:
+		i, err := strconv.ParseInt(s, 0, 64)
+		return err == nil && i == v.ValueLongInteger()
+	case TypeBigInteger:
+		i, ok := new(big.Int).SetString(s, 0)
+		return ok && i.Cmp(v.ValueBigInteger()) == 0
+	case TypeBoolean:
+		b, err := strconv.ParseBool(s)
+		return err == nil && b == v.ValueBoolean()
+	case TypeByteString:
+		b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		return err == nil && bytes.Equal(b, v.ValueByteString())
+	case TypeDateTime, TypeDateTimeExtended:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil && t.Equal(v.ValueDateTime())
+	case TypeInterval:
+		d, err := time.ParseDuration(s)
+		return err == nil && d == v.ValueInterval()
+	default:
+		return false
+	}
+}
+
+// eachValue calls fn with each value enclosed in Structure t.  It stops at the
+// first malformed value, and does nothing if t isn't a Structure.
+func eachValue(t TTLV, fn func(v TTLV)) {
+	if t.Type() != TypeStructure {
+		return
+	}
+
+	for v := t.ValueStructure(); len(v) > 0; v = v[v.FullLen():] {
+		if v.ValidHeader() != nil || len(v) < v.FullLen() {
+			return
+		}
+
+		fn(v[:v.FullLen()])
+	}
+}
+
+// Find returns the first value selected by the path expression, or nil if the path
+// doesn't select any values.  Returns an error if the expression can't be parsed.
+// The returned value is a slice of t.  See Path for the syntax of path expressions.
+//
+//	uid, err := resp.Find("BatchItem/ResponsePayload/UniqueIdentifier")
+func (t TTLV) Find(path string) (TTLV, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Find(t), nil
+}
+
+// FindAll returns all the values selected by the path expression.  Returns an error
+// if the expression can't be parsed.  The returned values are slices of t.  See Path
+// for the syntax of path expressions.
+func (t TTLV) FindAll(path string) ([]TTLV, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.FindAll(t), nil
+}
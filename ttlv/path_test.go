@@ -0,0 +1,192 @@
+package ttlv_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/Seagate/kmip-go/kmip14"
+	. "github.com/Seagate/kmip-go/ttlv"
+	"github.com/ansel1/merry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTLV_Find(t *testing.T) {
+	resp, err := Marshal(Value{TagResponseMessage, Values{
+		{TagResponseHeader, Values{
+			{TagTimeStamp, time.Date(2021, 4, 12, 15, 30, 0, 0, time.UTC)},
+			{TagBatchCount, 3},
+		}},
+		{TagBatchItem, Values{
+			{TagOperation, OperationCreate},
+			{TagResultStatus, ResultStatusSuccess},
+			{TagResponsePayload, Values{
+				{TagObjectType, ObjectTypeSymmetricKey},
+				{TagUniqueIdentifier, "key1"},
+			}},
+		}},
+		{TagBatchItem, Values{
+			{TagOperation, OperationGetAttributes},
+			{TagUniqueBatchItemID, []byte{0x01, 0x02}},
+			{TagResultStatus, ResultStatusSuccess},
+			{TagResponsePayload, Values{
+				{TagUniqueIdentifier, "key2"},
+				{TagAttribute, Values{
+					{TagAttributeName, "Cryptographic Length"},
+					{TagAttributeValue, 256},
+				}},
+				{TagAttribute, Values{
+					{TagAttributeName, "Name"},
+					{TagAttributeValue, Values{
+						{TagNameValue, "red"},
+						{TagNameType, NameTypeUninterpretedTextString},
+					}},
+				}},
+				{TagAttribute, Values{
+					{TagAttributeName, "Name"},
+					{TagAttributeValue, Values{
+						{TagNameValue, "blue"},
+						{TagNameType, NameTypeUninterpretedTextString},
+					}},
+				}},
+			}},
+		}},
+		{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+			{TagResultStatus, ResultStatusOperationFailed},
+			{TagResultReason, ResultReasonItemNotFound},
+		}},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path     string
+		expected []Value
+	}{
+		{
+			path:     "BatchItem/ResponsePayload/UniqueIdentifier",
+			expected: []Value{{TagUniqueIdentifier, "key1"}, {TagUniqueIdentifier, "key2"}},
+		},
+		{
+			path:     "BatchItem[1]/ResponsePayload/UniqueIdentifier",
+			expected: []Value{{TagUniqueIdentifier, "key2"}},
+		},
+		{
+			path:     "BatchItem[Operation=Get]/ResultReason",
+			expected: []Value{{TagResultReason, ResultReasonItemNotFound}},
+		},
+		{
+			path:     "BatchItem[ResultStatus=Success][1]/Operation",
+			expected: []Value{{TagOperation, OperationGetAttributes}},
+		},
+		{
+			path:     "BatchItem[ResultReason]/Operation",
+			expected: []Value{{TagOperation, OperationGet}},
+		},
+		{
+			path:     "BatchItem[Operation=0x0000000a]/Operation",
+			expected: []Value{{TagOperation, OperationGet}},
+		},
+		{
+			path:     "BatchItem[UniqueBatchItemID=0102]/Operation",
+			expected: []Value{{TagOperation, OperationGetAttributes}},
+		},
+		{
+			path:     `BatchItem/ResponsePayload/Attribute[AttributeName="Name"]/AttributeValue/NameValue`,
+			expected: []Value{{TagNameValue, "red"}, {TagNameValue, "blue"}},
+		},
+		{
+			path:     `BatchItem/ResponsePayload/Attribute[AttributeName="Cryptographic Length"]/AttributeValue`,
+			expected: []Value{{TagAttributeValue, 256}},
+		},
+		{
+			path:     "ResponseHeader[TimeStamp=2021-04-12T15:30:00Z]/BatchCount",
+			expected: []Value{{TagBatchCount, 3}},
+		},
+		{
+			path:     "0x42000f[0]/0x42005c",
+			expected: []Value{{TagOperation, OperationCreate}},
+		},
+		{
+			path:     "BatchItem/*[ObjectType]/*",
+			expected: []Value{{TagObjectType, ObjectTypeSymmetricKey}, {TagUniqueIdentifier, "key1"}},
+		},
+		{
+			path: "BatchItem[3]",
+		},
+		{
+			path: "BatchItem[Operation=Locate]",
+		},
+		{
+			path: "BatchItem/ResultStatus/ResultStatus",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			all, err := resp.FindAll(tc.path)
+			require.NoError(t, err)
+
+			var expected []TTLV
+
+			for _, v := range tc.expected {
+				b, err := Marshal(v)
+				require.NoError(t, err)
+
+				expected = append(expected, b)
+			}
+
+			assert.Equal(t, expected, all)
+
+			first, err := resp.Find(tc.path)
+			require.NoError(t, err)
+
+			if len(expected) == 0 {
+				assert.Nil(t, first)
+			} else {
+				assert.Equal(t, expected[0], first)
+			}
+		})
+	}
+}
+
+func TestTTLV_Find_noCopy(t *testing.T) {
+	b, err := Marshal(Value{TagBatchItem, Values{
+		{TagOperation, OperationGet},
+	}})
+	require.NoError(t, err)
+
+	op, err := b.Find("Operation")
+	require.NoError(t, err)
+	require.Equal(t, EnumValue(OperationGet), op.ValueEnumeration())
+
+	// the result should share memory with the original value
+	b[len(b)-5] = 0x0b
+	assert.Equal(t, EnumValue(OperationGetAttributes), op.ValueEnumeration())
+}
+
+func TestParsePath_errors(t *testing.T) {
+	paths := []string{
+		"",
+		"BatchItem/",
+		"/BatchItem",
+		"BatchItem//Operation",
+		"NotATag",
+		"BatchItem[",
+		"BatchItem[0",
+		"BatchItem[-1]",
+		"BatchItem[]",
+		"BatchItem[NotATag=1]",
+		`BatchItem[Operation="Get]`,
+		`BatchItem[Operation="Get"x]`,
+		"BatchItem[0]x",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			_, err := ParsePath(path)
+			require.Error(t, err)
+			require.True(t, merry.Is(err, ErrInvalidPath), "%+v", err)
+		})
+	}
+}
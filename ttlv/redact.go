@@ -0,0 +1,37 @@
+package ttlv
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// Redacted is a TTLV value whose JSON and XML encodings replace the values of tags
+// marked sensitive in DefaultRegistry with a redaction marker, like "[redacted 32 bytes]".
+// The marker records the length of the value it replaces.  Use it to log values
+// which may contain key material or credentials:
+//
+//	logger.Debug("request", "ttlv", ttlv.Redacted(req))
+//
+// The redacted encodings can't be decoded back into the original values.  Print, and
+// so String(), always redact sensitive values, unless PrintOptions.Reveal is set.
+type Redacted TTLV
+
+// String renders the value using Print().
+func (r Redacted) String() string {
+	return TTLV(r).String()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r Redacted) MarshalJSON() ([]byte, error) {
+	return TTLV(r).marshalJSON(&DefaultRegistry, true)
+}
+
+// MarshalXML implements xml.Marshaler.
+func (r Redacted) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return TTLV(r).marshalXML(e, &DefaultRegistry, true)
+}
+
+// redactionMarker replaces a sensitive value of n bytes.
+func redactionMarker(n int) string {
+	return "[redacted " + strconv.Itoa(n) + " bytes]"
+}
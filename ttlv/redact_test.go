@@ -0,0 +1,182 @@
+package ttlv_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	. "github.com/Seagate/kmip-go/kmip14"
+	. "github.com/Seagate/kmip-go/ttlv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func redactionSample(t *testing.T) TTLV {
+	t.Helper()
+
+	b, err := Marshal(Value{TagKeyBlock, Values{
+		{TagKeyFormatType, KeyFormatTypeRaw},
+		{TagKeyValue, Values{
+			{TagKeyMaterial, []byte{0x01, 0x02, 0x03, 0x04}},
+		}},
+		{TagCryptographicLength, 32},
+	}})
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestRegistry_SetSensitive(t *testing.T) {
+	var r Registry
+
+	assert.False(t, r.IsSensitive(TagKeyMaterial))
+	assert.Empty(t, r.SensitiveTags())
+
+	r.SetSensitive(TagPassword, true)
+	r.SetSensitive(TagKeyMaterial, true)
+	assert.True(t, r.IsSensitive(TagKeyMaterial))
+	assert.Equal(t, []Tag{TagKeyMaterial, TagPassword}, r.SensitiveTags())
+
+	r.SetSensitive(TagKeyMaterial, false)
+	assert.False(t, r.IsSensitive(TagKeyMaterial))
+	assert.Equal(t, []Tag{TagPassword}, r.SensitiveTags())
+
+	// kmip14 registers its defaults
+	assert.True(t, DefaultRegistry.IsSensitive(TagKeyMaterial))
+	assert.True(t, DefaultRegistry.IsSensitive(TagPrivateExponent))
+	assert.False(t, DefaultRegistry.IsSensitive(TagUniqueIdentifier))
+}
+
+func TestPrint_redaction(t *testing.T) {
+	b := redactionSample(t)
+
+	assert.Equal(t, `KeyBlock (Structure/56):
+  KeyFormatType (Enumeration/4): Raw
+  KeyValue (Structure/16):
+    KeyMaterial (ByteString/4): [redacted 4 bytes]
+  CryptographicLength (Integer/4): 32`, b.String())
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Print(buf, "", "  ", b))
+	assert.Equal(t, b.String(), buf.String())
+
+	buf.Reset()
+	require.NoError(t, PrintPrettyHex(buf, "", "  ", b))
+	assert.Equal(t, `420040 | 01 | 00000038
+  420042 | 05 | 00000004 | 0000000100000000
+  420045 | 01 | 00000010
+    420043 | 08 | 00000004 | ****************
+  42002a | 02 | 00000004 | 0000002000000000`, buf.String())
+}
+
+func TestPrintOptions(t *testing.T) {
+	b := redactionSample(t)
+
+	// reveal applies to the call only
+	assert.Equal(t, `KeyBlock (Structure/56):
+  KeyFormatType (Enumeration/4): Raw
+  KeyValue (Structure/16):
+    KeyMaterial (ByteString/4): 0x01020304
+  CryptographicLength (Integer/4): 32`, PrintOptions{Reveal: true}.String(b))
+	assert.Contains(t, b.String(), "[redacted 4 bytes]")
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintOptions{Reveal: true}.PrintPrettyHex(buf, "", "  ", b))
+	assert.Contains(t, buf.String(), "420043 | 08 | 00000004 | 0102030400000000")
+
+	// nested values are masked according to the options' registry
+	var r Registry
+
+	RegisterTypes(&r)
+	Register(&r)
+	r.SetSensitive(TagKeyMaterial, false)
+	r.SetSensitive(TagCryptographicLength, true)
+
+	buf.Reset()
+	require.NoError(t, PrintOptions{Registry: &r}.PrintPrettyHex(buf, "", "  ", b))
+	assert.Equal(t, `420040 | 01 | 00000038
+  420042 | 05 | 00000004 | 0000000100000000
+  420045 | 01 | 00000010
+    420043 | 08 | 00000004 | 0102030400000000
+  42002a | 02 | 00000004 | ****************`, buf.String())
+}
+
+func TestRedacted(t *testing.T) {
+	b := redactionSample(t)
+
+	// redaction is opt-in for JSON and XML
+	s, err := json.Marshal(b)
+	require.NoError(t, err)
+	assert.Contains(t, string(s), `"01020304"`)
+
+	s, err = json.Marshal(Redacted(b))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tag":"KeyBlock","value":[
+		{"tag":"KeyFormatType","type":"Enumeration","value":"Raw"},
+		{"tag":"KeyValue","value":[
+			{"tag":"KeyMaterial","type":"ByteString","value":"[redacted 4 bytes]"}
+		]},
+		{"tag":"CryptographicLength","type":"Integer","value":32}
+	]}`, string(s))
+
+	s, err = xml.Marshal(Redacted(b))
+	require.NoError(t, err)
+	assert.Equal(t, `<KeyBlock>`+
+		`<KeyFormatType type="Enumeration" value="Raw"></KeyFormatType>`+
+		`<KeyValue><KeyMaterial type="ByteString" value="[redacted 4 bytes]"></KeyMaterial></KeyValue>`+
+		`<CryptographicLength type="Integer" value="32"></CryptographicLength>`+
+		`</KeyBlock>`, string(s))
+
+	// sensitive structures are redacted entirely
+	km, err := Marshal(Value{TagKeyMaterial, Values{
+		{TagPrivateExponent, 3},
+	}})
+	require.NoError(t, err)
+
+	s, err = json.Marshal(Redacted(km))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tag":"KeyMaterial","value":"[redacted 16 bytes]"}`, string(s))
+	assert.Equal(t, `KeyMaterial (Structure/16): [redacted 16 bytes]`, km.String())
+}
+
+func TestEncoder_Redact(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := NewJSONEncoder(buf)
+	enc.Redact = true
+
+	require.NoError(t, enc.Encode(Value{TagCredentialValue, Values{
+		{TagUsername, "fred"},
+		{TagPassword, "secret"},
+	}}))
+	assert.JSONEq(t, `{"tag":"CredentialValue","value":[
+		{"tag":"Username","type":"TextString","value":"fred"},
+		{"tag":"Password","type":"TextString","value":"[redacted 6 bytes]"}
+	]}`, buf.String())
+
+	buf.Reset()
+	enc = NewXMLEncoder(buf)
+	enc.Redact = true
+
+	require.NoError(t, enc.Encode(Value{TagPassword, "secret"}))
+	assert.Equal(t, `<Password type="TextString" value="[redacted 6 bytes]"></Password>`+"\n", buf.String())
+
+	// sensitive Structures are measured by their TTLV encoding
+	buf.Reset()
+	enc = NewJSONEncoder(buf)
+	enc.Redact = true
+
+	require.NoError(t, enc.Encode(Value{TagKeyMaterial, Values{
+		{TagPrivateExponent, []byte{0x01, 0x02, 0x03, 0x04}},
+	}}))
+	assert.JSONEq(t, `{"tag":"KeyMaterial","value":"[redacted 16 bytes]"}`, buf.String())
+
+	buf.Reset()
+	enc = NewXMLEncoder(buf)
+	enc.Redact = true
+
+	require.NoError(t, enc.Encode(Value{TagKeyMaterial, Values{
+		{TagPrivateExponent, []byte{0x01, 0x02, 0x03, 0x04}},
+	}}))
+	assert.Equal(t, `<KeyMaterial type="Structure" value="[redacted 16 bytes]"></KeyMaterial>`+"\n", buf.String())
+}
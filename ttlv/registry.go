@@ -1,7 +1,12 @@
 package ttlv
 
 import (
+	"encoding/xml"
+	"reflect"
+	"slices"
 	"sort"
+	"sync"
+	"sync/atomic"
 
 	"github.com/Seagate/kmip-go/internal/kmiputil"
 	"github.com/ansel1/merry"
@@ -9,10 +14,8 @@ import (
 
 // DefaultRegistry holds the default mappings of types, tags, enums, and bitmasks
 // to canonical names and normalized names from the KMIP spec.  It is pre-populated with the 1.4 spec's
-// values.  It can be replaced, or additional values can be registered with it.
-//
-// It is not currently concurrent-safe, so replace or configure it early in your
-// program.
+// values.  Additional values can be registered with it.  It is used by the package level
+// functions, like Print and Marshal, and by Encoders and Decoders without a Registry.
 var DefaultRegistry Registry
 
 //nolint:gochecknoinits
@@ -144,21 +147,94 @@ func (e *Enum) Bitmask() bool {
 // Registry holds all the known tags, types, enums and bitmaps declared in
 // a KMIP spec.  It's used throughout the package to map values their canonical
 // and normalized names.
+//
+// Tags and enum values can also be registered with the versions of the spec which
+// define them, using RegisterSince or SetTagAvailability and SetEnumValueAvailability.
+// ForVersion returns a view of the registry which only contains the values defined in
+// a single version.
+//
+// Registries are safe for concurrent use, including registering values while other
+// goroutines encode and decode.  The Enums passed to RegisterEnum are not, so populate
+// them before registering them.  A Registry must not be copied after first use.
+//
+// Set the Registry of Encoders and Decoders to use a registry other than DefaultRegistry,
+// e.g. to keep the definitions of vendor extensions with conflicting tags apart:
+//
+//	r := &ttlv.Registry{}
+//	ttlv.RegisterTypes(r)
+//	kmip14.Register(r)
+//	r.RegisterTag(0x540001, "AcmeWidget")
+//	dec := ttlv.NewDecoder(conn)
+//	dec.Registry = r
 type Registry struct {
-	enums map[Tag]EnumMap
-	tags  Enum
-	types Enum
+	mu               sync.RWMutex
+	enums            map[Tag]EnumMap
+	tags             Enum
+	types            Enum
+	tagAvailability  map[Tag]Availability
+	enumAvailability map[Tag]map[uint32]Availability
+	sensitive        map[Tag]bool
+
+	// typeInfos caches the typeInfo of each golang type marshaled with the registry.
+	// Tags are inferred from the names of types and fields, so registering a tag
+	// replaces the cache.
+	typeInfos atomic.Pointer[sync.Map]
+}
+
+// registryOrDefault returns r, or DefaultRegistry if r is nil.
+func registryOrDefault(r *Registry) *Registry {
+	if r == nil {
+		return &DefaultRegistry
+	}
+
+	return r
 }
 
 func (r *Registry) RegisterType(t Type, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	r.types.RegisterValue(uint32(t), name)
 }
 
 func (r *Registry) RegisterTag(t Tag, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	r.tags.RegisterValue(uint32(t), name)
+	r.typeInfos.Store(nil)
+}
+
+// typeInfo returns the cached typeInfo of typ, computing it if needed.  Errors
+// aren't cached.
+func (r *Registry) typeInfo(typ reflect.Type) (typeInfo, error) {
+	cache := r.typeInfos.Load()
+	if cache == nil {
+		cache = &sync.Map{}
+		if !r.typeInfos.CompareAndSwap(nil, cache) {
+			// another goroutine got there first
+			return r.typeInfo(typ)
+		}
+	}
+
+	if ti, ok := cache.Load(typ); ok {
+		return ti.(typeInfo), nil //nolint:forcetypeassert
+	}
+
+	ti, err := newTypeInfo(typ, r)
+	if err != nil {
+		return ti, err
+	}
+
+	cache.Store(typ, ti)
+
+	return ti, nil
 }
 
 func (r *Registry) RegisterEnum(t Tag, def EnumMap) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.enums == nil {
 		r.enums = map[Tag]EnumMap{}
 	}
@@ -169,13 +245,25 @@ func (r *Registry) RegisterEnum(t Tag, def EnumMap) {
 // EnumForTag returns the enum map registered for a tag.  Returns
 // nil if no map is registered for this tag.
 func (r *Registry) EnumForTag(t Tag) EnumMap {
-	if r.enums == nil {
-		return nil
-	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
 	return r.enums[t]
 }
 
+// enumsCopy returns a copy of the enums registered for each tag.
+func (r *Registry) enumsCopy() map[Tag]EnumMap {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	enums := make(map[Tag]EnumMap, len(r.enums))
+	for t, e := range r.enums {
+		enums[t] = e
+	}
+
+	return enums
+}
+
 func (r *Registry) IsBitmask(t Tag) bool {
 	if e := r.EnumForTag(t); e != nil {
 		return e.Bitmask()
@@ -192,12 +280,97 @@ func (r *Registry) IsEnum(t Tag) bool {
 	return false
 }
 
+// SetSensitive marks the tag as sensitive, or not.  The values of sensitive tags,
+// like key material and passwords, are replaced with a redaction marker by Print,
+// PrintPrettyHex, and Redacted.
+func (r *Registry) SetSensitive(t Tag, sensitive bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !sensitive {
+		delete(r.sensitive, t)
+		return
+	}
+
+	if r.sensitive == nil {
+		r.sensitive = map[Tag]bool{}
+	}
+
+	r.sensitive[t] = true
+}
+
+// IsSensitive returns true if the tag has been marked sensitive.
+func (r *Registry) IsSensitive(t Tag) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.sensitive[t]
+}
+
+// SensitiveTags returns the tags marked sensitive, in order.
+func (r *Registry) SensitiveTags() []Tag {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tags := make([]Tag, 0, len(r.sensitive))
+	for t := range r.sensitive {
+		tags = append(tags, t)
+	}
+
+	slices.Sort(tags)
+
+	return tags
+}
+
+// Tags returns the registered tags.  The returned EnumMap reads the registry's tags
+// under its lock, so it is safe to use while tags are being registered, and reflects
+// tags registered later.
 func (r *Registry) Tags() EnumMap {
-	return &r.tags
+	return lockedEnum{r: r, e: &r.tags}
 }
 
+// Types returns the registered types.  Like Tags, the returned EnumMap is safe to use
+// while types are being registered.
 func (r *Registry) Types() EnumMap {
-	return &r.types
+	return lockedEnum{r: r, e: &r.types}
+}
+
+// lockedEnum is an Enum of a Registry, which is read under the registry's lock.
+type lockedEnum struct {
+	r *Registry
+	e *Enum
+}
+
+func (l lockedEnum) Name(v uint32) (string, bool) {
+	l.r.mu.RLock()
+	defer l.r.mu.RUnlock()
+
+	return l.e.Name(v)
+}
+
+func (l lockedEnum) CanonicalName(v uint32) (string, bool) {
+	l.r.mu.RLock()
+	defer l.r.mu.RUnlock()
+
+	return l.e.CanonicalName(v)
+}
+
+func (l lockedEnum) Value(name string) (uint32, bool) {
+	l.r.mu.RLock()
+	defer l.r.mu.RUnlock()
+
+	return l.e.Value(name)
+}
+
+func (l lockedEnum) Values() []uint32 {
+	l.r.mu.RLock()
+	defer l.r.mu.RUnlock()
+
+	return l.e.Values()
+}
+
+func (l lockedEnum) Bitmask() bool {
+	return l.e.Bitmask()
 }
 
 func (r *Registry) FormatEnum(t Tag, v uint32) string {
@@ -209,14 +382,23 @@ func (r *Registry) FormatInt(t Tag, v int32) string {
 }
 
 func (r *Registry) FormatTag(t Tag) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	return FormatTag(uint32(t), &r.tags)
 }
 
 func (r *Registry) FormatTagCanonical(t Tag) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	return FormatTagCanonical(uint32(t), &r.tags)
 }
 
 func (r *Registry) FormatType(t Type) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	return FormatType(byte(t), &r.types)
 }
 
@@ -233,10 +415,16 @@ func (r *Registry) ParseInt(t Tag, s string) (int32, error) {
 // Returns TagNone if not found.
 // Returns error if s is a malformed hex string, or a hex string of incorrect length
 func (r *Registry) ParseTag(s string) (Tag, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	return ParseTag(s, &r.tags)
 }
 
 func (r *Registry) ParseType(s string) (Type, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	return ParseType(s, &r.types)
 }
 
@@ -246,3 +434,22 @@ type uint32Slice []uint32
 func (p uint32Slice) Len() int           { return len(p) }
 func (p uint32Slice) Less(i, j int) bool { return p[i] < p[j] }
 func (p uint32Slice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// registryTTLV is a TTLV value whose JSON and XML encodings use the names in a Registry
+// other than DefaultRegistry.  If redact is true, the values of the tags marked
+// sensitive in the registry are redacted.
+type registryTTLV struct {
+	t      TTLV
+	r      *Registry
+	redact bool
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v registryTTLV) MarshalJSON() ([]byte, error) {
+	return v.t.marshalJSON(v.r, v.redact)
+}
+
+// MarshalXML implements xml.Marshaler.
+func (v registryTTLV) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return v.t.marshalXML(e, v.r, v.redact)
+}
@@ -1,6 +1,11 @@
 package ttlv_test
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 
 	. "github.com/Seagate/kmip-go/kmip14"
@@ -128,3 +133,340 @@ func TestNormalizeNames(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistry_ForVersion(t *testing.T) {
+	var r Registry
+
+	RegisterTypes(&r)
+	RegisterGeneratedDefinitions(&r)
+
+	const (
+		tagProtectionLevel        = Tag(0x420142)
+		objectTypeCertRequest     = uint32(0x0a)
+		cryptographicUsageMaskNew = uint32(0x00100000)
+	)
+
+	r.RegisterSince(Version{Major: 2}, func(r *Registry) {
+		r.RegisterTag(tagProtectionLevel, "Protection Level")
+
+		objectTypes := NewObjectTypeEnum()
+		objectTypes.RegisterValue(objectTypeCertRequest, "Certificate Request")
+		r.RegisterEnum(TagObjectType, &objectTypes)
+
+		masks := NewCryptographicUsageMaskEnum()
+		masks.RegisterValue(cryptographicUsageMaskNew, "Authenticate")
+		r.RegisterEnum(TagCryptographicUsageMask, &masks)
+	})
+
+	assert.Equal(t, Availability{Introduced: Version{Major: 2}}, r.TagAvailability(tagProtectionLevel))
+	assert.Equal(t, Availability{}, r.TagAvailability(TagObjectType))
+	assert.Equal(t, Availability{Introduced: Version{Major: 2}}, r.EnumValueAvailability(TagObjectType, objectTypeCertRequest))
+	assert.Equal(t, Availability{}, r.EnumValueAvailability(TagObjectType, uint32(ObjectTypeSymmetricKey)))
+
+	v14 := r.ForVersion(Version{Major: 1, Minor: 4})
+	v20 := r.ForVersion(Version{Major: 2})
+
+	assert.False(t, v14.TagAvailable(tagProtectionLevel))
+	assert.True(t, v20.TagAvailable(tagProtectionLevel))
+
+	_, ok := v14.Tags().Value("ProtectionLevel")
+	assert.False(t, ok)
+	_, ok = v20.Tags().Value("ProtectionLevel")
+	assert.True(t, ok)
+
+	assert.NotContains(t, v14.EnumForTag(TagObjectType).Values(), objectTypeCertRequest)
+	assert.Contains(t, v20.EnumForTag(TagObjectType).Values(), objectTypeCertRequest)
+	_, ok = v14.EnumForTag(TagObjectType).Name(objectTypeCertRequest)
+	assert.False(t, ok)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		valid bool
+	}{
+		{
+			name:  "1.4enum",
+			value: Value{Tag: TagObjectType, Value: ObjectTypeSymmetricKey},
+			valid: true,
+		},
+		{
+			name:  "2.0enum",
+			value: Value{Tag: TagObjectType, Value: EnumValue(objectTypeCertRequest)},
+		},
+		{
+			name:  "2.0tag",
+			value: Value{Tag: TagBatchCount, Value: Values{{Tag: tagProtectionLevel, Value: 1}}},
+		},
+		{
+			name:  "2.0bitmask",
+			value: Value{Tag: TagCryptographicUsageMask, Value: int32(CryptographicUsageMaskSign) | int32(cryptographicUsageMaskNew)},
+		},
+		{
+			name: "2.0attributevalue",
+			value: Value{Tag: TagAttribute, Value: Values{
+				{Tag: TagAttributeName, Value: "Object Type"},
+				{Tag: TagAttributeValue, Value: EnumValue(objectTypeCertRequest)},
+			}},
+		},
+		{
+			name:  "unregisteredtag",
+			value: Value{Tag: Tag(0x540001), Value: 1},
+			valid: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := Marshal(tc.value)
+			require.NoError(t, err)
+
+			require.NoError(t, v20.Validate(b))
+
+			var buf bytes.Buffer
+
+			encErr := v14.NewEncoder(&buf).Encode(tc.value)
+			decErr := v14.NewDecoder(bytes.NewReader(b)).Decode(&Value{})
+
+			if tc.valid {
+				require.NoError(t, v14.Validate(b))
+				require.NoError(t, encErr)
+				require.NoError(t, decErr)
+				require.Equal(t, b, TTLV(buf.Bytes()))
+
+				return
+			}
+
+			require.True(t, errors.Is(v14.Validate(b), ErrNotInVersion))
+			require.True(t, errors.Is(encErr, ErrNotInVersion), "%+v", encErr)
+			require.True(t, errors.Is(decErr, ErrNotInVersion), "%+v", decErr)
+			require.Empty(t, buf.Bytes())
+		})
+	}
+}
+
+// newVendorRegistry returns a registry with the 1.4 definitions, plus a vendor extension
+// tag named name, and an enum for a second extension tag.
+func newVendorRegistry(name string) *Registry {
+	r := &Registry{}
+	RegisterTypes(r)
+	Register(r)
+	r.RegisterTag(Tag(0x540001), name)
+	r.RegisterTag(Tag(0x540002), name+"Kind")
+
+	e := NewEnum()
+	e.RegisterValue(1, name+"Small")
+	r.RegisterEnum(Tag(0x540002), &e)
+
+	return r
+}
+
+func TestRegistry_scoped(t *testing.T) {
+	widgets := newVendorRegistry("AcmeWidget")
+	gadgets := newVendorRegistry("AcmeGadget")
+
+	type widget struct {
+		AcmeWidget     int
+		AcmeWidgetKind EnumValue
+	}
+
+	type gadget struct {
+		AcmeGadget     int
+		AcmeGadgetKind EnumValue
+	}
+
+	v := Value{TagRequestPayload, Values{
+		{Tag(0x540001), 5},
+		{Tag(0x540002), EnumValue(1)},
+	}}
+
+	expected, err := Marshal(v)
+	require.NoError(t, err)
+
+	// the names of fields are parsed with the encoder's registry
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	enc.Registry = widgets
+	require.NoError(t, enc.EncodeValue(TagRequestPayload, widget{AcmeWidget: 5, AcmeWidgetKind: 1}))
+	assert.Equal(t, expected, TTLV(buf.Bytes()))
+
+	buf.Reset()
+
+	enc.Registry = gadgets
+	require.NoError(t, enc.EncodeValue(TagRequestPayload, gadget{AcmeGadget: 5, AcmeGadgetKind: 1}))
+	assert.Equal(t, expected, TTLV(buf.Bytes()))
+
+	// ...and by the decoder
+	var g gadget
+
+	dec := NewDecoder(bytes.NewReader(expected))
+	dec.Registry = gadgets
+	require.NoError(t, dec.Decode(&g))
+	assert.Equal(t, gadget{AcmeGadget: 5, AcmeGadgetKind: 1}, g)
+
+	// DefaultRegistry is unaffected
+	assert.Equal(t, "0x540001", Tag(0x540001).String())
+	assert.NotContains(t, expected.String(), "Acme")
+
+	// Print, JSON and XML use the registry's names
+	var sb strings.Builder
+
+	require.NoError(t, widgets.Print(&sb, "", "  ", expected))
+	assert.Contains(t, sb.String(), "AcmeWidget (Integer/4): 5")
+	assert.Contains(t, sb.String(), "AcmeWidgetKind (Enumeration/4): AcmeWidgetSmall")
+
+	parsed, err := widgets.ParseText(strings.NewReader(sb.String()))
+	require.NoError(t, err)
+	assert.Equal(t, expected, parsed)
+
+	_, err = gadgets.ParseText(strings.NewReader(sb.String()))
+	require.Error(t, err)
+
+	for _, format := range []string{"json", "xml"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			var enc *Encoder
+
+			switch format {
+			case "json":
+				enc = NewJSONEncoder(&buf)
+			default:
+				enc = NewXMLEncoder(&buf)
+			}
+
+			enc.Registry = widgets
+			require.NoError(t, enc.Encode(expected))
+			assert.Contains(t, buf.String(), "AcmeWidget")
+			assert.Contains(t, buf.String(), "AcmeWidgetSmall")
+
+			var dec *Decoder
+
+			switch format {
+			case "json":
+				dec = NewJSONDecoder(bytes.NewReader(buf.Bytes()))
+			default:
+				dec = NewXMLDecoder(bytes.NewReader(buf.Bytes()))
+			}
+
+			dec.Registry = widgets
+
+			decoded, err := dec.NextTTLV()
+			require.NoError(t, err)
+			assert.Equal(t, expected, decoded, Diff(expected, decoded))
+
+			// the names can't be parsed with the default registry
+			switch format {
+			case "json":
+				dec = NewJSONDecoder(bytes.NewReader(buf.Bytes()))
+			default:
+				dec = NewXMLDecoder(bytes.NewReader(buf.Bytes()))
+			}
+
+			_, err = dec.NextTTLV()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestRegistry_concurrent(t *testing.T) {
+	r := newVendorRegistry("AcmeWidget")
+
+	// kmip20 registers the registry's tags as the enum of AttributeReference.  It isn't
+	// imported, because its definitions would be registered in DefaultRegistry.
+	const tagAttributeReference = Tag(0x42013b)
+
+	r.RegisterEnum(tagAttributeReference, r.Tags())
+	r.RegisterEnum(TagExtensionType, r.Types())
+
+	b, err := Marshal(Value{TagRequestPayload, Values{
+		{Tag(0x540001), 5},
+		{TagCryptographicUsageMask, CryptographicUsageMaskEncrypt},
+	}})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				tag := Tag(0x550000 + i*1000 + j)
+				r.RegisterTag(tag, fmt.Sprintf("Extension%d", tag))
+				r.SetSensitive(tag, true)
+				r.SetTagAvailability(tag, Availability{})
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				var buf bytes.Buffer
+
+				enc := NewJSONEncoder(&buf)
+				enc.Registry = r
+				assert.NoError(t, enc.Encode(b))
+
+				// the type info of structs is cached by the registry
+				assert.NoError(t, enc.EncodeValue(TagRequestPayload, struct{ AcmeWidget int }{5}))
+
+				var sb strings.Builder
+
+				assert.NoError(t, r.Print(&sb, "", "  ", b))
+				_, _ = r.ParseTag(fmt.Sprintf("Extension%d", 0x550000+j))
+
+				// enums of the registry's own tags and types
+				_ = r.FormatEnum(tagAttributeReference, uint32(0x550000+j))
+				_ = r.FormatEnum(TagExtensionType, uint32(TypeInteger))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, "Extension5570560", r.FormatTag(Tag(0x550000)))
+}
+
+func TestRegistry_typeInfoCache(t *testing.T) {
+	r := &Registry{}
+	RegisterTypes(r)
+	Register(r)
+
+	type widget struct {
+		TTLVTag    struct{} `ttlv:"RequestPayload"`
+		AcmeWidget int      `ttlv:",omitempty"`
+	}
+
+	marshal := func(v widget) (TTLV, error) {
+		var buf bytes.Buffer
+
+		enc := NewEncoder(&buf)
+		enc.Registry = r
+		err := enc.Encode(v)
+
+		return buf.Bytes(), err
+	}
+
+	// caches the type info of widget, which has no tag for AcmeWidget
+	_, err := marshal(widget{})
+	require.NoError(t, err)
+
+	_, err = marshal(widget{AcmeWidget: 5})
+	require.ErrorIs(t, err, ErrNoTag)
+
+	// registering the tag replaces the cached type info
+	r.RegisterTag(Tag(0x540001), "AcmeWidget")
+
+	b, err := marshal(widget{AcmeWidget: 5})
+	require.NoError(t, err)
+
+	expected, err := Marshal(Value{TagRequestPayload, Values{
+		{Tag(0x540001), 5},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, expected, b)
+}
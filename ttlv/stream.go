@@ -0,0 +1,345 @@
+package ttlv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+
+	"github.com/ansel1/merry"
+)
+
+// ByteStream is a ByteString whose value is read from an io.Reader when it is marshaled,
+// rather than held in memory, like large opaque objects:
+//
+//	f, _ := os.Open("blob")
+//	fi, _ := f.Stat()
+//	type OpaqueObject struct {
+//	    OpaqueDataType  kmip14.OpaqueDataType
+//	    OpaqueDataValue ttlv.ByteStream
+//	}
+//	err := enc.Encode(OpaqueObject{
+//	    OpaqueDataType:  0x80000001,
+//	    OpaqueDataValue: ttlv.ByteStream{Reader: f, Len: int(fi.Size())},
+//	})
+//
+// See Encoder.EncodeByteStream.  A ByteStream with a nil Reader isn't marshaled.
+//
+// When unmarshaling, Len is set to the length of the value, and Reader reads the value
+// from the decoded TTLV, without copying it.  If the value was streamed to the Decoder's
+// ByteStreamWriter, Reader is nil.
+type ByteStream struct {
+	Reader io.Reader
+	// Len is the length of the value, in bytes.  Reader must supply at least Len bytes.
+	// Marshaling returns an error with cause ErrInvalidLen if Len is negative, or too
+	// large for a TTLV length.
+	Len int
+}
+
+var byteStreamType = reflect.TypeFor[ByteStream]()
+
+// MarshalTTLV implements Marshaler.
+func (b ByteStream) MarshalTTLV(e *Encoder, tag Tag) error {
+	if b.Reader == nil {
+		return nil
+	}
+
+	if !validStreamLen(b.Len) {
+		return e.marshalingError(tag, byteStreamType, ErrInvalidLen)
+	}
+
+	e.EncodeByteStream(tag, b.Reader, b.Len)
+
+	return nil
+}
+
+// UnmarshalTTLV implements Unmarshaler.
+func (b *ByteStream) UnmarshalTTLV(d *Decoder, ttlv TTLV) error {
+	if len(ttlv) == 0 {
+		return nil
+	}
+
+	if ttlv.Type() != TypeByteString {
+		return d.newUnmarshalerError(ttlv, byteStreamType, ErrUnsupportedTypeError)
+	}
+
+	if n, ok := d.streamed[&ttlv[0]]; ok {
+		*b = ByteStream{Len: n}
+		return nil
+	}
+
+	v := ttlv.ValueByteString()
+	*b = ByteStream{Reader: bytes.NewReader(v), Len: len(v)}
+
+	return nil
+}
+
+// pendingStream is a ByteString value which is copied from a reader when an encBuf is
+// written.
+type pendingStream struct {
+	// offset is where the value belongs in the buffer, after the ByteString's header
+	offset int
+	r      io.Reader
+	n      int
+}
+
+// paddedLen is the length of the value, plus padding to a multiple of 8 bytes.
+func (s pendingStream) paddedLen() int {
+	return s.n + (8-s.n%8)%8
+}
+
+// validStreamLen returns true if n can be the length of a ByteString.
+func validStreamLen(n int) bool {
+	return n >= 0 && uint64(n) <= math.MaxUint32
+}
+
+// beginStream writes the header of a ByteString with a value of n bytes, which will be
+// read from r when the buffer is written.  If n isn't a valid length, an empty value is
+// written instead, and the error is returned when the buffer is flushed.
+func (h *encBuf) beginStream(tag Tag, r io.Reader, n int) {
+	if !validStreamLen(n) {
+		if h.streamErr == nil {
+			h.streamErr = merry.Here(ErrInvalidLen).Appendf("byte stream length %d for tag %v", n, tag)
+		}
+
+		n = 0
+	}
+
+	i := h.begin(tag, TypeByteString)
+	binary.BigEndian.PutUint32(h.Bytes()[i-4:], uint32(n))
+	h.streams = append(h.streams, pendingStream{offset: i, r: r, n: n})
+}
+
+// readStream reads the value of a ByteString of n bytes from r into memory.  The
+// errors are those Flush returns for streams copied to the writer.
+func readStream(tag Tag, r io.Reader, n int) ([]byte, error) {
+	if !validStreamLen(n) {
+		return nil, merry.Here(ErrInvalidLen).Appendf("byte stream length %d for tag %v", n, tag)
+	}
+
+	b := make([]byte, n)
+
+	copied, err := io.ReadFull(r, b)
+
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return nil, merry.Here(io.ErrUnexpectedEOF).Appendf("%v: byte stream ended after %d of %d bytes", tag, copied, n)
+	case err != nil:
+		return nil, merry.Prependf(err, "%v: copying byte stream", tag)
+	}
+
+	return b, nil
+}
+
+// streamedLen is the total length of the streamed values, including padding, which
+// belong after offset i of the buffer.
+func (h *encBuf) streamedLen(i int) int {
+	var n int
+
+	for j := len(h.streams) - 1; j >= 0 && h.streams[j].offset >= i; j-- {
+		n += h.streams[j].paddedLen()
+	}
+
+	return n
+}
+
+// Reset empties the buffer, and discards the pending streams.
+func (h *encBuf) Reset() {
+	h.Buffer.Reset()
+	clear(h.streams)
+	h.streams = h.streams[:0]
+	h.streamErr = nil
+}
+
+// WriteTo writes the buffer to w, copying the value of each pending stream from its
+// reader in place.  Unlike bytes.Buffer, the buffer isn't drained.
+func (h *encBuf) WriteTo(w io.Writer) (int64, error) {
+	if len(h.streams) == 0 {
+		n, err := w.Write(h.Bytes())
+		return int64(n), err
+	}
+
+	var written int64
+
+	b := h.Bytes()
+	prev := 0
+
+	for _, s := range h.streams {
+		n, err := w.Write(b[prev:s.offset])
+		written += int64(n)
+
+		if err != nil {
+			return written, err
+		}
+
+		copied, err := io.CopyN(w, s.r, int64(s.n))
+		written += copied
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return written, merry.Here(io.ErrUnexpectedEOF).Appendf("%v: byte stream ended after %d of %d bytes", TTLV(b[s.offset-lenHeader:]).Tag(), copied, s.n)
+		case err != nil:
+			return written, merry.Prependf(err, "%v: copying byte stream", TTLV(b[s.offset-lenHeader:]).Tag())
+		}
+
+		n, err = w.Write(zeros[:s.paddedLen()-s.n])
+		written += int64(n)
+
+		if err != nil {
+			return written, err
+		}
+
+		prev = s.offset
+	}
+
+	n, err := w.Write(b[prev:])
+
+	return written + int64(n), err
+}
+
+// readStreams reads the values of the pending streams into the buffer.
+func (h *encBuf) readStreams() error {
+	if len(h.streams) == 0 {
+		return nil
+	}
+
+	var b bytes.Buffer
+
+	b.Grow(h.Len() + h.streamedLen(0))
+
+	if _, err := h.WriteTo(&b); err != nil {
+		return err
+	}
+
+	h.Reset()
+	_, _ = h.Write(b.Bytes())
+
+	return nil
+}
+
+// nextStreamingTTLV reads the next value from the stream, like NextTTLV, but copies
+// the values of large ByteStrings to the writers returned by ByteStreamWriter.
+// The streamed values are removed from the returned TTLV, which is read one
+// value at a time, so the lengths of the enclosing Structures can be corrected.
+func (dec *Decoder) nextStreamingTTLV() (TTLV, error) {
+	type openStructure struct {
+		// offset is the offset of the Structure's header
+		offset int
+		// remaining is the number of bytes of the Structure left to read from the stream
+		remaining int
+		// removed is the number of bytes of streamed values removed from the Structure
+		removed int
+		// items is the number of values read in the Structure
+		items int
+	}
+
+	var (
+		buf      []byte
+		open     []openStructure
+		streamed []pendingStream
+	)
+
+	// the limits are checked as each header is read, because the length of the
+	// message can't be trusted to limit what is buffered
+	for {
+		off := len(buf)
+		if dec.MaxMessageSize > 0 && off+lenHeader > dec.MaxMessageSize {
+			return buf, merry.Here(ErrMessageTooLarge).Appendf("message length exceeds limit of %d", dec.MaxMessageSize)
+		}
+
+		buf = append(buf, zeros[:]...)
+
+		if n, err := io.ReadFull(dec.bufr, buf[off:]); err != nil {
+			return buf, dec.withPosition(err, buf, off+n)
+		}
+
+		t := TTLV(buf[off:])
+		if err := t.ValidHeader(); err != nil {
+			return buf, merry.Prependf(err, "invalid header: %v", t)
+		}
+
+		if len(open) > 0 {
+			parent := &open[len(open)-1]
+			if t.FullLen() > parent.remaining {
+				return buf, dec.withPosition(merry.Here(ErrInvalidLen).Appendf("%v overruns the enclosing structure", t.Tag()), buf, off)
+			}
+
+			parent.remaining -= t.FullLen()
+
+			parent.items++
+			if dec.MaxItemsPerStructure > 0 && parent.items > dec.MaxItemsPerStructure {
+				return buf, merry.Here(ErrTooManyItems).Appendf("structure contains more than %d items", dec.MaxItemsPerStructure)
+			}
+		}
+
+		var w io.Writer
+
+		if t.Type() == TypeByteString && t.Len() > dec.ByteStreamThreshold {
+			var err error
+
+			w, err = dec.ByteStreamWriter(t.Tag(), t.Len())
+			if err != nil {
+				return buf, err
+			}
+		}
+
+		switch {
+		case t.Type() == TypeStructure:
+			if dec.MaxDepth > 0 && len(open)+1 > dec.MaxDepth {
+				return buf, merry.Here(ErrMaxDepthExceeded).Appendf("%s is nested more than %d structures deep", t.Tag(), dec.MaxDepth)
+			}
+
+			open = append(open, openStructure{offset: off, remaining: t.Len()})
+		case w != nil:
+			n := t.Len()
+			if _, err := io.CopyN(w, dec.bufr, int64(n)); err != nil {
+				return buf, dec.withPosition(merry.Prependf(err, "%v: streaming byte string", t.Tag()), buf, off)
+			}
+
+			s := pendingStream{offset: off, n: n}
+			if _, err := dec.bufr.Discard(s.paddedLen() - n); err != nil {
+				return buf, merry.Wrap(err)
+			}
+
+			// leave an empty byte string in place of the value
+			binary.BigEndian.PutUint32(buf[off+4:], 0)
+
+			for i := range open {
+				open[i].removed += s.paddedLen()
+			}
+
+			streamed = append(streamed, s)
+		default:
+			end := off + t.FullLen()
+			if dec.MaxMessageSize > 0 && end > dec.MaxMessageSize {
+				return buf, merry.Here(ErrMessageTooLarge).Appendf("message length exceeds limit of %d", dec.MaxMessageSize)
+			}
+
+			buf = append(buf, make([]byte, t.FullLen()-lenHeader)...)
+			if n, err := io.ReadFull(dec.bufr, buf[off+lenHeader:end]); err != nil {
+				return buf, dec.withPosition(err, buf, off+lenHeader+n)
+			}
+		}
+
+		// close the Structures which have been read completely
+		for len(open) > 0 && open[len(open)-1].remaining == 0 {
+			s := open[len(open)-1]
+			l := binary.BigEndian.Uint32(buf[s.offset+4:])
+			binary.BigEndian.PutUint32(buf[s.offset+4:], l-uint32(s.removed))
+			open = open[:len(open)-1]
+		}
+
+		if len(open) == 0 {
+			break
+		}
+	}
+
+	dec.streamed = make(map[*byte]int, len(streamed))
+	for _, s := range streamed {
+		dec.streamed[&buf[s.offset]] = s.n
+	}
+
+	return buf, nil
+}
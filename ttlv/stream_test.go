@@ -0,0 +1,270 @@
+package ttlv_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/Seagate/kmip-go/kmip14"
+	. "github.com/Seagate/kmip-go/ttlv"
+	"github.com/ansel1/merry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// opaqueBlob is an extension OpaqueDataType.  None are defined by the spec.
+const opaqueBlob = OpaqueDataType(0x80000001)
+
+type streamedObject struct {
+	TTLVTag         struct{} `ttlv:"OpaqueObject"`
+	OpaqueDataType  OpaqueDataType
+	OpaqueDataValue ByteStream
+	Description     string
+}
+
+type bufferedObject struct {
+	TTLVTag         struct{} `ttlv:"OpaqueObject"`
+	OpaqueDataType  OpaqueDataType
+	OpaqueDataValue []byte
+	Description     string
+}
+
+func TestEncoder_EncodeByteStream(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abc"), 1000)
+
+	expected, err := Marshal(Value{TagRequestPayload, Values{
+		{TagObjectType, ObjectTypeOpaqueObject},
+		{TagOpaqueObject, bufferedObject{OpaqueDataType: opaqueBlob, OpaqueDataValue: data, Description: "blob"}},
+	}})
+	require.NoError(t, err)
+
+	v := Value{TagRequestPayload, Values{
+		{TagObjectType, ObjectTypeOpaqueObject},
+		{TagOpaqueObject, streamedObject{
+			OpaqueDataType:  opaqueBlob,
+			OpaqueDataValue: ByteStream{Reader: bytes.NewReader(data), Len: len(data)},
+			Description:     "blob",
+		}},
+	}}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, NewEncoder(&buf).Encode(v))
+	assert.Equal(t, expected, TTLV(buf.Bytes()), Diff(expected, buf.Bytes()))
+
+	// a nil reader isn't encoded
+	b, err := Marshal(streamedObject{OpaqueDataType: opaqueBlob})
+	require.NoError(t, err)
+
+	expected, err = Marshal(bufferedObject{OpaqueDataType: opaqueBlob})
+	require.NoError(t, err)
+	assert.Equal(t, expected, b)
+
+	// JSON encoders read the stream into memory
+	var jsonBuf bytes.Buffer
+
+	v.Value.(Values)[1].Value = streamedObject{ //nolint:forcetypeassert
+		OpaqueDataType:  opaqueBlob,
+		OpaqueDataValue: ByteStream{Reader: bytes.NewReader(data[:5]), Len: 5},
+	}
+	require.NoError(t, NewJSONEncoder(&jsonBuf).Encode(v))
+
+	expected, err = Marshal(Value{TagRequestPayload, Values{
+		{TagObjectType, ObjectTypeOpaqueObject},
+		{TagOpaqueObject, bufferedObject{OpaqueDataType: opaqueBlob, OpaqueDataValue: data[:5]}},
+	}})
+	require.NoError(t, err)
+
+	expectedJSON, err := json.Marshal(expected)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expectedJSON), jsonBuf.String())
+}
+
+func TestEncoder_EncodeByteStream_short(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	err := enc.EncodeStructure(TagOpaqueObject, func(e *Encoder) error {
+		e.EncodeByteStream(TagOpaqueDataValue, strings.NewReader("short"), 10)
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = enc.Flush()
+	require.Error(t, err)
+	assert.True(t, merry.Is(err, io.ErrUnexpectedEOF), "expected io.ErrUnexpectedEOF, got %v", err)
+	assert.Contains(t, err.Error(), "5 of 10 bytes")
+}
+
+func TestEncoder_EncodeByteStream_invalidLen(t *testing.T) {
+	_, err := Marshal(streamedObject{
+		OpaqueDataType:  opaqueBlob,
+		OpaqueDataValue: ByteStream{Reader: strings.NewReader("data"), Len: -1},
+	})
+	require.Error(t, err)
+	assert.True(t, merry.Is(err, ErrInvalidLen), "expected ErrInvalidLen, got %v", err)
+
+	var buf bytes.Buffer
+
+	enc := NewEncoder(&buf)
+	enc.EncodeByteStream(TagOpaqueDataValue, strings.NewReader("data"), -1)
+
+	err = enc.Flush()
+	require.Error(t, err)
+	assert.True(t, merry.Is(err, ErrInvalidLen), "expected ErrInvalidLen, got %v", err)
+	assert.Zero(t, buf.Len())
+
+	// the error is discarded with the buffer
+	enc.EncodeByteStream(TagOpaqueDataValue, strings.NewReader("data"), 4)
+	require.NoError(t, enc.Flush())
+}
+
+func TestDecoder_ByteStreamWriter(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abc"), 1000)
+
+	msg, err := Marshal(Value{TagRequestPayload, Values{
+		{TagObjectType, ObjectTypeOpaqueObject},
+		{TagOpaqueObject, bufferedObject{OpaqueDataType: opaqueBlob, OpaqueDataValue: data, Description: "blob"}},
+		{TagNonceValue, []byte{0x01, 0x02, 0x03}},
+	}})
+	require.NoError(t, err)
+
+	// two messages in a row, to check the stream is left at the start of the next one
+	stream := append(append(TTLV{}, msg...), msg...)
+
+	var streamed bytes.Buffer
+
+	dec := NewDecoder(bytes.NewReader(stream))
+	dec.ByteStreamThreshold = 100
+	dec.MaxMessageSize = 200
+	dec.ByteStreamWriter = func(tag Tag, n int) (io.Writer, error) {
+		assert.Equal(t, TagOpaqueDataValue, tag)
+		assert.Equal(t, len(data), n)
+
+		return &streamed, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		streamed.Reset()
+
+		var v struct {
+			TTLVTag      struct{} `ttlv:"RequestPayload"`
+			ObjectType   ObjectType
+			OpaqueObject streamedObject
+			NonceValue   []byte
+		}
+
+		require.NoError(t, dec.Decode(&v))
+		assert.Equal(t, data, streamed.Bytes())
+		assert.Equal(t, ObjectTypeOpaqueObject, v.ObjectType)
+		assert.Equal(t, opaqueBlob, v.OpaqueObject.OpaqueDataType)
+		assert.Equal(t, ByteStream{Len: len(data)}, v.OpaqueObject.OpaqueDataValue)
+		assert.Equal(t, "blob", v.OpaqueObject.Description)
+		assert.Equal(t, []byte{0x01, 0x02, 0x03}, v.NonceValue)
+	}
+
+	// the streamed value is left empty, and the lengths are corrected
+	expected, err := Marshal(Value{TagRequestPayload, Values{
+		{TagObjectType, ObjectTypeOpaqueObject},
+		{TagOpaqueObject, bufferedObject{OpaqueDataType: opaqueBlob, OpaqueDataValue: []byte{}, Description: "blob"}},
+		{TagNonceValue, []byte{0x01, 0x02, 0x03}},
+	}})
+	require.NoError(t, err)
+
+	dec = NewDecoder(bytes.NewReader(msg))
+	dec.ByteStreamWriter = func(tag Tag, n int) (io.Writer, error) {
+		if tag == TagOpaqueDataValue {
+			return io.Discard, nil
+		}
+
+		// read other values into memory
+		return nil, nil
+	}
+
+	b, err := dec.NextTTLV()
+	require.NoError(t, err)
+	require.NoError(t, b.Valid())
+	assert.Equal(t, expected, b, Diff(expected, b))
+
+	// without streaming, values are read into memory and the limit applies
+	dec = NewDecoder(bytes.NewReader(msg))
+	dec.MaxMessageSize = 200
+
+	_, err = dec.NextTTLV()
+	assert.True(t, merry.Is(err, ErrMessageTooLarge), "expected ErrMessageTooLarge, got %v", err)
+
+	// ByteStream fields read values decoded in memory
+	var obj streamedObject
+
+	p, err := ParsePath("OpaqueObject")
+	require.NoError(t, err)
+	require.NoError(t, Unmarshal(p.Find(msg), &obj))
+
+	require.NotNil(t, obj.OpaqueDataValue.Reader)
+	assert.Equal(t, len(data), obj.OpaqueDataValue.Len)
+
+	read, err := io.ReadAll(obj.OpaqueDataValue.Reader)
+	require.NoError(t, err)
+	assert.Equal(t, data, read)
+}
+
+// header returns a TTLV header, without a value.
+func header(tag Tag, typ Type, n int) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b, uint32(tag)<<8|uint32(typ))
+	binary.BigEndian.PutUint32(b[4:], uint32(n))
+
+	return b
+}
+
+func TestDecoder_ByteStreamWriter_limits(t *testing.T) {
+	// the top level Structure claims to be huge, so the limits must be enforced as
+	// the values inside it are read, rather than after it's read
+	const huge = 1 << 30
+
+	nested := header(TagRequestPayload, TypeStructure, huge)
+	for i := 1; i < 10; i++ {
+		nested = append(nested, header(TagRequestPayload, TypeStructure, huge-8*i)...)
+	}
+
+	empty := header(TagRequestPayload, TypeStructure, huge)
+	for i := 0; i < 20; i++ {
+		empty = append(empty, header(TagRequestPayload, TypeStructure, 0)...)
+	}
+
+	streamed := header(TagRequestPayload, TypeStructure, huge)
+	for i := 0; i < 40; i++ {
+		streamed = append(streamed, header(TagOpaqueDataValue, TypeByteString, 128)...)
+		streamed = append(streamed, make([]byte, 128)...)
+	}
+
+	tests := []struct {
+		name     string
+		input    []byte
+		limits   Decoder
+		expected error
+	}{
+		{name: "depth", input: nested, limits: Decoder{MaxDepth: 4}, expected: ErrMaxDepthExceeded},
+		{name: "items", input: empty, limits: Decoder{MaxItemsPerStructure: 10}, expected: ErrTooManyItems},
+		{name: "size", input: streamed, limits: Decoder{MaxMessageSize: 200}, expected: ErrMessageTooLarge},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader(tc.input))
+			dec.MaxMessageSize = tc.limits.MaxMessageSize
+			dec.MaxDepth = tc.limits.MaxDepth
+			dec.MaxItemsPerStructure = tc.limits.MaxItemsPerStructure
+			dec.ByteStreamThreshold = 100
+			dec.ByteStreamWriter = func(tag Tag, n int) (io.Writer, error) {
+				return io.Discard, nil
+			}
+
+			_, err := dec.NextTTLV()
+			assert.True(t, merry.Is(err, tc.expected), "expected %v, got %v", tc.expected, err)
+		})
+	}
+}
@@ -4,6 +4,10 @@ const (
 	TagNone               = Tag(0)
 	tagAttributeName  Tag = 0x42000a
 	tagAttributeValue Tag = 0x42000b
+
+	tagTimeStamp              Tag = 0x420092
+	tagUniqueBatchItemID      Tag = 0x420093
+	tagServerCorrelationValue Tag = 0x420106
 )
 
 // Tag
@@ -48,3 +52,9 @@ func (t Tag) Valid() bool {
 		return false
 	}
 }
+
+// Extension returns true if the tag is in the range the spec reserves for
+// extensions, 0x54XXXX.
+func (t Tag) Extension() bool {
+	return uint32(t) >= minCustomTag && uint32(t) < maxCustomTag
+}
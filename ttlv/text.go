@@ -0,0 +1,276 @@
+package ttlv
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seagate/kmip-go/internal/kmiputil"
+	"github.com/ansel1/merry"
+)
+
+// ErrInvalidText is the cause of errors returned when text can't be parsed by ParseText.
+var ErrInvalidText = errors.New("invalid text")
+
+// textHeader matches the start of each value printed by Print, e.g.
+// "  ProtocolVersionMajor (Integer/4): 1".  The length is optional.
+var textHeader = regexp.MustCompile(`^(\s*)(\S+) \(([A-Za-z]+)(?:/\d+)?\):(?: (.*))?$`)
+
+// textTimeLayout is the format time.Time values are printed in by Print.
+const textTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// ParseText parses the human-readable format written by Print back into TTLV.  Each
+// line holds a single value:
+//
+//	ProtocolVersion (Structure/32):
+//	  ProtocolVersionMajor (Integer/4): 1
+//	  ProtocolVersionMinor (Integer/4): 0
+//
+// The values of a Structure are the lines which follow it, indented further than the
+// Structure.  Any amount of indentation can be used.  The lengths are ignored and
+// recalculated, and may be omitted, e.g. "ProtocolVersionMajor (Integer): 1", so values
+// can be edited by hand.  Tags may be names or hex values.  Values may be in the format
+// Print writes them, or:
+//
+//   - Integers, Long Integers, Big Integers and Enumerations may be decimal, or hex
+//     starting with "0x"
+//   - Integers may be the names of the values of a registered bitmask, joined with "|"
+//   - Enumerations may be registered names, including the values of Attribute Values,
+//     named by the preceding Attribute Name
+//   - Byte Strings may be hex, with or without "0x"
+//   - Date Times may be in RFC3339 format
+//   - Intervals may be durations, like "10s", or a number of seconds
+//
+// Text Strings continue on any following lines which don't start a new value, so multi-line
+// strings round trip.  Blank lines at the end of a Text String are lost.
+//
+// If there is more than one value at the outermost level of the text, the returned TTLV
+// holds all of them, one after the other.  Values redacted by Print can't be parsed, so
+// print values which must round trip with PrintOptions{Reveal: true}.
+//
+// Names are parsed with DefaultRegistry.
+func ParseText(r io.Reader) (TTLV, error) {
+	return DefaultRegistry.ParseText(r)
+}
+
+// ParseText is like the package level ParseText function, but parses tags, types, and
+// enum values with the names registered in reg.
+func (reg *Registry) ParseText(r io.Reader) (TTLV, error) {
+	var (
+		roots   []*Node
+		parents []*Node
+		indents []int
+		last    *Node
+		blanks  int
+		lineNum int
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		lineNum++
+
+		if strings.TrimSpace(line) == "" {
+			blanks++
+			continue
+		}
+
+		m := textHeader.FindStringSubmatch(line)
+		if m == nil {
+			if last == nil || last.Type != TypeTextString {
+				return nil, merry.Here(ErrInvalidText).Appendf("line %d: expected \"<tag> (<type>/<length>): <value>\", got %q", lineNum, line)
+			}
+
+			// continuation of a multi-line text string
+			last.Value = last.Value.(string) + strings.Repeat("\n", blanks+1) + line //nolint:forcetypeassert
+			blanks = 0
+
+			continue
+		}
+
+		blanks = 0
+		indent := len(m[1])
+
+		// the parent is the closest preceding structure which is indented less
+		for len(indents) > 0 && indents[len(indents)-1] >= indent {
+			parents = parents[:len(parents)-1]
+			indents = indents[:len(indents)-1]
+		}
+
+		var parent *Node
+		if len(parents) > 0 {
+			parent = parents[len(parents)-1]
+		}
+
+		n, err := reg.parseTextValue(m[2], m[3], m[4], parent)
+		if err != nil {
+			return nil, merry.Prependf(err, "line %d", lineNum)
+		}
+
+		if parent != nil {
+			parent.Children = append(parent.Children, n)
+		} else {
+			roots = append(roots, n)
+		}
+
+		if n.Type == TypeStructure {
+			parents = append(parents, n)
+			indents = append(indents, indent)
+		}
+
+		last = n
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(roots) == 0 {
+		return nil, merry.Here(ErrInvalidText).Append("no values")
+	}
+
+	var out TTLV
+
+	for _, n := range roots {
+		b, err := Marshal(n)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, b...)
+	}
+
+	return out, nil
+}
+
+// parseTextValue parses a single line of ParseText's input into a Node.
+func (reg *Registry) parseTextValue(tagS, typS, val string, parent *Node) (*Node, error) {
+	tag, err := reg.ParseTag(tagS)
+	if err != nil {
+		return nil, merry.Here(ErrInvalidText).WithCause(err).Appendf("invalid tag %q", tagS)
+	}
+
+	typ, err := reg.ParseType(typS)
+	if err != nil {
+		return nil, merry.Here(ErrInvalidText).WithCause(err).Appendf("invalid type %q", typS)
+	}
+
+	n := &Node{Tag: tag, Type: typ}
+
+	if strings.HasPrefix(val, "[redacted ") {
+		return nil, merry.Here(ErrInvalidText).Appendf("the value of %v was redacted", tag)
+	}
+
+	if typ == TypeStructure {
+		if val != "" {
+			return nil, merry.Here(ErrInvalidText).Appendf("unexpected value %q for Structure %v", val, tag)
+		}
+
+		return n, nil
+	}
+
+	if typ != TypeTextString {
+		val = strings.TrimSpace(val)
+	}
+
+	// the values of attributes are interpreted according to their attribute name
+	enumTag := tag
+	if tag == tagAttributeValue && parent != nil {
+		for _, c := range parent.Children {
+			if c.Tag == tagAttributeName && c.Type == TypeTextString {
+				//nolint:forcetypeassert
+				enumTag, _ = reg.ParseTag(kmiputil.NormalizeName(c.Value.(string)))
+			}
+		}
+	}
+
+	switch typ {
+	case TypeInteger:
+		n.Value, err = reg.ParseInt(enumTag, val)
+	case TypeLongInteger:
+		n.Value, err = parseTextLongInt(val)
+	case TypeBigInteger:
+		n.Value, err = parseTextBigInt(val)
+	case TypeEnumeration:
+		var e uint32
+
+		e, err = reg.ParseEnum(enumTag, val)
+		n.Value = EnumValue(e)
+	case TypeBoolean:
+		n.Value, err = strconv.ParseBool(val)
+	case TypeTextString:
+		n.Value = val
+	case TypeByteString:
+		n.Value, err = hex.DecodeString(strings.TrimPrefix(val, "0x"))
+	case TypeDateTime:
+		n.Value, err = parseTextTime(val)
+	case TypeDateTimeExtended:
+		var t time.Time
+
+		t, err = parseTextTime(val)
+		n.Value = DateTimeExtended{Time: t}
+	case TypeInterval:
+		n.Value, err = parseTextInterval(val)
+	}
+
+	if err != nil {
+		return nil, merry.Here(ErrInvalidText).WithCause(err).Appendf("invalid %v value %q for %v", typ, val, tag)
+	}
+
+	return n, nil
+}
+
+func parseTextLongInt(s string) (int64, error) {
+	if h, ok := strings.CutPrefix(s, "0x"); ok {
+		u, err := strconv.ParseUint(h, 16, 64)
+		return int64(u), err
+	}
+
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func parseTextBigInt(s string) (*big.Int, error) {
+	if h, ok := strings.CutPrefix(s, "0x"); ok {
+		// hex values are two's complement, like the encoded value
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return nil, err
+		}
+
+		n := &big.Int{}
+		unmarshalBigInt(n, b)
+
+		return n, nil
+	}
+
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, errors.New("must be a number")
+	}
+
+	return n, nil
+}
+
+func parseTextTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(textTimeLayout, s)
+}
+
+func parseTextInterval(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+
+	return time.ParseDuration(s)
+}
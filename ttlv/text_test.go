@@ -0,0 +1,182 @@
+package ttlv_test
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/Seagate/kmip-go/kmip14"
+	. "github.com/Seagate/kmip-go/ttlv"
+	"github.com/ansel1/merry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseText(t *testing.T) {
+	allTypes, err := Marshal(Value{TagResponsePayload, Values{
+		{TagTimeStamp, time.Date(2021, 4, 12, 15, 30, 0, 0, time.UTC)},
+		{TagArchiveDate, DateTimeExtended{Time: time.Date(2021, 4, 12, 15, 30, 0, 123000, time.UTC)}},
+		{TagLeaseTime, 10 * time.Second},
+		{TagUsageLimitsCount, int64(-10)},
+		{TagG, big.NewInt(-12345)},
+		{TagCryptographicUsageMask, CryptographicUsageMaskEncrypt | CryptographicUsageMaskDecrypt},
+		{TagCryptographicLength, 256},
+		{TagNonceID, []byte{}},
+		{TagIVCounterNonce, []byte{0x01, 0x02}},
+		{TagComment, "multi\n\nline\n  text "},
+		{TagObjectGroup, ""},
+		{TagSensitive, true},
+		{TagOperation, Operation(0x99)},
+		{Tag(0x540001), Values{
+			{Tag(0x540002), "vendor"},
+		}},
+	}})
+	require.NoError(t, err)
+
+	tests := map[string]TTLV{
+		"sample":   TTLV(Hex2bytes(sample)),
+		"allTypes": allTypes,
+	}
+
+	for name, b := range tests {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := ParseText(strings.NewReader(PrintOptions{Reveal: true}.String(b)))
+			require.NoError(t, err, Details(err))
+			assert.Equal(t, b, parsed, parsed.String())
+		})
+	}
+
+	// multiple values are concatenated
+	s := TTLV(Hex2bytes(sample))
+
+	reveal := PrintOptions{Reveal: true}
+
+	parsed, err := ParseText(strings.NewReader(reveal.String(s) + "\n\n" + reveal.String(allTypes) + "\n"))
+	require.NoError(t, err, Details(err))
+	assert.Equal(t, append(append(TTLV{}, s...), allTypes...), parsed)
+}
+
+func TestParseText_sensitive(t *testing.T) {
+	b, err := Marshal(Value{TagKeyBlock, Values{
+		{TagKeyFormatType, KeyFormatTypeRaw},
+		{TagKeyValue, Values{
+			{TagKeyMaterial, []byte{0x01, 0x02, 0x03, 0x04}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	// PrintOptions which reveal the KeyMaterial print text which can be parsed
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintOptions{Reveal: true}.Print(buf, "", "  ", b))
+
+	parsed, err := ParseText(buf)
+	require.NoError(t, err, Details(err))
+	assert.Equal(t, b, parsed, parsed.String())
+
+	// Print and String redact it, so it can't be parsed
+	buf.Reset()
+	require.NoError(t, Print(buf, "", "  ", b))
+
+	for _, s := range []string{buf.String(), b.String()} {
+		_, err = ParseText(strings.NewReader(s))
+		require.Error(t, err)
+		assert.True(t, merry.Is(err, ErrInvalidText), "expected ErrInvalidText, got %v", err)
+	}
+}
+
+func TestParseText_handEdited(t *testing.T) {
+	// lengths are wrong or missing, indentation is uneven, and values are in
+	// alternate formats
+	text := `RequestMessage (Structure/4):
+    RequestHeader (Structure):
+       ProtocolVersion (Structure/32):
+         ProtocolVersionMajor (Integer/4): 0x00000001
+         ProtocolVersionMinor (Integer): 4
+       BatchCount (Integer/4): 1
+    BatchItem (Structure):
+      Operation (Enumeration/4): 0x0000000a
+      RequestPayload (Structure/0):
+        UniqueIdentifier (TextString/0): key1
+        Attribute (Structure):
+          AttributeName (TextString): Cryptographic Algorithm
+          AttributeValue (Enumeration): AES
+        Attribute (Structure):
+          AttributeName (TextString): Cryptographic Usage Mask
+          AttributeValue (Integer): Encrypt|Decrypt
+        IVCounterNonce (ByteString): 0102
+        G (BigInteger): 0xffffffffffffcfc7
+        TimeStamp (DateTime): 2021-04-12T15:30:00Z
+        LeaseTime (Interval): 10
+        UsageLimitsCount (LongInteger): 0x10
+        0x540001 (TextString): vendor
+`
+
+	expected, err := Marshal(Value{TagRequestMessage, Values{
+		{TagRequestHeader, Values{
+			{TagProtocolVersion, Values{
+				{TagProtocolVersionMajor, 1},
+				{TagProtocolVersionMinor, 4},
+			}},
+			{TagBatchCount, 1},
+		}},
+		{TagBatchItem, Values{
+			{TagOperation, OperationGet},
+			{TagRequestPayload, Values{
+				{TagUniqueIdentifier, "key1"},
+				{TagAttribute, Values{
+					{TagAttributeName, "Cryptographic Algorithm"},
+					{TagAttributeValue, CryptographicAlgorithmAES},
+				}},
+				{TagAttribute, Values{
+					{TagAttributeName, "Cryptographic Usage Mask"},
+					{TagAttributeValue, CryptographicUsageMaskEncrypt | CryptographicUsageMaskDecrypt},
+				}},
+				{TagIVCounterNonce, []byte{0x01, 0x02}},
+				{TagG, big.NewInt(-12345)},
+				{TagTimeStamp, time.Date(2021, 4, 12, 15, 30, 0, 0, time.UTC)},
+				{TagLeaseTime, 10 * time.Second},
+				{TagUsageLimitsCount, int64(16)},
+				{Tag(0x540001), "vendor"},
+			}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	parsed, err := ParseText(strings.NewReader(text))
+	require.NoError(t, err, Details(err))
+	assert.Equal(t, expected, parsed, Diff(expected, parsed))
+}
+
+func TestParseText_errors(t *testing.T) {
+	tests := []struct {
+		name, text, msg string
+	}{
+		{name: "empty", text: "\n\n", msg: "no values"},
+		{name: "notText", text: "420069010000002042006a", msg: "line 1"},
+		{name: "unknownTag", text: "Foo (Integer/4): 1", msg: "invalid tag"},
+		{name: "unknownType", text: "BatchCount (Int/4): 1", msg: "invalid type"},
+		{name: "badInteger", text: "BatchCount (Integer/4): one", msg: "invalid Integer value"},
+		{name: "badEnum", text: "Operation (Enumeration/4): Fetch", msg: "invalid Enumeration value"},
+		{name: "badBoolean", text: "Sensitive (Boolean/8): maybe", msg: "invalid Boolean value"},
+		{name: "badByteString", text: "NonceID (ByteString/1): 0xzz", msg: "invalid ByteString value"},
+		{name: "badDateTime", text: "TimeStamp (DateTime/8): yesterday", msg: "invalid DateTime value"},
+		{name: "structureValue", text: "BatchItem (Structure/0): 1", msg: "unexpected value"},
+		{name: "redacted", text: "KeyMaterial (ByteString/4): [redacted 4 bytes]", msg: "redacted"},
+		{
+			name: "lineNumber",
+			text: "BatchItem (Structure/16):\n  BatchCount (Integer/4): 1\n  BatchCount (Integer/4): x",
+			msg:  "line 3",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseText(strings.NewReader(tc.text))
+			require.Error(t, err)
+			assert.True(t, merry.Is(err, ErrInvalidText), "expected ErrInvalidText, got %v", err)
+			assert.Contains(t, err.Error(), tc.msg)
+		})
+	}
+}
@@ -0,0 +1,890 @@
+package ttlv
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seagate/kmip-go/internal/kmiputil"
+	"github.com/ansel1/merry"
+)
+
+// textStream reads the values of the JSON or XML encoding one token at a time, without
+// buffering whole values.  next returns the next value.  After it returns a Structure,
+// the following calls return the values of the Structure, and then false at its end.  At
+// the end of the stream, next returns io.EOF.
+//
+// Values which can't be read are skipped, so after an error next can read the value
+// which follows, unless the text itself is malformed.
+type textStream interface {
+	next() (textValue, bool, error)
+	// offset returns the offset of the stream in its input.
+	offset() int64
+	// state returns the state shared by both encodings.
+	state() *textState
+}
+
+// textState tracks the Structures a textStream is reading, and checks the values read
+// against the Decoder's limits and View.
+type textState struct {
+	dec *Decoder
+	// view, if set, is checked against each value as it's read
+	view *View
+	// levels are the Structures being read, outermost first
+	levels []textLevel
+	// size is the length of the TTLV encoding of the current message read so far
+	size int
+	// name is the name of the last value read, in the path of values in errors
+	name string
+}
+
+type textLevel struct {
+	name    string
+	tag     Tag
+	attrTag Tag
+	items   int
+	// tags and counts count the values with each tag, for the indexes in paths
+	tags   []Tag
+	counts []int
+}
+
+func (s *textState) state() *textState {
+	return s
+}
+
+// enumTag returns the tag whose enum names the values of a value with tag, read
+// in the current Structure.
+func (s *textState) enumTag(tag Tag) Tag {
+	if len(s.levels) == 0 {
+		return tag
+	}
+
+	return enumTagFor(tag, s.levels[len(s.levels)-1].attrTag)
+}
+
+// read records that value tv has been read, and checks it against the limits and
+// the view.  If tv is a Structure, the following values are read as its values.
+func (s *textState) read(tv *textValue) error {
+	dec := s.dec
+	r := dec.registry()
+
+	if len(s.levels) == 0 {
+		// the start of a message
+		s.size = 0
+		s.name = r.FormatTag(tv.tag)
+	} else {
+		l := &s.levels[len(s.levels)-1]
+
+		l.items++
+		if dec.MaxItemsPerStructure > 0 && l.items > dec.MaxItemsPerStructure {
+			return merry.Here(ErrTooManyItems).Appendf("structure contains more than %d items", dec.MaxItemsPerStructure)
+		}
+
+		i := indexOfTag(l.tags, tv.tag)
+		if i < 0 {
+			l.tags = append(l.tags, tv.tag)
+			l.counts = append(l.counts, 0)
+			i = len(l.tags) - 1
+		}
+
+		s.name = r.FormatTag(tv.tag)
+		if l.counts[i] > 0 {
+			s.name += "[" + strconv.Itoa(l.counts[i]) + "]"
+		}
+
+		l.counts[i]++
+
+		if tv.tag == tagAttributeName && tv.typ == TypeTextString {
+			l.attrTag, _ = r.ParseTag(kmiputil.NormalizeName(tv.s))
+		}
+	}
+
+	if tv.typ == TypeStructure {
+		s.size += lenHeader
+	} else {
+		s.size += tv.encodedLen()
+	}
+
+	if dec.MaxMessageSize > 0 && s.size > dec.MaxMessageSize {
+		return merry.Here(ErrMessageTooLarge).Appendf("message length %d exceeds limit of %d", s.size, dec.MaxMessageSize)
+	}
+
+	if s.view != nil {
+		if err := s.view.validateValue(tv.tag, tv.typ, tv.enumTag, uint32(tv.n)); err != nil {
+			return err
+		}
+	}
+
+	if tv.typ == TypeStructure {
+		if dec.MaxDepth > 0 && len(s.levels)+1 > dec.MaxDepth {
+			return merry.Here(ErrMaxDepthExceeded).Appendf("%s is nested more than %d structures deep", tv.tag, dec.MaxDepth)
+		}
+
+		s.levels = append(s.levels, textLevel{name: s.name, tag: tv.tag})
+		s.name = ""
+	}
+
+	return nil
+}
+
+// leave records the end of the current Structure.
+func (s *textState) leave() {
+	s.name = s.levels[len(s.levels)-1].name
+	s.levels = s.levels[:len(s.levels)-1]
+}
+
+// path returns the path of the last value read, like "RequestMessage/BatchItem[1]/Operation",
+// in the syntax of ErrorPath.
+func (s *textState) path() string {
+	names := make([]string, 0, len(s.levels)+1)
+	for _, l := range s.levels {
+		names = append(names, l.name)
+	}
+
+	if s.name != "" {
+		names = append(names, s.name)
+	}
+
+	return strings.Join(names, "/")
+}
+
+func indexOfTag(tags []Tag, tag Tag) int {
+	for i, t := range tags {
+		if t == tag {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// jsonStream reads the KMIP JSON encoding.
+type jsonStream struct {
+	textState
+	d *json.Decoder
+}
+
+func newJSONStream(dec *Decoder, r io.Reader) *jsonStream {
+	return &jsonStream{textState: textState{dec: dec}, d: json.NewDecoder(r)}
+}
+
+func (s *jsonStream) offset() int64 {
+	return s.d.InputOffset()
+}
+
+func (s *jsonStream) next() (textValue, bool, error) {
+	if len(s.levels) > 0 && !s.d.More() {
+		return textValue{}, false, s.endStructure()
+	}
+
+	tok, err := s.d.Token()
+	if err != nil {
+		return textValue{}, false, err
+	}
+
+	if tok != json.Delim('{') {
+		return textValue{}, false, merry.Errorf("expected a KMIP JSON object, found %v", tok)
+	}
+
+	var tagS, typeS string
+
+	var v interface{}
+
+	for {
+		key, err := s.d.Token()
+		if err != nil {
+			return textValue{}, false, err
+		}
+
+		if key == json.Delim('}') {
+			break
+		}
+
+		switch key {
+		case "tag":
+			err = s.string(&tagS)
+		case "type":
+			err = s.string(&typeS)
+		case "value":
+			v, err = s.d.Token()
+			if err == nil && v == json.Delim('[') {
+				// the values of the Structure are read by the following calls to next
+				tv, err := s.structure(tagS, typeS)
+
+				return tv, err == nil, err
+			}
+		default:
+			err = s.skip(0)
+		}
+
+		if err != nil {
+			return textValue{}, false, err
+		}
+
+		if v == json.Delim('{') {
+			if err := s.skip(2); err != nil {
+				return textValue{}, false, err
+			}
+
+			return textValue{}, false, merry.New("value must not be an object")
+		}
+	}
+
+	r := s.dec.registry()
+
+	tag, err := r.ParseTag(tagS)
+	if err != nil {
+		return textValue{}, false, merry.Prepend(err, "invalid tag")
+	}
+
+	if typeS == "" {
+		return textValue{}, false, syntaxError(tag, TypeStructure, merry.New("value must be an array of values"))
+	}
+
+	tp, err := r.ParseType(typeS)
+	if err != nil {
+		return textValue{}, false, merry.Prepend(err, "invalid type")
+	}
+
+	tv, err := parseJSONValue(tag, tp, v, r, s.enumTag(tag))
+	if err != nil {
+		return tv, false, err
+	}
+
+	return tv, true, s.read(&tv)
+}
+
+// structure returns the header of a Structure whose values follow.  If the Structure
+// is rejected, its values are skipped.
+func (s *jsonStream) structure(tagS, typeS string) (textValue, error) {
+	tv, err := s.structureValue(tagS, typeS)
+	if err == nil {
+		err = s.read(&tv)
+	}
+
+	if err != nil {
+		// skip the rest of the array and the object
+		if err := s.skip(2); err != nil {
+			return tv, err
+		}
+	}
+
+	return tv, err
+}
+
+func (s *jsonStream) structureValue(tagS, typeS string) (textValue, error) {
+	if tagS == "" {
+		return textValue{}, merry.New("the tag of a Structure must precede its value")
+	}
+
+	tag, err := s.dec.registry().ParseTag(tagS)
+	if err != nil {
+		return textValue{}, merry.Prepend(err, "invalid tag")
+	}
+
+	return textValue{tag: tag, typ: TypeStructure, enumTag: tag}, s.checkStructureType(tag, typeS)
+}
+
+func (s *jsonStream) checkStructureType(tag Tag, typeS string) error {
+	if typeS == "" {
+		return nil
+	}
+
+	tp, err := s.dec.registry().ParseType(typeS)
+	if err != nil {
+		return merry.Prepend(err, "invalid type")
+	}
+
+	if tp != TypeStructure {
+		_, err := parseJSONValue(tag, tp, []interface{}{}, s.dec.registry(), tag)
+		return err
+	}
+
+	return nil
+}
+
+// endStructure reads the end of the array of values of a Structure, and the rest of the
+// object containing it.
+func (s *jsonStream) endStructure() error {
+	if _, err := s.d.Token(); err != nil {
+		return err
+	}
+
+	var keyErr error
+
+	for {
+		key, err := s.d.Token()
+		if err != nil {
+			return err
+		}
+
+		if key == json.Delim('}') {
+			break
+		}
+
+		switch key {
+		case "tag":
+			err = s.skip(0)
+			if keyErr == nil {
+				keyErr = merry.New("the tag of a Structure must precede its value")
+			}
+		case "type":
+			var typeS string
+			if err = s.string(&typeS); err == nil && keyErr == nil {
+				keyErr = s.checkStructureType(s.levels[len(s.levels)-1].tag, typeS)
+			}
+		default:
+			err = s.skip(0)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	s.leave()
+
+	return keyErr
+}
+
+func (s *jsonStream) string(dst *string) error {
+	tok, err := s.d.Token()
+	if err != nil {
+		return err
+	}
+
+	str, ok := tok.(string)
+	if !ok {
+		return merry.Errorf("expected a string, found %v", tok)
+	}
+
+	*dst = str
+
+	return nil
+}
+
+// skip skips tokens until depth arrays or objects, which have been opened, are
+// closed.  If depth is 0, it skips the next value.
+func (s *jsonStream) skip(depth int) error {
+	for {
+		tok, err := s.d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case json.Delim('['), json.Delim('{'):
+			depth++
+		case json.Delim(']'), json.Delim('}'):
+			depth--
+		}
+
+		if depth <= 0 {
+			return nil
+		}
+	}
+}
+
+// xmlStream reads the KMIP XML encoding.
+type xmlStream struct {
+	textState
+	d *xml.Decoder
+}
+
+func newXMLStream(dec *Decoder, r io.Reader) *xmlStream {
+	return &xmlStream{textState: textState{dec: dec}, d: xml.NewDecoder(r)}
+}
+
+func (s *xmlStream) offset() int64 {
+	return s.d.InputOffset()
+}
+
+func (s *xmlStream) next() (textValue, bool, error) {
+	for {
+		tok, err := s.d.Token()
+		if err != nil {
+			return textValue{}, false, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			tv, err := s.start(t)
+			if err != nil {
+				// skip the rest of the element
+				if err := s.d.Skip(); err != nil {
+					return tv, false, err
+				}
+			}
+
+			return tv, err == nil, err
+		case xml.EndElement:
+			if len(s.levels) == 0 {
+				return textValue{}, false, merry.Errorf("unexpected end element %s", t.Name.Local)
+			}
+
+			s.leave()
+
+			return textValue{}, false, nil
+		default:
+			// whitespace, comments, and processing instructions
+		}
+	}
+}
+
+func (s *xmlStream) start(se xml.StartElement) (textValue, error) {
+	tagS := se.Name.Local
+
+	var typeS, valueS string
+
+	for _, a := range se.Attr {
+		switch a.Name.Local {
+		case "tag":
+			if a.Value != "" {
+				tagS = a.Value
+			}
+		case "type":
+			typeS = a.Value
+		case "value":
+			valueS = a.Value
+		}
+	}
+
+	r := s.dec.registry()
+
+	tag, err := r.ParseTag(tagS)
+	if err != nil {
+		return textValue{}, merry.Prepend(err, "invalid tag")
+	}
+
+	tp := TypeStructure
+	if typeS != "" {
+		tp, err = r.ParseType(typeS)
+		if err != nil {
+			return textValue{}, merry.Prepend(err, "invalid type")
+		}
+	}
+
+	if tp == TypeStructure {
+		// the values of the Structure are read by the following calls to next
+		tv := textValue{tag: tag, typ: TypeStructure, enumTag: tag}
+		return tv, s.read(&tv)
+	}
+
+	tv, err := parseXMLValue(tag, tp, valueS, r, s.enumTag(tag))
+	if err == nil {
+		err = s.read(&tv)
+	}
+
+	if err == nil {
+		// skip the content of the element
+		err = s.d.Skip()
+	}
+
+	return tv, err
+}
+
+// skipText skips the rest of value tv, which was just read from the stream.
+func skipText(s textStream, tv textValue) error {
+	if tv.typ != TypeStructure {
+		return nil
+	}
+
+	depth := len(s.state().levels) - 1
+	for len(s.state().levels) > depth {
+		if _, _, err := s.next(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTextTTLV reads the rest of value tv, which was just read from the stream, and
+// returns its TTLV encoding.
+func readTextTTLV(s textStream, tv textValue) (TTLV, error) {
+	var buf encBuf
+	if err := writeTextTTLV(s, tv, &buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeTextTTLV(s textStream, tv textValue, buf *encBuf) error {
+	if tv.typ != TypeStructure {
+		buf.encodeTextValue(tv)
+		return nil
+	}
+
+	i := buf.begin(tv.tag, TypeStructure)
+
+	for {
+		c, ok, err := s.next()
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			break
+		}
+
+		if err := writeTextTTLV(s, c, buf); err != nil {
+			return err
+		}
+	}
+
+	buf.end(i)
+
+	return nil
+}
+
+// nextText reads the next value from the JSON or XML encoding into a TTLV buffer.
+func (dec *Decoder) nextText() (TTLV, error) {
+	dec.text.n = 0
+	dec.stream.state().view = nil
+
+	tv, _, err := dec.stream.next()
+	if err == nil {
+		var t TTLV
+
+		t, err = readTextTTLV(dec.stream, tv)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	dec.skipMessage()
+
+	return nil, merry.Wrap(err)
+}
+
+// skipMessage skips the rest of the message being read from the JSON or XML encoding,
+// so the next message can be read after an error.  It gives up if the text is malformed.
+func (dec *Decoder) skipMessage() {
+	for len(dec.stream.state().levels) > 0 {
+		off := dec.stream.offset()
+		if _, _, err := dec.stream.next(); err != nil && dec.stream.offset() == off {
+			return
+		}
+	}
+}
+
+// decodeText reads the next value from the JSON or XML encoding, and decodes it directly
+// into v, as its tokens are read.  Values are only transcoded to TTLV when they are decoded
+// into a value which needs TTLV, like an Unmarshaler, or an interface which holds a Structure.
+func (dec *Decoder) decodeText(v interface{}) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr {
+		return merry.New("non-pointer passed to Decode")
+	}
+
+	dec.text.n = 0
+	dec.stream.state().view = dec.View
+
+	tv, _, err := dec.stream.next()
+	if err == nil {
+		err = dec.decodeTextValue(val, tv)
+	}
+
+	if err != nil {
+		dec.skipMessage()
+
+		if name := val.Type().Elem().Name(); name != "" {
+			err = prependFieldPath(err, name)
+		}
+
+		return merry.Wrap(err)
+	}
+
+	return nil
+}
+
+// decodeTextValue decodes value tv, which was just read from the stream, and the rest
+// of its values, if it is a Structure, into val.  It follows the same rules as unmarshal.
+func (dec *Decoder) decodeTextValue(val reflect.Value, tv textValue) error {
+	// Load value from interface, but only if the result will be
+	// usefully addressable.
+	if val.Kind() == reflect.Interface && !val.IsNil() {
+		e := val.Elem()
+		if e.Kind() == reflect.Ptr && !e.IsNil() {
+			val = e
+		}
+	}
+
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+
+		val = val.Elem()
+	}
+
+	if dec.textNeedsTTLV(val, tv) {
+		path := dec.stream.state().path()
+
+		t, err := readTextTTLV(dec.stream, tv)
+		if err != nil {
+			return err
+		}
+
+		return textPosition(dec.unmarshal(val, t), path)
+	}
+
+	switch val.Kind() {
+	case reflect.Interface:
+		val.Set(reflect.ValueOf(tv.value()))
+		return nil
+	case reflect.Slice:
+		if val.Type().Elem() == byteType {
+			break
+		}
+
+		n := val.Len()
+		val.Set(reflect.Append(val, reflect.Zero(val.Type().Elem())))
+
+		if err := dec.decodeTextValue(val.Index(n), tv); err != nil {
+			val.SetLen(n)
+			return prependFieldPath(err, "["+strconv.Itoa(n)+"]")
+		}
+
+		return nil
+	default:
+	}
+
+	if tv.typ != TypeStructure {
+		return dec.setTextValue(val, tv)
+	}
+
+	if val.Kind() != reflect.Struct {
+		err := dec.textError(tv, val.Type(), ErrUnsupportedTypeError)
+		_ = skipText(dec.stream, tv)
+
+		return err
+	}
+
+	// stash currStruct
+	currStruct := dec.currStruct
+	err := dec.decodeTextStructure(val, tv)
+	// restore currStruct
+	dec.currStruct = currStruct
+
+	return err
+}
+
+// textNeedsTTLV returns true if tv must be transcoded to TTLV to decode it into val:
+// if val is an Unmarshaler, a map, or an interface which would hold a Structure.
+// Types with generated UnmarshalTTLV methods are decoded like other structs, as the
+// generated methods do the same.
+func (dec *Decoder) textNeedsTTLV(val reflect.Value, tv textValue) bool {
+	switch {
+	case val.Kind() == reflect.Interface:
+		return tv.typ == TypeStructure
+	case val.Kind() == reflect.Map:
+		return tv.typ == TypeStructure
+	case isGenerated(val.Type()):
+		return false
+	case implementsOwn(val.Type(), unmarshalerType, dec.registry()):
+		return true
+	default:
+		return val.CanAddr() && val.Addr().CanInterface() && implementsOwn(val.Addr().Type(), unmarshalerType, dec.registry())
+	}
+}
+
+func (dec *Decoder) decodeTextStructure(val reflect.Value, tv textValue) error {
+	ti, err := getTypeInfo(val.Type(), dec.registry())
+	if err != nil {
+		err := dec.textError(tv, val.Type(), err)
+		_ = skipText(dec.stream, tv)
+
+		return err
+	}
+
+	if ti.tagField != nil && ti.tagField.ti.typ == tagType {
+		val.FieldByIndex(ti.tagField.index).Set(reflect.ValueOf(tv.tag))
+	}
+
+	fields := ti.valueFields
+
+	// if the struct has validation flags, track which fields were found
+	var present []bool
+	if ti.validate {
+		present = make([]bool, len(fields))
+	}
+
+	// push currStruct (caller will pop)
+	dec.currStruct = val.Type()
+
+	for {
+		c, ok, err := dec.stream.next()
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			break
+		}
+
+		fldIdx := ti.fieldFor(c.tag)
+		if fldIdx < 0 {
+			if dec.DisallowExtraValues {
+				return dec.textError(c, val.Type(), ErrUnexpectedValue)
+			}
+
+			if err := skipText(dec.stream, c); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if present != nil {
+			present[fldIdx] = true
+		}
+
+		// push currField
+		currField := dec.currField
+		dec.currField = fields[fldIdx].name
+
+		fv, ok := allocFieldByIndex(val, fields[fldIdx].index)
+		if ok {
+			err = dec.decodeTextValue(fv, c)
+		} else {
+			err = dec.textError(c, fv.Type(), ErrUnsupportedTypeError)
+		}
+		// restore currField
+		dec.currField = currField
+
+		if err != nil {
+			return prependFieldPath(err, fields[fldIdx].name)
+		}
+	}
+
+	if present != nil {
+		return validateFields(val, ti, present, present)
+	}
+
+	return nil
+}
+
+// setTextValue decodes tv, which isn't a Structure, into val.  It follows the same
+// rules as unmarshal.
+func (dec *Decoder) setTextValue(val reflect.Value, tv textValue) error {
+	typeMismatchErr := func() error {
+		return dec.textError(tv, val.Type(), ErrUnsupportedTypeError)
+	}
+
+	switch tv.typ {
+	case TypeInterval:
+		if val.Kind() != reflect.Int64 {
+			return typeMismatchErr()
+		}
+
+		val.SetInt(int64(time.Duration(tv.n) * time.Second))
+	case TypeDateTime, TypeDateTimeExtended:
+		if val.Type() != timeType {
+			return typeMismatchErr()
+		}
+
+		val.Set(reflect.ValueOf(tv.time()))
+	case TypeByteString:
+		if val.Kind() != reflect.Slice && val.Type().Elem() != byteType {
+			return typeMismatchErr()
+		}
+
+		val.SetBytes(tv.b)
+	case TypeTextString:
+		if val.Kind() != reflect.String {
+			return typeMismatchErr()
+		}
+
+		val.SetString(tv.s)
+	case TypeBoolean:
+		if val.Kind() != reflect.Bool {
+			return typeMismatchErr()
+		}
+
+		val.SetBool(tv.n != 0)
+	case TypeEnumeration, TypeInteger:
+		// Enumerations are unsigned, and Integers are signed
+		i := int64(uint32(tv.n))
+		if tv.typ == TypeInteger {
+			i = int64(int32(tv.n))
+		}
+
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+			if val.OverflowInt(i) {
+				return dec.textError(tv, val.Type(), ErrIntOverflow)
+			}
+
+			val.SetInt(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			if val.OverflowUint(uint64(i)) {
+				return dec.textError(tv, val.Type(), ErrIntOverflow)
+			}
+
+			val.SetUint(uint64(i))
+		default:
+			return typeMismatchErr()
+		}
+	case TypeLongInteger:
+		switch val.Kind() {
+		case reflect.Int64:
+			val.SetInt(tv.n)
+		case reflect.Uint64:
+			val.SetUint(uint64(tv.n))
+		default:
+			return typeMismatchErr()
+		}
+	case TypeBigInteger:
+		if val.Type() != bigIntType {
+			return typeMismatchErr()
+		}
+
+		val.Set(reflect.ValueOf(*tv.i))
+	default:
+		return dec.textError(tv, val.Type(), ErrInvalidType)
+	}
+
+	return nil
+}
+
+// textError returns an *UnmarshalerError for decoding tv into a value of type valType.
+// Values read from JSON or XML have a Path, but no Offset.
+func (dec *Decoder) textError(tv textValue, valType reflect.Type, cause error) merry.Error {
+	e := &UnmarshalerError{
+		Struct: dec.currStruct,
+		Field:  dec.currField,
+		Tag:    tv.tag,
+		Type:   tv.typ,
+		Val:    valType,
+		Path:   dec.stream.state().path(),
+		Offset: -1,
+	}
+
+	return merry.WrapSkipping(e, 1).WithCause(cause)
+}
+
+// textPosition replaces the position of an *UnmarshalerError in a value transcoded to
+// TTLV with the position of the value in the message.  path is the path of the value.
+func textPosition(err error, path string) error {
+	var ue *UnmarshalerError
+	if !errors.As(err, &ue) || ue.Offset < 0 {
+		return err
+	}
+
+	// the first step of ue.Path is the value itself
+	if i := strings.IndexByte(ue.Path, '/'); i >= 0 {
+		path += ue.Path[i:]
+	}
+
+	ue.Path = path
+	ue.Offset = -1
+
+	return err
+}
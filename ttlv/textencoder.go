@@ -0,0 +1,289 @@
+package ttlv
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+
+	"github.com/Seagate/kmip-go/internal/kmiputil"
+)
+
+// textEncoder writes the values encoded by a JSON or XML Encoder as text, as they are
+// encoded, rather than encoding them to TTLV first.  The text is buffered until the
+// Encoder is flushed.
+type textEncoder struct {
+	format format
+	buf    bytes.Buffer
+	xmlEnc *xml.Encoder
+	// levels are the Structures being written, outermost first
+	levels []textEncoderLevel
+	// redacting counts the sensitive Structures being encoded to TTLV, to measure
+	// them for their redaction markers
+	redacting int
+	// err is the first error found in the buffered values, which Flush returns
+	err error
+}
+
+type textEncoderLevel struct {
+	// el is the element of the Structure in the XML encoding
+	el      xmlElement
+	attrTag Tag
+	items   int
+}
+
+func newTextEncoder(f format) *textEncoder {
+	x := &textEncoder{format: f}
+	x.xmlEnc = xml.NewEncoder(&x.buf)
+
+	return x
+}
+
+// fail records err, if it's the first error.
+func (x *textEncoder) fail(err error) {
+	if x.err == nil {
+		x.err = err
+	}
+}
+
+// reset discards the buffered values.
+func (x *textEncoder) reset() {
+	if x.err != nil || len(x.levels) > 0 {
+		// the XML encoder may have been left inside an element
+		x.xmlEnc = xml.NewEncoder(&x.buf)
+	}
+
+	x.buf.Reset()
+	x.levels = x.levels[:0]
+	x.redacting = 0
+	x.err = nil
+}
+
+// flush writes the buffered values to w, unless an error was found in them.
+func (x *textEncoder) flush(w io.Writer) error {
+	defer x.reset()
+
+	if x.err != nil {
+		return x.err
+	}
+
+	_, err := x.buf.WriteTo(w)
+
+	return err
+}
+
+// beginValue starts writing a value, and returns the level of the Structure which
+// contains it, or nil.
+func (x *textEncoder) beginValue() *textEncoderLevel {
+	if len(x.levels) == 0 {
+		return nil
+	}
+
+	l := &x.levels[len(x.levels)-1]
+	if l.items > 0 && x.format == formatJSON {
+		x.buf.WriteByte(',')
+	}
+
+	l.items++
+
+	return l
+}
+
+// endValue finishes writing a value.  Each message is followed by a newline.
+func (x *textEncoder) endValue() {
+	if len(x.levels) > 0 {
+		return
+	}
+
+	if x.format == formatXML {
+		x.fail(x.xmlEnc.Flush())
+	}
+
+	x.buf.WriteByte('\n')
+}
+
+// writesText returns true if the encoder writes the values it encodes as JSON or XML
+// text, rather than TTLV.
+func (e *Encoder) writesText() bool {
+	return e.text != nil && e.text.redacting == 0
+}
+
+// writeText writes value tv, which isn't a Structure.
+func (e *Encoder) writeText(tv textValue) {
+	x := e.text
+	r := e.registry()
+
+	tv.enumTag = tv.tag
+
+	// the Enumerations and Integers of AttributeValues are named by the preceding AttributeName
+	var attrValue bool
+
+	l := x.beginValue()
+	if l != nil {
+		tv.enumTag = enumTagFor(tv.tag, l.attrTag)
+		attrValue = tv.tag == tagAttributeValue && (tv.typ == TypeEnumeration || tv.typ == TypeInteger)
+
+		if tv.tag == tagAttributeName && tv.typ == TypeTextString {
+			l.attrTag, _ = r.ParseTag(kmiputil.NormalizeName(tv.s))
+		}
+	}
+
+	if e.View != nil {
+		x.fail(e.View.validateValue(tv.tag, tv.typ, tv.enumTag, uint32(tv.n)))
+	}
+
+	switch {
+	case e.Redact && !attrValue && r.IsSensitive(tv.tag):
+		e.writeRedactedText(tv.tag, tv.typ, tv.valueLen())
+	case x.format == formatJSON:
+		v, err := tv.jsonValue(r)
+		x.fail(err)
+
+		x.buf.WriteString(`{"tag":"`)
+		x.buf.WriteString(r.FormatTag(tv.tag))
+		x.buf.WriteString(`","type":"`)
+		x.buf.WriteString(r.FormatType(tv.typ))
+		x.buf.WriteString(`","value":`)
+		x.buf.WriteString(v)
+		x.buf.WriteString(`}`)
+	default:
+		out := newXMLElement(tv.tag, tv.typ, r)
+		out.Value = tv.xmlValue(r, attrValue)
+		x.fail(x.xmlEnc.Encode(&out))
+	}
+
+	x.endValue()
+}
+
+// writeRedactedText writes a redaction marker in place of a value of n bytes.
+func (e *Encoder) writeRedactedText(tag Tag, typ Type, n int) {
+	x := e.text
+	r := e.registry()
+
+	if x.format == formatJSON {
+		x.buf.WriteString(`{"tag":"`)
+		x.buf.WriteString(r.FormatTag(tag))
+
+		if typ != TypeStructure {
+			x.buf.WriteString(`","type":"`)
+			x.buf.WriteString(r.FormatType(typ))
+		}
+
+		x.buf.WriteString(`","value":"`)
+		x.buf.WriteString(redactionMarker(n))
+		x.buf.WriteString(`"}`)
+
+		return
+	}
+
+	out := newXMLElement(tag, typ, r)
+	out.Type = r.FormatType(typ)
+	out.Value = redactionMarker(n)
+	x.fail(x.xmlEnc.Encode(&out))
+}
+
+// beginText starts writing a Structure.
+func (e *Encoder) beginText(tag Tag) {
+	x := e.text
+	r := e.registry()
+
+	x.beginValue()
+
+	if e.View != nil {
+		x.fail(e.View.validateValue(tag, TypeStructure, tag, 0))
+	}
+
+	el := newXMLElement(tag, TypeStructure, r)
+
+	if x.format == formatJSON {
+		x.buf.WriteString(`{"tag":"`)
+		x.buf.WriteString(r.FormatTag(tag))
+		x.buf.WriteString(`","value":[`)
+	} else {
+		x.fail(x.xmlEnc.EncodeToken(el.start()))
+	}
+
+	x.levels = append(x.levels, textEncoderLevel{el: el})
+}
+
+// endText finishes writing a Structure.
+func (e *Encoder) endText() {
+	x := e.text
+
+	l := x.levels[len(x.levels)-1]
+	x.levels = x.levels[:len(x.levels)-1]
+
+	if x.format == formatJSON {
+		x.buf.WriteString(`]}`)
+	} else {
+		x.fail(x.xmlEnc.EncodeToken(xml.EndElement{Name: l.el.XMLName}))
+	}
+
+	x.endValue()
+}
+
+// encodeTextStructure writes a Structure whose values are encoded by f.  The values of
+// sensitive Structures are encoded to TTLV, to measure them for their redaction markers,
+// and to check them against the View.
+func (e *Encoder) encodeTextStructure(tag Tag, f func(e *Encoder) error) error {
+	if !e.Redact || !e.registry().IsSensitive(tag) {
+		e.beginText(tag)
+		err := f(e)
+		e.endText()
+
+		return err
+	}
+
+	x := e.text
+	start := e.encBuf.Len()
+
+	x.redacting++
+	i := e.encBuf.begin(tag, TypeStructure)
+	err := f(e)
+	e.encBuf.end(i)
+	x.redacting--
+
+	e.writeTextTTLV(e.encBuf.Bytes()[start:])
+	e.encBuf.Truncate(start)
+
+	return err
+}
+
+// writeTextTTLV writes a value which is already encoded as TTLV.
+func (e *Encoder) writeTextTTLV(t TTLV) {
+	if err := t.Valid(); err != nil {
+		e.text.fail(err)
+		return
+	}
+
+	e.writeValidTextTTLV(t)
+}
+
+func (e *Encoder) writeValidTextTTLV(t TTLV) {
+	x := e.text
+
+	if t.Type() != TypeStructure {
+		e.writeText(ttlvTextValue(t, t.Tag()))
+		return
+	}
+
+	if !e.Redact || !e.registry().IsSensitive(t.Tag()) {
+		e.beginText(t.Tag())
+
+		for c := t.ValueStructure(); c != nil; c = c.Next() {
+			e.writeValidTextTTLV(c)
+		}
+
+		e.endText()
+
+		return
+	}
+
+	x.beginValue()
+
+	if e.View != nil {
+		x.fail(e.View.validate(t, t.Tag()))
+	}
+
+	e.writeRedactedText(t.Tag(), TypeStructure, t.Len())
+	x.endValue()
+}
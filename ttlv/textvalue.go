@@ -0,0 +1,548 @@
+package ttlv
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Seagate/kmip-go/internal/kmiputil"
+	"github.com/ansel1/merry"
+)
+
+// textValue is a KMIP value read from or written to the JSON or XML encoding.  The
+// values of a Structure aren't read or written with it: see textStream and textEncoder.
+type textValue struct {
+	tag Tag
+	typ Type
+	// enumTag is the tag whose enum names the value, if it's an Enumeration or an
+	// Integer: the tag named by the preceding AttributeName for AttributeValues,
+	// otherwise the value's own tag.
+	enumTag Tag
+	// n holds the value of Integers, LongIntegers, Enumerations, Intervals (in seconds),
+	// Booleans (0 or 1), DateTimes (seconds since the epoch), and DateTimeExtendeds
+	// (microseconds since the epoch).
+	n int64
+	s string
+	// b holds the value of ByteStrings, and the TTLV encoding of BigIntegers read
+	// from TTLV, which may be longer than the encoding of i.
+	b []byte
+	i *big.Int
+}
+
+// ttlvTextValue returns the value of t, which isn't a Structure.  enumTag is the tag
+// whose enum names the value.
+func ttlvTextValue(t TTLV, enumTag Tag) textValue {
+	tv := textValue{tag: t.Tag(), typ: t.Type(), enumTag: enumTag}
+
+	switch tv.typ {
+	case TypeTextString:
+		tv.s = t.ValueTextString()
+	case TypeByteString:
+		tv.b = t.ValueByteString()
+	case TypeBigInteger:
+		tv.i = t.ValueBigInteger()
+		tv.b = t.ValueRaw()
+	case TypeBoolean:
+		tv.n = boolInt(t.ValueBoolean())
+	case TypeInteger:
+		tv.n = int64(t.ValueInteger())
+	case TypeEnumeration, TypeInterval:
+		tv.n = int64(binary.BigEndian.Uint32(t.ValueRaw()))
+	case TypeLongInteger, TypeDateTime, TypeDateTimeExtended:
+		tv.n = t.ValueLongInteger()
+	default:
+	}
+
+	return tv
+}
+
+// enumTagFor returns the tag whose enum names the values of a value with tag.  attrTag
+// is the tag named by the preceding AttributeName, if any.
+func enumTagFor(tag, attrTag Tag) Tag {
+	if tag == tagAttributeValue && attrTag != TagNone {
+		return attrTag
+	}
+
+	return tag
+}
+
+// parseJSONValue parses the value v of a value with tag and type tp, which isn't a
+// Structure, from the KMIP JSON encoding.  v is the value as decoded by encoding/json
+// into an interface{}.
+func parseJSONValue(tag Tag, tp Type, v interface{}, r *Registry, enumTag Tag) (textValue, error) {
+	tv := textValue{tag: tag, typ: tp, enumTag: enumTag}
+
+	syntaxError := func(err error) error {
+		return syntaxError(tag, tp, merry.HereSkipping(err, 1))
+	}
+
+	// performance note: for some types, like int, long int, and interval,
+	// we are essentially decoding from binary into a go native type
+	// then re-encoding to binary.  I benchmarked skipping this step
+	// and transferring the bytes directly from the decoded hex strings
+	// to the binary TTLV.  It turned out not be faster, and added an
+	// additional set of paths to test.  Wasn't worth it.
+
+	switch tp {
+	case TypeBoolean:
+		switch tv2 := v.(type) {
+		default:
+			return tv, syntaxError(errors.New("must be boolean or hex string"))
+		case bool:
+			tv.n = boolInt(tv2)
+		case string:
+			switch tv2 {
+			default:
+				return tv, syntaxError(errors.New("hex string for Boolean value must be either 0x0000000000000001 (true) or 0x0000000000000000 (false)"))
+			case "0x0000000000000001":
+				tv.n = 1
+			case "0x0000000000000000":
+				tv.n = 0
+			}
+		}
+	case TypeTextString:
+		s, ok := v.(string)
+		if !ok {
+			return tv, syntaxError(errors.New("must be string"))
+		}
+
+		tv.s = s
+	case TypeByteString:
+		s, ok := v.(string)
+		if !ok {
+			return tv, syntaxError(errors.New("must be hex string"))
+		}
+
+		// TODO: consider allowing this, just strip off the 0x prefix
+		// it's not to spec, but its a simple accommodation
+		if strings.HasPrefix(s, "0x") {
+			return tv, syntaxError(errors.New("should not have 0x prefix"))
+		}
+
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return tv, syntaxError(err)
+		}
+
+		tv.b = b
+	case TypeInterval:
+		switch tv2 := v.(type) {
+		default:
+			return tv, syntaxError(errors.New("must be number or hex string"))
+		case string:
+			b, err := kmiputil.ParseHexValue(tv2, 4)
+			if err != nil {
+				return tv, syntaxError(err)
+			}
+
+			if b == nil {
+				return tv, syntaxError(errors.New("hex value must start with 0x"))
+			}
+
+			tv.n = int64(kmiputil.DecodeUint32(b))
+		case float64:
+			if tv2 < 0 || tv2 > math.MaxUint32 || tv2 != math.Trunc(tv2) {
+				return tv, syntaxError(errors.New("must be a whole number of seconds between 0 and 4294967295"))
+			}
+
+			tv.n = int64(tv2)
+		}
+	case TypeDateTime, TypeDateTimeExtended:
+		s, ok := v.(string)
+		if !ok {
+			return tv, syntaxError(errors.New("must be string"))
+		}
+
+		return tv, parseDateTime(&tv, s, syntaxError)
+	case TypeInteger:
+		switch tv2 := v.(type) {
+		default:
+			return tv, syntaxError(errors.New("must be number, hex string, or mask value name"))
+		case string:
+			i, err := r.ParseInt(enumTag, tv2)
+			if err != nil {
+				return tv, syntaxError(err)
+			}
+
+			tv.n = int64(i)
+		case float64:
+			tv.n = int64(int32(tv2))
+		}
+	case TypeLongInteger:
+		switch tv2 := v.(type) {
+		default:
+			return tv, syntaxError(errors.New("must be number or hex string"))
+		case string:
+			b, err := kmiputil.ParseHexValue(tv2, 8)
+			if err != nil {
+				return tv, syntaxError(err)
+			}
+
+			if b == nil {
+				return tv, syntaxError(errors.New("hex value must start with 0x"))
+			}
+
+			tv.n = int64(kmiputil.DecodeUint64(b))
+		case float64:
+			tv.n = int64(tv2)
+		}
+	case TypeBigInteger:
+		switch tv2 := v.(type) {
+		default:
+			return tv, syntaxError(errors.New("must be number or hex string"))
+		case string:
+			if !strings.HasPrefix(tv2, "0x") {
+				return tv, syntaxError(errors.New("hex value must start with 0x"))
+			}
+
+			b, err := hex.DecodeString(tv2[2:])
+			if err != nil {
+				return tv, syntaxError(err)
+			}
+
+			if len(b)%8 != 0 {
+				return tv, syntaxError(errors.New("must be multiple of 8 bytes (16 hex characters)"))
+			}
+
+			tv.i = &big.Int{}
+			unmarshalBigInt(tv.i, unpadBigInt(b))
+		case float64:
+			tv.i = big.NewInt(int64(tv2))
+		}
+	case TypeEnumeration:
+		switch tv2 := v.(type) {
+		default:
+			return tv, syntaxError(errors.New("must be number or string"))
+		case string:
+			u, err := r.ParseEnum(enumTag, tv2)
+			if err != nil {
+				return tv, syntaxError(err)
+			}
+
+			tv.n = int64(u)
+		case float64:
+			tv.n = int64(uint32(tv2))
+		}
+	default:
+		return tv, syntaxError(errors.New("must be a Structure"))
+	}
+
+	return tv, nil
+}
+
+// parseXMLValue parses the value attribute s of a value with tag and type tp, which
+// isn't a Structure, from the KMIP XML encoding.
+func parseXMLValue(tag Tag, tp Type, s string, r *Registry, enumTag Tag) (textValue, error) {
+	tv := textValue{tag: tag, typ: tp, enumTag: enumTag}
+
+	syntaxError := func(err error) error {
+		return syntaxError(tag, tp, merry.HereSkipping(err, 1))
+	}
+
+	switch tp {
+	case TypeBoolean:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return tv, syntaxError(merry.Prepend(err, "must be 0, 1, true, or false"))
+		}
+
+		tv.n = boolInt(b)
+	case TypeTextString:
+		tv.s = s
+	case TypeByteString:
+		// TODO: consider allowing this, just strip off the 0x prefix
+		// it's not to spec, but its a simple accommodation
+		if strings.HasPrefix(s, "0x") {
+			return tv, syntaxError(errors.New("should not have 0x prefix"))
+		}
+
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return tv, syntaxError(err)
+		}
+
+		tv.b = b
+	case TypeInterval:
+		u, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return tv, syntaxError(merry.Prepend(err, "must be a number"))
+		}
+
+		tv.n = int64(u)
+	case TypeDateTime, TypeDateTimeExtended:
+		return tv, parseDateTime(&tv, s, syntaxError)
+	case TypeInteger:
+		i, err := r.ParseInt(enumTag, strings.ReplaceAll(s, " ", "|"))
+		if err != nil {
+			return tv, syntaxError(err)
+		}
+
+		tv.n = int64(i)
+	case TypeLongInteger:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return tv, syntaxError(merry.Prepend(err, "must be number"))
+		}
+
+		tv.n = i
+	case TypeBigInteger:
+		// TODO: consider allowing this, just strip off the 0x prefix
+		// it's not to spec, but its a simple accommodation
+		if strings.HasPrefix(s, "0x") {
+			return tv, syntaxError(merry.New("should not have 0x prefix"))
+		}
+
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return tv, syntaxError(err)
+		}
+
+		if len(b)%8 != 0 {
+			return tv, syntaxError(errors.New("must be multiple of 8 bytes"))
+		}
+
+		tv.i = &big.Int{}
+		unmarshalBigInt(tv.i, b)
+	case TypeEnumeration:
+		e, err := r.ParseEnum(enumTag, s)
+		if err != nil {
+			return tv, syntaxError(err)
+		}
+
+		tv.n = int64(e)
+	default:
+		return tv, syntaxError(errors.New("must be a Structure"))
+	}
+
+	return tv, nil
+}
+
+// parseDateTime parses a DateTime or DateTimeExtended, written in ISO8601 format, or
+// as a hex string of the seconds or microseconds since the epoch, for times which
+// can't be written in ISO8601 format.
+func parseDateTime(tv *textValue, s string, syntaxError func(error) error) error {
+	b, err := kmiputil.ParseHexValue(s, 8)
+	if err != nil {
+		return syntaxError(err)
+	}
+
+	if b != nil {
+		tv.n = int64(kmiputil.DecodeUint64(b))
+		return nil
+	}
+
+	tm, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return syntaxError(merry.Prepend(err, "must be ISO8601 format"))
+	}
+
+	if tv.typ == TypeDateTime {
+		tv.n = tm.Unix()
+	} else {
+		// see encodeDateTimeExtended
+		tv.n = (tm.Unix() * 1000000) + int64(tm.Nanosecond()/1000)
+	}
+
+	return nil
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// encodeTextValue encodes a value which isn't a Structure.
+func (h *encBuf) encodeTextValue(tv textValue) {
+	switch tv.typ {
+	case TypeBoolean:
+		h.writeLongIntVal(tv.tag, TypeBoolean, tv.n)
+	case TypeTextString:
+		h.encodeTextString(tv.tag, tv.s)
+	case TypeByteString:
+		i := h.begin(tv.tag, TypeByteString)
+		_, _ = h.Write(tv.b)
+		h.end(i)
+	case TypeInterval, TypeInteger, TypeEnumeration:
+		h.writeIntVal(tv.tag, tv.typ, uint32(tv.n))
+	case TypeDateTime, TypeDateTimeExtended, TypeLongInteger:
+		h.writeLongIntVal(tv.tag, tv.typ, tv.n)
+	case TypeBigInteger:
+		h.encodeBigInt(tv.tag, tv.i)
+	default:
+	}
+}
+
+// encodedLen returns the length of the TTLV encoding of a value which isn't a
+// Structure, including its header and padding.
+func (tv *textValue) encodedLen() int {
+	return lenHeader + (tv.valueLen()+7)/8*8
+}
+
+// valueLen returns the length of the TTLV encoding of the value, without its header
+// or padding, like TTLV.Len.
+func (tv *textValue) valueLen() int {
+	switch tv.typ {
+	case TypeTextString:
+		return len(tv.s)
+	case TypeByteString:
+		return len(tv.b)
+	case TypeBigInteger:
+		return len(tv.raw())
+	case TypeInteger, TypeEnumeration, TypeInterval:
+		return 4
+	default:
+		return 8
+	}
+}
+
+// raw returns the TTLV encoding of a LongInteger, BigInteger, Boolean, DateTime,
+// or DateTimeExtended, without its header.
+func (tv *textValue) raw() []byte {
+	if tv.typ != TypeBigInteger {
+		return binary.BigEndian.AppendUint64(nil, uint64(tv.n))
+	}
+
+	if tv.b != nil {
+		return tv.b
+	}
+
+	var buf encBuf
+	buf.encodeBigInt(tv.tag, tv.i)
+
+	return buf.Bytes()[lenHeader:]
+}
+
+// jsonValue returns the value in the KMIP JSON encoding.
+func (tv *textValue) jsonValue(r *Registry) (string, error) {
+	switch tv.typ {
+	case TypeBoolean:
+		return strconv.FormatBool(tv.n != 0), nil
+	case TypeEnumeration:
+		return `"` + r.FormatEnum(tv.enumTag, uint32(tv.n)) + `"`, nil
+	case TypeInteger:
+		if enum := r.EnumForTag(tv.enumTag); enum != nil {
+			return `"` + FormatInt(int32(tv.n), enum) + `"`, nil
+		}
+
+		return strconv.Itoa(int(int32(tv.n))), nil
+	case TypeLongInteger:
+		if tv.n <= -maxJSONInt || tv.n >= maxJSONInt {
+			return `"0x` + hex.EncodeToString(tv.raw()) + `"`, nil
+		}
+
+		return strconv.FormatInt(tv.n, 10), nil
+	case TypeBigInteger:
+		if tv.i.IsInt64() && tv.i.CmpAbs(maxJSONBigInt) < 0 {
+			val, err := tv.i.MarshalJSON()
+
+			return string(val), err
+		}
+
+		return `"0x` + hex.EncodeToString(tv.raw()) + `"`, nil
+	case TypeTextString:
+		val, err := json.Marshal(tv.s)
+
+		return string(val), err
+	case TypeByteString:
+		return `"` + hex.EncodeToString(tv.b) + `"`, nil
+	case TypeDateTime, TypeDateTimeExtended:
+		tm := tv.time()
+		if !iso8601Year(tm) {
+			return `"0x` + hex.EncodeToString(tv.raw()) + `"`, nil
+		}
+
+		val, err := tm.MarshalJSON()
+
+		return string(val), err
+	case TypeInterval:
+		return strconv.FormatUint(uint64(uint32(tv.n)), 10), nil
+	default:
+		return "", merry.Errorf("%s: invalid type %s", tv.tag, tv.typ)
+	}
+}
+
+// xmlValue returns the value attribute of the value in the KMIP XML encoding.
+// The Integers of AttributeValues within Structures are formatted like the values
+// of the attribute named by the AttributeName before them.
+func (tv *textValue) xmlValue(r *Registry, attrValue bool) string {
+	switch tv.typ {
+	case TypeInteger:
+		if attrValue {
+			return r.FormatInt(tv.enumTag, int32(tv.n))
+		}
+
+		if enum := r.EnumForTag(tv.enumTag); enum != nil {
+			return strings.ReplaceAll(FormatInt(int32(tv.n), enum), "|", " ")
+		}
+
+		return strconv.Itoa(int(int32(tv.n)))
+	case TypeBoolean:
+		return strconv.FormatBool(tv.n != 0)
+	case TypeLongInteger:
+		return strconv.FormatInt(tv.n, 10)
+	case TypeBigInteger:
+		return hex.EncodeToString(tv.raw())
+	case TypeEnumeration:
+		return r.FormatEnum(tv.enumTag, uint32(tv.n))
+	case TypeTextString:
+		return tv.s
+	case TypeByteString:
+		return hex.EncodeToString(tv.b)
+	case TypeDateTime, TypeDateTimeExtended:
+		if tm := tv.time(); iso8601Year(tm) {
+			return tm.Format(time.RFC3339Nano)
+		}
+
+		return "0x" + hex.EncodeToString(tv.raw())
+	case TypeInterval:
+		return strconv.FormatUint(uint64(uint32(tv.n)), 10)
+	default:
+		return ""
+	}
+}
+
+// time returns the value of a DateTime or DateTimeExtended, like TTLV.ValueDateTime.
+func (tv *textValue) time() time.Time {
+	if tv.typ == TypeDateTimeExtended {
+		return time.UnixMicro(tv.n).UTC()
+	}
+
+	return time.Unix(tv.n, 0).UTC()
+}
+
+// value returns the value like TTLV.Value does.
+func (tv *textValue) value() interface{} {
+	switch tv.typ {
+	case TypeInterval:
+		return time.Duration(tv.n) * time.Second
+	case TypeDateTime:
+		return tv.time()
+	case TypeDateTimeExtended:
+		return DateTimeExtended{Time: tv.time()}
+	case TypeByteString:
+		return tv.b
+	case TypeTextString:
+		return tv.s
+	case TypeBoolean:
+		return tv.n != 0
+	case TypeEnumeration:
+		return EnumValue(tv.n)
+	case TypeBigInteger:
+		return tv.i
+	case TypeLongInteger:
+		return tv.n
+	case TypeInteger:
+		return int32(tv.n)
+	default:
+		return nil
+	}
+}
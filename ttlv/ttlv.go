@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"io"
 	"math/big"
-	"strconv"
 	"strings"
 	"time"
 
@@ -205,7 +204,7 @@ func (t TTLV) ValueDateTime() time.Time {
 	i := t.ValueLongInteger()
 
 	if t.Type() == TypeDateTimeExtended {
-		return time.Unix(0, i*1000).UTC()
+		return time.UnixMicro(i).UTC()
 	}
 
 	return time.Unix(i, 0).UTC()
@@ -215,7 +214,7 @@ func (t TTLV) ValueDateTimeExtended() DateTimeExtended {
 	i := t.ValueLongInteger()
 
 	if t.Type() == TypeDateTimeExtended {
-		return DateTimeExtended{Time: time.Unix(0, i*1000).UTC()}
+		return DateTimeExtended{Time: time.UnixMicro(i).UTC()}
 	}
 
 	return DateTimeExtended{Time: time.Unix(i, 0).UTC()}
@@ -311,8 +310,11 @@ func (t TTLV) ValidHeader() error {
 	return nil
 }
 
+// Next returns the TTLV value following t, or nil if there isn't one.  Only t's header
+// is checked, so iterating over the values of a Structure doesn't validate them again.
+// Returns nil if the header is invalid, or the value is truncated.
 func (t TTLV) Next() TTLV {
-	if t.Valid() != nil {
+	if t.ValidHeader() != nil || len(t) < t.FullLen() {
 		return nil
 	}
 
@@ -324,7 +326,9 @@ func (t TTLV) Next() TTLV {
 	return n
 }
 
-// String renders the TTLV in a human-friendly format using Print().
+// String renders the TTLV in a human-friendly format using Print().  Like Print, it
+// redacts the values of tags marked sensitive in DefaultRegistry, so it is safe to log.
+// Use PrintOptions{Reveal: true}.String to include them.
 func (t TTLV) String() string {
 	var sb strings.Builder
 	_ = Print(&sb, "", "  ", t)
@@ -333,116 +337,102 @@ func (t TTLV) String() string {
 }
 
 func (t TTLV) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return t.marshalXML(e, &DefaultRegistry, false)
+}
+
+// marshalXML encodes t in the KMIP XML encoding, using the names in registry r.  If
+// redact is true, the values of sensitive tags are replaced with a redaction marker.
+func (t TTLV) marshalXML(e *xml.Encoder, r *Registry, redact bool) error {
 	if len(t) == 0 {
 		return nil
 	}
 
-	out := struct {
-		XMLName  xml.Name
-		Tag      string `xml:"tag,omitempty,attr"`
-		Type     string `xml:"type,attr,omitempty"`
-		Value    string `xml:"value,attr,omitempty"`
-		Children []TTLV
-		Inner    []byte `xml:",innerxml"`
-	}{}
+	out := newXMLElement(t.Tag(), t.Type(), r)
 
-	if tagS := t.Tag().String(); strings.HasPrefix(tagS, "0x") {
-		out.XMLName.Local = "TTLV"
-		out.Tag = tagS
-	} else {
-		out.XMLName.Local = tagS
+	if redact && r.IsSensitive(t.Tag()) {
+		out.Type = r.FormatType(t.Type())
+		out.Value = redactionMarker(t.Len())
+
+		return e.Encode(&out)
 	}
 
 	if t.Type() != TypeStructure {
-		out.Type = t.Type().String()
+		tv := ttlvTextValue(t, t.Tag())
+		out.Value = tv.xmlValue(r, false)
+
+		return e.Encode(&out)
 	}
 
-	switch t.Type() {
-	case TypeStructure:
-		se := xml.StartElement{Name: out.XMLName}
-		if out.Type != "" {
-			se.Attr = append(se.Attr, xml.Attr{Name: xml.Name{Local: "type"}, Value: out.Type})
+	if err := e.EncodeToken(out.start()); err != nil {
+		return err
+	}
+
+	var attrTag Tag
+
+	n := t.ValueStructure()
+	for len(n) > 0 {
+		// if the struct contains an attribute name, followed by an
+		// attribute value, use the name to try and map enumeration values
+		// to their string variants
+		if n.Tag() == tagAttributeName {
+			// try to map the attribute name to a tag
+			attrTag, _ = r.ParseTag(kmiputil.NormalizeName(n.ValueTextString()))
 		}
 
-		err := e.EncodeToken(se)
-		if err != nil {
+		if n.Tag() == tagAttributeValue && (n.Type() == TypeEnumeration || n.Type() == TypeInteger) {
+			tv := ttlvTextValue(n, attrTag)
+			c := newXMLElement(n.Tag(), n.Type(), r)
+			c.Value = tv.xmlValue(r, true)
+
+			if err := e.Encode(&c); err != nil {
+				return err
+			}
+		} else if err := e.Encode(registryTTLV{t: n, r: r, redact: redact}); err != nil {
 			return err
 		}
 
-		var attrTag Tag
+		n = n.Next()
+	}
 
-		n := t.ValueStructure()
-		for len(n) > 0 {
-			// if the struct contains an attribute name, followed by an
-			// attribute value, use the name to try and map enumeration values
-			// to their string variants
-			if n.Tag() == tagAttributeName {
-				// try to map the attribute name to a tag
-				attrTag, _ = DefaultRegistry.ParseTag(kmiputil.NormalizeName(n.ValueTextString()))
-			}
+	return e.EncodeToken(xml.EndElement{Name: out.XMLName})
+}
 
-			if n.Tag() == tagAttributeValue && (n.Type() == TypeEnumeration || n.Type() == TypeInteger) {
-				valAttr := xml.Attr{
-					Name: xml.Name{Local: "value"},
-				}
-
-				if n.Type() == TypeEnumeration {
-					valAttr.Value = DefaultRegistry.FormatEnum(attrTag, uint32(n.ValueEnumeration()))
-				} else {
-					valAttr.Value = DefaultRegistry.FormatInt(attrTag, n.ValueInteger())
-				}
-
-				err := e.EncodeToken(xml.StartElement{
-					Name: xml.Name{Local: tagAttributeValue.String()},
-					Attr: []xml.Attr{
-						{
-							Name:  xml.Name{Local: "type"},
-							Value: n.Type().String(),
-						},
-						valAttr,
-					},
-				})
-				if err != nil {
-					return err
-				}
-
-				if err := e.EncodeToken(xml.EndElement{Name: xml.Name{Local: "AttributeValue"}}); err != nil {
-					return err
-				}
-			} else if err := e.Encode(n); err != nil {
-				return err
-			}
+// xmlElement is the element of a value in the KMIP XML encoding.  The values of
+// Structures are written as its children.
+type xmlElement struct {
+	XMLName xml.Name
+	Tag     string `xml:"tag,omitempty,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Value   string `xml:"value,attr,omitempty"`
+}
 
-			n = n.Next()
-		}
+// newXMLElement returns the element of a value with tag and type typ.  Tags without
+// names are written as TTLV elements with a tag attribute.
+func newXMLElement(tag Tag, typ Type, r *Registry) xmlElement {
+	var out xmlElement
 
-		return e.EncodeToken(xml.EndElement{Name: out.XMLName})
+	if tagS := r.FormatTag(tag); strings.HasPrefix(tagS, "0x") {
+		out.XMLName.Local = "TTLV"
+		out.Tag = tagS
+	} else {
+		out.XMLName.Local = tagS
+	}
 
-	case TypeInteger:
-		if enum := DefaultRegistry.EnumForTag(t.Tag()); enum != nil {
-			out.Value = strings.ReplaceAll(FormatInt(t.ValueInteger(), enum), "|", " ")
-		} else {
-			out.Value = strconv.Itoa(int(t.ValueInteger()))
-		}
-	case TypeBoolean:
-		out.Value = strconv.FormatBool(t.ValueBoolean())
-	case TypeLongInteger:
-		out.Value = strconv.FormatInt(t.ValueLongInteger(), 10)
-	case TypeBigInteger:
-		out.Value = hex.EncodeToString(t.ValueRaw())
-	case TypeEnumeration:
-		out.Value = DefaultRegistry.FormatEnum(t.Tag(), uint32(t.ValueEnumeration()))
-	case TypeTextString:
-		out.Value = t.ValueTextString()
-	case TypeByteString:
-		out.Value = hex.EncodeToString(t.ValueByteString())
-	case TypeDateTime, TypeDateTimeExtended:
-		out.Value = t.ValueDateTime().Format(time.RFC3339Nano)
-	case TypeInterval:
-		out.Value = strconv.FormatUint(uint64(t.ValueInterval()/time.Second), 10)
+	if typ != TypeStructure {
+		out.Type = r.FormatType(typ)
 	}
 
-	return e.Encode(&out)
+	return out
+}
+
+// start returns the start element of a Structure.
+func (x *xmlElement) start() xml.StartElement {
+	se := xml.StartElement{Name: x.XMLName}
+	if x.Tag != "" {
+		se.Attr = append(se.Attr, xml.Attr{Name: xml.Name{Local: "tag"}, Value: x.Tag})
+	}
+
+	return se
 }
 
 type xmltval struct {
@@ -457,12 +447,12 @@ func syntaxError(tag Tag, tp Type, err error) error {
 	return merry.Prependf(err, "%s: invalid %s", tag.String(), tp.String())
 }
 
-func unmarshalXMLTval(buf *encBuf, tval *xmltval, attrTag Tag) error {
+func unmarshalXMLTval(buf *encBuf, tval *xmltval, r *Registry, attrTag Tag) error {
 	if tval.Tag == "" {
 		tval.Tag = tval.XMLName.Local
 	}
 
-	tag, err := DefaultRegistry.ParseTag(tval.Tag)
+	tag, err := r.ParseTag(tval.Tag)
 	if err != nil {
 		return merry.Prepend(err, "invalid tag")
 	}
@@ -471,134 +461,53 @@ func unmarshalXMLTval(buf *encBuf, tval *xmltval, attrTag Tag) error {
 	if tval.Type == "" {
 		tp = TypeStructure
 	} else {
-		tp, err = DefaultRegistry.ParseType(tval.Type)
+		tp, err = r.ParseType(tval.Type)
 		if err != nil {
 			return merry.Prepend(err, "invalid type")
 		}
 	}
 
-	syntaxError := func(err error) error {
-		return syntaxError(tag, tp, merry.HereSkipping(err, 1))
-	}
-
-	switch tp {
-	case TypeBoolean:
-		b, err := strconv.ParseBool(tval.Value)
-		if err != nil {
-			return syntaxError(merry.Prepend(err, "must be 0, 1, true, or false"))
-		}
-
-		buf.encodeBool(tag, b)
-	case TypeTextString:
-		buf.encodeTextString(tag, tval.Value)
-	case TypeByteString:
-		// TODO: consider allowing this, just strip off the 0x prefix
-		// it's not to spec, but its a simple accommodation
-		if strings.HasPrefix(tval.Value, "0x") {
-			return syntaxError(errors.New("should not have 0x prefix"))
-		}
-
-		b, err := hex.DecodeString(tval.Value)
-		if err != nil {
-			return syntaxError(err)
-		}
-
-		buf.encodeByteString(tag, b)
-	case TypeInterval:
-		u, err := strconv.ParseUint(tval.Value, 10, 64)
-		if err != nil {
-			return syntaxError(merry.Prepend(err, "must be a number"))
-		}
-
-		buf.encodeInterval(tag, time.Duration(u)*time.Second)
-	case TypeDateTime, TypeDateTimeExtended:
-		d, err := time.Parse(time.RFC3339Nano, tval.Value)
-		if err != nil {
-			return syntaxError(merry.Prepend(err, "must be ISO8601 format"))
-		}
-
-		if tp == TypeDateTime {
-			buf.encodeDateTime(tag, d)
-		} else {
-			buf.encodeDateTimeExtended(tag, d)
-		}
-	case TypeInteger:
-		enumTag := tag
-		if tag == tagAttributeValue && attrTag != TagNone {
-			enumTag = attrTag
-		}
-
-		i, err := DefaultRegistry.ParseInt(enumTag, strings.ReplaceAll(tval.Value, " ", "|"))
+	if tp != TypeStructure {
+		tv, err := parseXMLValue(tag, tp, tval.Value, r, enumTagFor(tag, attrTag))
 		if err != nil {
-			return syntaxError(err)
-		}
-
-		buf.encodeInt(tag, i)
-	case TypeLongInteger:
-		i, err := strconv.ParseInt(tval.Value, 10, 64)
-		if err != nil {
-			return syntaxError(merry.Prepend(err, "must be number"))
+			return err
 		}
 
-		buf.encodeLongInt(tag, i)
-	case TypeBigInteger:
-		// TODO: consider allowing this, just strip off the 0x prefix
-		// it's not to spec, but its a simple accommodation
-		if strings.HasPrefix(tval.Value, "0x") {
-			return syntaxError(merry.New("should not have 0x prefix"))
-		}
+		buf.encodeTextValue(tv)
 
-		b, err := hex.DecodeString(tval.Value)
-		if err != nil {
-			return syntaxError(err)
-		}
+		return nil
+	}
 
-		if len(b)%8 != 0 {
-			return syntaxError(errors.New("must be multiple of 8 bytes"))
-		}
+	i := buf.begin(tag, TypeStructure)
+	attrTag = TagNone
 
-		n := &big.Int{}
-		unmarshalBigInt(n, b)
-		buf.encodeBigInt(tag, n)
-	case TypeEnumeration:
-		enumTag := tag
-		if tag == tagAttributeValue && attrTag != TagNone {
-			enumTag = attrTag
-		}
+	for _, c := range tval.Children {
+		offset := buf.Len()
 
-		e, err := DefaultRegistry.ParseEnum(enumTag, tval.Value)
+		err := unmarshalXMLTval(buf, c, r, attrTag)
 		if err != nil {
-			return syntaxError(err)
+			return err
 		}
-
-		buf.encodeEnum(tag, e)
-	case TypeStructure:
-		i := buf.begin(tag, TypeStructure)
-		var attrTag Tag
-
-		for _, c := range tval.Children {
-			offset := buf.Len()
-
-			err := unmarshalXMLTval(buf, c, attrTag)
-			if err != nil {
-				return err
-			}
-			// check whether the TTLV we just unmarshaled is an AttributeName
-			ttlv := TTLV(buf.Bytes()[offset:])
-			if ttlv.Tag() == tagAttributeName {
-				// try to parse the value as a tag name, which may be used later
-				// when unmarshaling the AttributeValue
-				attrTag, _ = DefaultRegistry.ParseTag(kmiputil.NormalizeName(ttlv.ValueTextString()))
-			}
+		// check whether the TTLV we just unmarshaled is an AttributeName
+		ttlv := TTLV(buf.Bytes()[offset:])
+		if ttlv.Tag() == tagAttributeName {
+			// try to parse the value as a tag name, which may be used later
+			// when unmarshaling the AttributeValue
+			attrTag, _ = r.ParseTag(kmiputil.NormalizeName(ttlv.ValueTextString()))
 		}
-
-		buf.end(i)
 	}
 
+	buf.end(i)
+
 	return nil
 }
 
 func (t *TTLV) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return t.unmarshalXML(d, start, &DefaultRegistry)
+}
+
+// unmarshalXML decodes the KMIP XML encoding of a value, using the names in registry r.
+func (t *TTLV) unmarshalXML(d *xml.Decoder, start xml.StartElement, r *Registry) error {
 	var out xmltval
 
 	err := d.DecodeElement(&out, &start)
@@ -608,7 +517,7 @@ func (t *TTLV) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 
 	var buf encBuf
 
-	err = unmarshalXMLTval(&buf, &out, TagNone)
+	err = unmarshalXMLTval(&buf, &out, r, TagNone)
 	if err != nil {
 		return err
 	}
@@ -624,10 +533,12 @@ var (
 )
 
 func (t *TTLV) UnmarshalJSON(b []byte) error {
-	return t.unmarshalJSON(b, TagNone)
+	return t.unmarshalJSON(b, &DefaultRegistry, TagNone)
 }
 
-func (t *TTLV) unmarshalJSON(b []byte, attrTag Tag) error {
+// unmarshalJSON decodes the KMIP JSON encoding of a value, using the names in registry r.
+// attrTag is the tag named by the preceding AttributeName, if any.
+func (t *TTLV) unmarshalJSON(b []byte, r *Registry, attrTag Tag) error {
 	if len(b) == 0 {
 		return nil
 	}
@@ -645,7 +556,7 @@ func (t *TTLV) unmarshalJSON(b []byte, attrTag Tag) error {
 		return err
 	}
 
-	tag, err := DefaultRegistry.ParseTag(ttl.Tag)
+	tag, err := r.ParseTag(ttl.Tag)
 	if err != nil {
 		return merry.Prepend(err, "invalid tag")
 	}
@@ -656,7 +567,7 @@ func (t *TTLV) unmarshalJSON(b []byte, attrTag Tag) error {
 	if ttl.Type == "" {
 		tp = TypeStructure
 	} else {
-		tp, err = DefaultRegistry.ParseType(ttl.Type)
+		tp, err = r.ParseType(ttl.Type)
 		if err != nil {
 			return merry.Prepend(err, "invalid type")
 		}
@@ -668,228 +579,64 @@ func (t *TTLV) unmarshalJSON(b []byte, attrTag Tag) error {
 		}
 	}
 
-	syntaxError := func(err error) error {
-		return syntaxError(tag, tp, merry.HereSkipping(err, 1))
-	}
-
-	// performance note: for some types, like int, long int, and interval,
-	// we are essentially decoding from binary into a go native type
-	// then re-encoding to binary.  I benchmarked skipping this step
-	// and transferring the bytes directly from the decoded hex strings
-	// to the binary TTLV.  It turned out not be faster, and added an
-	// additional set of paths to test.  Wasn't worth it.
-
 	enc := encBuf{}
 
-	switch tp {
-	case TypeBoolean:
-		switch tv := v.(type) {
-		default:
-			return syntaxError(errors.New("must be boolean or hex string"))
-		case bool:
-			enc.encodeBool(tag, tv)
-		case string:
-			switch tv {
-			default:
-				return syntaxError(errors.New("hex string for Boolean value must be either 0x0000000000000001 (true) or 0x0000000000000000 (false)"))
-			case "0x0000000000000001":
-				enc.encodeBool(tag, true)
-			case "0x0000000000000000":
-				enc.encodeBool(tag, false)
-			}
-		}
-	case TypeTextString:
-		switch tv := v.(type) {
-		default:
-			return syntaxError(errors.New("must be string"))
-		case string:
-			enc.encodeTextString(tag, tv)
-		}
-	case TypeByteString:
-		switch tv := v.(type) {
-		default:
-			return syntaxError(errors.New("must be hex string"))
-		case string:
-			// TODO: consider allowing this, just strip off the 0x prefix
-			// it's not to spec, but its a simple accommodation
-			if strings.HasPrefix(tv, "0x") {
-				return syntaxError(errors.New("should not have 0x prefix"))
-			}
-
-			b, err := hex.DecodeString(tv)
-			if err != nil {
-				return syntaxError(err)
-			}
-
-			enc.encodeByteString(tag, b)
-		}
-	case TypeInterval:
-		switch tv := v.(type) {
-		default:
-			return syntaxError(errors.New("must be number or hex string"))
-		case string:
-			b, err := kmiputil.ParseHexValue(tv, 4)
-			if err != nil {
-				return syntaxError(err)
-			}
-
-			if b == nil {
-				return syntaxError(errors.New("hex value must start with 0x"))
-			}
-
-			enc.encodeInterval(tag, time.Duration(kmiputil.DecodeUint32(b))*time.Second)
-		case float64:
-			enc.encodeInterval(tag, time.Duration(tv)*time.Second)
-		}
-	case TypeDateTime, TypeDateTimeExtended:
-		switch tv := v.(type) {
-		default:
-			return syntaxError(errors.New("must be string"))
-		case string:
-			var tm time.Time
-
-			b, err := kmiputil.ParseHexValue(tv, 8)
-			if err != nil {
-				return syntaxError(err)
-			}
-
-			if b != nil {
-				u := kmiputil.DecodeUint64(b)
-				if tp == TypeDateTime {
-					tm = time.Unix(int64(u), 0)
-				} else {
-					tm = tm.Add(time.Duration(u) * time.Microsecond)
-				}
-			} else {
-				var err error
-				tm, err = time.Parse(time.RFC3339Nano, tv)
-				if err != nil {
-					return syntaxError(merry.Prepend(err, "must be ISO8601 format"))
-				}
-			}
-
-			if tp == TypeDateTime {
-				enc.encodeDateTime(tag, tm)
-			} else {
-				enc.encodeDateTimeExtended(tag, tm)
-			}
+	if tp != TypeStructure {
+		tv, err := parseJSONValue(tag, tp, v, r, enumTagFor(tag, attrTag))
+		if err != nil {
+			return err
 		}
-	case TypeInteger:
-		switch tv := v.(type) {
-		default:
-			return syntaxError(errors.New("must be number, hex string, or mask value name"))
-		case string:
-			enumTag := tag
-			if tag == tagAttributeValue && attrTag != TagNone {
-				enumTag = attrTag
-			}
 
-			i, err := DefaultRegistry.ParseInt(enumTag, tv)
-			if err != nil {
-				return syntaxError(err)
-			}
+		enc.encodeTextValue(tv)
+		*t = enc.Bytes()
 
-			enc.encodeInt(tag, i)
-		case float64:
-			enc.encodeInt(tag, int32(tv))
-		}
-	case TypeLongInteger:
-		switch tv := v.(type) {
-		default:
-			return syntaxError(errors.New("must be number or hex string"))
-		case string:
-			b, err := kmiputil.ParseHexValue(tv, 8)
-			if err != nil {
-				return syntaxError(err)
-			}
-
-			if b == nil {
-				return syntaxError(errors.New("hex value must start with 0x"))
-			}
-
-			enc.encodeLongInt(tag, int64(kmiputil.DecodeUint64(b)))
-		case float64:
-			enc.encodeLongInt(tag, int64(tv))
-		}
-	case TypeBigInteger:
-		switch tv := v.(type) {
-		default:
-			return syntaxError(errors.New("must be number or hex string"))
-		case string:
-			if !strings.HasPrefix(tv, "0x") {
-				return syntaxError(errors.New("hex value must start with 0x"))
-			}
+		return nil
+	}
 
-			b, err := hex.DecodeString(tv[2:])
-			if err != nil {
-				return syntaxError(err)
-			}
+	syntaxError := func(err error) error {
+		return syntaxError(tag, tp, merry.HereSkipping(err, 1))
+	}
 
-			if len(b)%8 != 0 {
-				return syntaxError(errors.New("must be multiple of 8 bytes (16 hex characters)"))
-			}
+	// unmarshal each sub value
+	var children []json.RawMessage
 
-			i := &big.Int{}
-			unmarshalBigInt(i, unpadBigInt(b))
-			enc.encodeBigInt(tag, i)
-		case float64:
-			enc.encodeBigInt(tag, big.NewInt(int64(tv)))
-		}
-	case TypeEnumeration:
-		switch tv := v.(type) {
-		default:
-			return syntaxError(errors.New("must be number or string"))
-		case string:
-			enumTag := tag
-			if tag == tagAttributeValue && attrTag != TagNone {
-				enumTag = attrTag
-			}
+	err = json.Unmarshal(ttl.Value, &children)
+	if err != nil {
+		return syntaxError(err)
+	}
 
-			u, err := DefaultRegistry.ParseEnum(enumTag, tv)
-			if err != nil {
-				return syntaxError(err)
-			}
+	var scratch TTLV
 
-			enc.encodeEnum(tag, u)
-		case float64:
-			enc.encodeEnum(tag, uint32(tv))
-		}
-	case TypeStructure:
-		// unmarshal each sub value
-		var children []json.RawMessage
+	s := enc.begin(tag, TypeStructure)
 
-		err := json.Unmarshal(ttl.Value, &children)
+	attrTag = TagNone
+	for _, c := range children {
+		err := (&scratch).unmarshalJSON(c, r, attrTag)
 		if err != nil {
 			return syntaxError(err)
 		}
 
-		var scratch TTLV
-
-		s := enc.begin(tag, TypeStructure)
-
-		var attrTag Tag
-		for _, c := range children {
-			err := (&scratch).unmarshalJSON(c, attrTag)
-			if err != nil {
-				return syntaxError(err)
-			}
-
-			if tagAttributeName == scratch.Tag() {
-				attrTag, _ = DefaultRegistry.ParseTag(kmiputil.NormalizeName(scratch.ValueTextString()))
-			}
-
-			_, _ = enc.Write(scratch)
+		if tagAttributeName == scratch.Tag() {
+			attrTag, _ = r.ParseTag(kmiputil.NormalizeName(scratch.ValueTextString()))
 		}
 
-		enc.end(s)
+		_, _ = enc.Write(scratch)
 	}
 
+	enc.end(s)
+
 	*t = enc.Bytes()
 
 	return nil
 }
 
 func (t TTLV) MarshalJSON() ([]byte, error) {
+	return t.marshalJSON(&DefaultRegistry, false)
+}
+
+// marshalJSON encodes t in the KMIP JSON encoding, using the names in registry r.  If
+// redact is true, the values of sensitive tags are replaced with a redaction marker.
+func (t TTLV) marshalJSON(r *Registry, redact bool) ([]byte, error) {
 	if len(t) == 0 {
 		return []byte("null"), nil
 	}
@@ -901,126 +648,80 @@ func (t TTLV) MarshalJSON() ([]byte, error) {
 	var sb strings.Builder
 
 	sb.WriteString(`{"tag":"`)
-	sb.WriteString(t.Tag().String())
+	sb.WriteString(r.FormatTag(t.Tag()))
 
 	if t.Type() != TypeStructure {
 		sb.WriteString(`","type":"`)
-		sb.WriteString(t.Type().String())
+		sb.WriteString(r.FormatType(t.Type()))
 	}
 
 	sb.WriteString(`","value":`)
 
-	switch t.Type() {
-	case TypeBoolean:
-		if t.ValueBoolean() {
-			sb.WriteString("true")
-		} else {
-			sb.WriteString("false")
-		}
-	case TypeEnumeration:
-		sb.WriteString(`"`)
-		sb.WriteString(DefaultRegistry.FormatEnum(t.Tag(), uint32(t.ValueEnumeration())))
+	if redact && r.IsSensitive(t.Tag()) {
 		sb.WriteString(`"`)
-	case TypeInteger:
-		if enum := DefaultRegistry.EnumForTag(t.Tag()); enum != nil {
-			sb.WriteString(`"`)
-			sb.WriteString(FormatInt(t.ValueInteger(), enum))
-			sb.WriteString(`"`)
-		} else {
-			sb.WriteString(strconv.Itoa(int(t.ValueInteger())))
-		}
-	case TypeLongInteger:
-		v := t.ValueLongInteger()
-		if v <= -maxJSONInt || v >= maxJSONInt {
-			sb.WriteString(`"0x`)
-			sb.WriteString(hex.EncodeToString(t.ValueRaw()))
-			sb.WriteString(`"`)
-		} else {
-			sb.WriteString(strconv.FormatInt(v, 10))
-		}
-	case TypeBigInteger:
-		v := t.ValueBigInteger()
-		if v.IsInt64() && v.CmpAbs(maxJSONBigInt) < 0 {
-			val, err := v.MarshalJSON()
-			if err != nil {
-				return nil, err
-			}
+		sb.WriteString(redactionMarker(t.Len()))
+		sb.WriteString(`"}`)
 
-			sb.Write(val)
-		} else {
-			sb.WriteString(`"0x`)
-			sb.WriteString(hex.EncodeToString(t.ValueRaw()))
-			sb.WriteString(`"`)
-		}
-	case TypeTextString:
-		val, err := json.Marshal(t.ValueTextString())
+		return []byte(sb.String()), nil
+	}
+
+	if t.Type() != TypeStructure {
+		tv := ttlvTextValue(t, t.Tag())
+
+		v, err := tv.jsonValue(r)
 		if err != nil {
 			return nil, err
 		}
 
-		sb.Write(val)
-	case TypeByteString:
-		sb.WriteString(`"`)
-		sb.WriteString(hex.EncodeToString(t.ValueRaw()))
-		sb.WriteString(`"`)
-	case TypeStructure:
-		sb.WriteString("[")
-
-		c := t.ValueStructure()
-		var attrTag Tag
-
-		for len(c) > 0 {
-			// if the struct contains an attribute name, followed by an
-			// attribute value, use the name to try and map enumeration values
-			// to their string variants
-			if c.Tag() == tagAttributeName {
-				// try to map the attribute name to a tag
-				attrTag, _ = DefaultRegistry.ParseTag(kmiputil.NormalizeName(c.ValueTextString()))
-			}
+		sb.WriteString(v)
+		sb.WriteString(`}`)
 
-			switch {
-			case c.Tag() == tagAttributeValue && c.Type() == TypeEnumeration:
-				sb.WriteString(`{"tag":"AttributeValue","type":"Enumeration","value":"`)
-				sb.WriteString(DefaultRegistry.FormatEnum(attrTag, uint32(c.ValueEnumeration())))
-				sb.WriteString(`"}`)
-			case c.Tag() == tagAttributeValue && c.Type() == TypeInteger:
-				sb.WriteString(`{"tag":"AttributeValue","type":"Integer","value":`)
-
-				if enum := DefaultRegistry.EnumForTag(attrTag); enum != nil {
-					sb.WriteString(`"`)
-					sb.WriteString(FormatInt(c.ValueInteger(), enum))
-					sb.WriteString(`"`)
-				} else {
-					sb.WriteString(strconv.Itoa(int(c.ValueInteger())))
-				}
-
-				sb.WriteString(`}`)
-			default:
-				v, err := c.MarshalJSON()
-				if err != nil {
-					return nil, err
-				}
-
-				sb.Write(v)
+		return []byte(sb.String()), nil
+	}
+
+	sb.WriteString("[")
+
+	c := t.ValueStructure()
+	var attrTag Tag
+
+	for len(c) > 0 {
+		// if the struct contains an attribute name, followed by an
+		// attribute value, use the name to try and map enumeration values
+		// to their string variants
+		if c.Tag() == tagAttributeName {
+			// try to map the attribute name to a tag
+			attrTag, _ = r.ParseTag(kmiputil.NormalizeName(c.ValueTextString()))
+		}
+
+		switch {
+		case c.Tag() == tagAttributeValue && (c.Type() == TypeEnumeration || c.Type() == TypeInteger):
+			tv := ttlvTextValue(c, attrTag)
+
+			v, err := tv.jsonValue(r)
+			if err != nil {
+				return nil, err
 			}
 
-			c = c.Next()
-			if len(c) > 0 {
-				sb.WriteString(",")
+			sb.WriteString(`{"tag":"AttributeValue","type":"`)
+			sb.WriteString(r.FormatType(c.Type()))
+			sb.WriteString(`","value":`)
+			sb.WriteString(v)
+			sb.WriteString(`}`)
+		default:
+			v, err := c.marshalJSON(r, redact)
+			if err != nil {
+				return nil, err
 			}
-		}
-		sb.WriteString("]")
-	case TypeDateTime, TypeDateTimeExtended:
-		val, err := t.ValueDateTime().MarshalJSON()
-		if err != nil {
-			return nil, err
+
+			sb.Write(v)
 		}
 
-		sb.Write(val)
-	case TypeInterval:
-		sb.WriteString(strconv.FormatUint(uint64(binary.BigEndian.Uint32(t.ValueRaw())), 10))
+		c = c.Next()
+		if len(c) > 0 {
+			sb.WriteString(",")
+		}
 	}
-
+	sb.WriteString("]")
 	sb.WriteString(`}`)
 
 	return []byte(sb.String()), nil
@@ -1047,21 +748,64 @@ func (t *TTLV) UnmarshalTTLV(_ *Decoder, ttlv TTLV) error {
 	return nil
 }
 
-// Print pretty prints the TTLV value in a human-readable format.  This
-// format cannot be parsed back into TTLV.
+// Print pretty prints the TTLV value in a human-readable format.
 //
 // Print is safe to call on any TTLV value, even one which is valid,
 // not correctly encoded, or not actually TTLV bytes.  Print will
 // try and print as much of the value as it can decode, and return
 // a parsing error.
+//
+// Print redacts by default: the values of tags marked sensitive in DefaultRegistry,
+// like key material and passwords, are replaced with a marker like "[redacted 32 bytes]".
+// ParseText can't parse the marker, so to print values which must be parsed back into
+// TTLV, reveal them with PrintOptions{Reveal: true}.Print.
 func Print(w io.Writer, prefix, indent string, t TTLV) error {
+	return DefaultRegistry.Print(w, prefix, indent, t)
+}
+
+// Print is like the package level Print function, but formats tags, types, and enum
+// values with the names registered in r.  The values of tags marked sensitive in r are
+// redacted.
+func (r *Registry) Print(w io.Writer, prefix, indent string, t TTLV) error {
+	return PrintOptions{Registry: r}.Print(w, prefix, indent, t)
+}
+
+// PrintOptions configure a single call to Print, PrintPrettyHex, or String, without
+// changing the registry.  Set Reveal to print values which must round trip, like the
+// input of ParseText:
+//
+//	ttlv.PrintOptions{Reveal: true}.Print(os.Stdout, "", "  ", t)
+type PrintOptions struct {
+	// Registry is used to format tags, types, and enum values, and to find the
+	// sensitive tags.  Defaults to DefaultRegistry.
+	Registry *Registry
+	// Reveal prints the values of sensitive tags, instead of redacting them.
+	Reveal bool
+}
+
+// redact returns true if the value of the tag should be redacted.
+func (o PrintOptions) redact(r *Registry, tag Tag) bool {
+	return !o.Reveal && r.IsSensitive(tag)
+}
+
+// String renders t like TTLV.String, with the options.
+func (o PrintOptions) String(t TTLV) string {
+	var sb strings.Builder
+	_ = o.Print(&sb, "", "  ", t)
+
+	return sb.String()
+}
+
+// Print is like the package level Print function, with the options.
+func (o PrintOptions) Print(w io.Writer, prefix, indent string, t TTLV) error {
+	r := registryOrDefault(o.Registry)
 	currIndent := prefix
 
 	tag := t.Tag()
 	typ := t.Type()
 	l := t.Len()
 
-	if _, err := fmt.Fprintf(w, "%s%v (%s/%d):", currIndent, tag, typ.String(), l); err != nil {
+	if _, err := fmt.Fprintf(w, "%s%s (%s/%d):", currIndent, r.FormatTag(tag), r.FormatType(typ), l); err != nil {
 		return err
 	}
 
@@ -1085,6 +829,11 @@ func Print(w io.Writer, prefix, indent string, t TTLV) error {
 		return verr
 	}
 
+	if o.redact(r, tag) {
+		_, err := fmt.Fprint(w, " ", redactionMarker(l))
+		return err
+	}
+
 	switch typ {
 	case TypeByteString:
 		if _, err := fmt.Fprintf(w, " %#x", t.ValueByteString()); err != nil {
@@ -1099,7 +848,7 @@ func Print(w io.Writer, prefix, indent string, t TTLV) error {
 				return err
 			}
 
-			if err := Print(w, currIndent, indent, s); err != nil {
+			if err := o.Print(w, currIndent, indent, s); err != nil {
 				// an error means we've hit invalid bytes in the stream
 				// there are no markers to pick back up again, so we have to give up
 				return err
@@ -1108,11 +857,11 @@ func Print(w io.Writer, prefix, indent string, t TTLV) error {
 			s = s.Next()
 		}
 	case TypeEnumeration:
-		if _, err := fmt.Fprint(w, " ", DefaultRegistry.FormatEnum(tag, uint32(t.ValueEnumeration()))); err != nil {
+		if _, err := fmt.Fprint(w, " ", r.FormatEnum(tag, uint32(t.ValueEnumeration()))); err != nil {
 			return err
 		}
 	case TypeInteger:
-		if enum := DefaultRegistry.EnumForTag(tag); enum != nil {
+		if enum := r.EnumForTag(tag); enum != nil {
 			if _, err := fmt.Fprint(w, " ", FormatInt(t.ValueInteger(), enum)); err != nil {
 				return err
 			}
@@ -1133,7 +882,22 @@ func Print(w io.Writer, prefix, indent string, t TTLV) error {
 // PrintPrettyHex pretty prints the TTLV value as hex values, with spacers between
 // the segments of the TTLV.  Like Print, this is safe to call even on invalid TTLV
 // values.  An error will only be returned if there is a problem with the writer.
+//
+// Each hex digit of the values of tags marked sensitive in DefaultRegistry is
+// replaced with "*".
 func PrintPrettyHex(w io.Writer, prefix, indent string, t TTLV) error {
+	return DefaultRegistry.PrintPrettyHex(w, prefix, indent, t)
+}
+
+// PrintPrettyHex is like the package level PrintPrettyHex function, but masks the values
+// of tags marked sensitive in r.
+func (r *Registry) PrintPrettyHex(w io.Writer, prefix, indent string, t TTLV) error {
+	return PrintOptions{Registry: r}.PrintPrettyHex(w, prefix, indent, t)
+}
+
+// PrintPrettyHex is like the package level PrintPrettyHex function, with the options.
+func (o PrintOptions) PrintPrettyHex(w io.Writer, prefix, indent string, t TTLV) error {
+	r := registryOrDefault(o.Registry)
 	currIndent := prefix
 	b := []byte(t)
 
@@ -1151,6 +915,13 @@ func PrintPrettyHex(w io.Writer, prefix, indent string, t TTLV) error {
 		return err
 	}
 
+	if o.redact(r, t.Tag()) {
+		// mask each hex digit of the value, including any nested values
+		_, err := fmt.Fprintf(w, "%s%x | %x | %x | %s", currIndent, b[0:3], b[3:4], b[4:8], strings.Repeat("*", 2*(t.FullLen()-lenHeader)))
+
+		return err
+	}
+
 	if t.Type() != TypeStructure {
 		// print the entire value
 		_, err := fmt.Fprintf(w, "%s%x | %x | %x | %x", currIndent, b[0:3], b[3:4], b[4:8], b[lenHeader:t.FullLen()])
@@ -1172,7 +943,7 @@ func PrintPrettyHex(w io.Writer, prefix, indent string, t TTLV) error {
 			return werr
 		}
 
-		if err := PrintPrettyHex(w, currIndent, indent, s); err != nil {
+		if err := o.PrintPrettyHex(w, currIndent, indent, s); err != nil {
 			// an error means we've hit invalid bytes in the stream
 			// there are no markers to pick back up again, so we have to give up
 			return err
@@ -1226,6 +997,12 @@ func unmarshalBigInt(n *big.Int, data []byte) {
 	}
 }
 
+// iso8601Year returns true if the year of t can be written in ISO8601 format.  Other
+// times are written in hex.
+func iso8601Year(t time.Time) bool {
+	return t.Year() >= 0 && t.Year() <= 9999
+}
+
 // Hex2bytes converts hex string to bytes.  Any non-hex characters in the string are stripped first.
 // panics on error
 func Hex2bytes(s string) []byte {
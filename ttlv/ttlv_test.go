@@ -349,6 +349,21 @@ func TestTTLV_UnmarshalJSON_errors(t *testing.T) {
 			input: `{"tag":"BatchCount","type":"Interval","value":"0xA0A0A0A0A0"}`,
 			msg:   "BatchCount: invalid Interval: invalid hex string: must be 4 bytes",
 		},
+		{
+			name:  "intervalnegative",
+			input: `{"tag":"BatchCount","type":"Interval","value":-1}`,
+			msg:   "BatchCount: invalid Interval: must be a whole number of seconds between 0 and 4294967295",
+		},
+		{
+			name:  "intervaltoolarge",
+			input: `{"tag":"BatchCount","type":"Interval","value":4294967296}`,
+			msg:   "BatchCount: invalid Interval: must be a whole number of seconds between 0 and 4294967295",
+		},
+		{
+			name:  "intervalfraction",
+			input: `{"tag":"BatchCount","type":"Interval","value":1.5}`,
+			msg:   "BatchCount: invalid Interval: must be a whole number of seconds between 0 and 4294967295",
+		},
 		{
 			name:  "datetimeinvalidtype",
 			input: `{"tag":"BatchCount","type":"DateTime","value":true}`,
@@ -533,6 +548,13 @@ func TestTTLV_UnmarshalJSON(t *testing.T) {
 			},
 			exp: Value{Tag: TagBatchCount, Value: time.Date(2008, 0o3, 14, 11, 56, 40, 0, time.FixedZone("UTC", 0))},
 		},
+		{
+			name: "datetimeextendedhex",
+			inputs: []string{
+				`{"tag":"BatchCount","type":"DateTimeExtended","value":"0x000455FE3B3F7A01"}`,
+			},
+			exp: Value{Tag: TagBatchCount, Value: DateTimeExtended{Time: time.UnixMicro(0x000455FE3B3F7A01)}},
+		},
 		{
 			name: "integer",
 			inputs: []string{
@@ -178,9 +178,26 @@ func NewStruct(tag Tag, vals ...Value) Value {
 }
 
 type Encoder struct {
+	// View, if set, restricts the encoder to the tags and enum values defined in
+	// a version of the KMIP spec.  Flush returns an error with cause ErrNotInVersion
+	// rather than writing values which aren't defined.
+	View *View
+
+	// Redact, if set, makes JSON and XML encoders replace the values of tags marked
+	// sensitive in the Registry with a redaction marker.  The output can't be decoded
+	// back into the original values, so this is intended for logging.
+	Redact bool
+
+	// Registry, if set, is used instead of DefaultRegistry to infer tags from the names
+	// of types and fields, to encode enum and bitmask values, and to format the JSON and
+	// XML encodings.
+	Registry *Registry
+
 	encodeDepth int
 	w           io.Writer
 	encBuf      encBuf
+	// text writes the values of JSON and XML encoders
+	text *textEncoder
 
 	// these fields store where the encoder is when marshaling a nested struct.  its
 	// used to construct error messages.
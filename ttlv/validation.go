@@ -0,0 +1,180 @@
+package ttlv
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ansel1/merry"
+)
+
+var (
+	ErrMissingValue    = errors.New("required value is missing")
+	ErrValueOutOfRange = errors.New("value is out of range")
+	ErrMultipleValues  = errors.New("more than one value set in oneof group")
+)
+
+// ValidationError is returned by the Encoder and Decoder when a struct field violates
+// its "required", "min", "max", or "oneof" struct tag flags.  The cause of the error
+// will be ErrMissingValue, ErrValueOutOfRange, or ErrMultipleValues.
+type ValidationError struct {
+	// Struct is the type of the struct containing the field
+	Struct reflect.Type
+	// Field is the name of the invalid field.  For oneof groups, it lists
+	// all the fields in the group.
+	Field string
+	Tag   Tag
+}
+
+func (e *ValidationError) Error() string {
+	msg := "kmip: invalid value"
+	if e.Tag != TagNone {
+		msg += " for " + e.Tag.String()
+	}
+
+	return msg + " in struct field " + e.Struct.Name() + "." + e.Field
+}
+
+func validationError(typ reflect.Type, field string, tag Tag, cause error) merry.Error {
+	e := &ValidationError{
+		Struct: typ,
+		Field:  field,
+		Tag:    tag,
+	}
+
+	return merry.WrapSkipping(e, 1).WithCause(cause)
+}
+
+// validateForMarshal checks the validation flags of the fields of struct v.
+// For the "required" and "oneof" flags, fields are present if they aren't empty
+// and will be encoded.  getFieldInfo doesn't allow those flags on fields whose
+// zero value is valid, so an empty field is a missing one.  The "min" and "max"
+// flags are checked on every field which will be encoded, even if it is empty, so
+// Marshal never writes a value which Unmarshal would reject.
+func validateForMarshal(v reflect.Value, ti typeInfo) error {
+	present := make([]bool, len(ti.valueFields))
+	encoded := make([]bool, len(ti.valueFields))
+
+	for i := range ti.valueFields {
+		fv := fieldByIndex(v, ti.valueFields[i].index)
+		encoded[i] = fv.IsValid() && ti.valueFields[i].encodes(fv)
+		present[i] = encoded[i] && !isEmptyValue(fv)
+	}
+
+	return validateFields(v, ti, present, encoded)
+}
+
+// encodes returns true if encoding the field's value v writes at least one value.
+func (fi *fieldInfo) encodes(v reflect.Value) bool {
+	v = indirect(v)
+	if !v.IsValid() {
+		return false
+	}
+
+	if fi.flags.omitEmpty() && isEmptyValue(v) {
+		return false
+	}
+
+	switch {
+	case v.Kind() == reflect.Slice && v.Type().Elem() != byteType, v.Kind() == reflect.Array:
+		return v.Len() > 0
+	}
+
+	return true
+}
+
+// validateFields checks the validation flags of the fields of struct v.  present
+// indicates which fields have values, and encoded which fields must be in range.
+func validateFields(v reflect.Value, ti typeInfo, present, encoded []bool) error {
+	var groups []string
+
+	for i := range ti.valueFields {
+		fi := &ti.valueFields[i]
+
+		if fi.flags&fRequired != 0 && !present[i] {
+			return validationError(ti.typ, fi.name, fi.tag, ErrMissingValue)
+		}
+
+		if fi.flags&(fMin|fMax) != 0 && encoded[i] && !fi.inRange(fieldByIndex(v, fi.index)) {
+			return validationError(ti.typ, fi.name, fi.tag, ErrValueOutOfRange)
+		}
+
+		if fi.flags&fOneOf != 0 && !slices.Contains(groups, fi.oneOf) {
+			groups = append(groups, fi.oneOf)
+		}
+	}
+
+	// exactly one field in each oneof group must be present
+	for _, group := range groups {
+		var names []string
+
+		var count int
+
+		for i := range ti.valueFields {
+			fi := &ti.valueFields[i]
+			if fi.flags&fOneOf == 0 || fi.oneOf != group {
+				continue
+			}
+
+			names = append(names, fi.name)
+
+			if present[i] {
+				count++
+			}
+		}
+
+		switch {
+		case count == 0:
+			return validationError(ti.typ, strings.Join(names, "|"), TagNone, ErrMissingValue)
+		case count > 1:
+			return validationError(ti.typ, strings.Join(names, "|"), TagNone, ErrMultipleValues)
+		}
+	}
+
+	return nil
+}
+
+// inRange checks a value against the field's min and max flags.  Numbers are
+// compared by value.  Strings, byte strings, and slices are compared by length.
+func (fi *fieldInfo) inRange(v reflect.Value) bool {
+	v = indirect(v)
+	if !v.IsValid() {
+		return true
+	}
+
+	if v.Type() == bigIntType {
+		i := v.Interface().(big.Int) //nolint:forcetypeassert
+		if fi.flags&fMin != 0 && i.Cmp(big.NewInt(fi.min)) < 0 {
+			return false
+		}
+
+		return fi.flags&fMax == 0 || i.Cmp(big.NewInt(fi.max)) <= 0
+	}
+
+	var n int64
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := v.Uint()
+		if u > math.MaxInt64 {
+			u = math.MaxInt64
+		}
+
+		n = int64(u)
+	case reflect.String, reflect.Slice, reflect.Array:
+		n = int64(v.Len())
+	default:
+		return true
+	}
+
+	if fi.flags&fMin != 0 && n < fi.min {
+		return false
+	}
+
+	return fi.flags&fMax == 0 || n <= fi.max
+}
@@ -0,0 +1,369 @@
+package ttlv
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/Seagate/kmip-go/internal/kmiputil"
+	"github.com/ansel1/merry"
+)
+
+// ErrNotInVersion is the cause of errors returned when a value is not defined in the
+// version of the KMIP spec a View is scoped to.
+var ErrNotInVersion = merry.New("value not defined in protocol version")
+
+// Version identifies a version of the KMIP specification.
+type Version struct {
+	Major, Minor int
+}
+
+func (v Version) String() string {
+	return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor)
+}
+
+// Before returns true if v is an earlier version than o.
+func (v Version) Before(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+
+	return v.Minor < o.Minor
+}
+
+// Availability records which versions of the KMIP spec define a tag or an
+// enum value.  The zero value means the tag or value is defined in every version.
+type Availability struct {
+	// Introduced is the first version which defines the value.
+	Introduced Version
+	// Deprecated, if not zero, is the version in which the value was deprecated
+	// and removed from the spec.  The value is not available in that version or later.
+	Deprecated Version
+}
+
+// In returns true if the value is defined in version v.
+func (a Availability) In(v Version) bool {
+	if v.Before(a.Introduced) {
+		return false
+	}
+
+	return a.Deprecated == Version{} || v.Before(a.Deprecated)
+}
+
+// SetTagAvailability records the versions of the spec which define a tag.
+func (r *Registry) SetTagAvailability(t Tag, a Availability) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tagAvailability == nil {
+		r.tagAvailability = map[Tag]Availability{}
+	}
+
+	r.tagAvailability[t] = a
+}
+
+// TagAvailability returns the versions of the spec which define a tag.
+func (r *Registry) TagAvailability(t Tag) Availability {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.tagAvailability[t]
+}
+
+// SetEnumValueAvailability records the versions of the spec which define an enum or bitmask
+// value of the enum registered for a tag.
+func (r *Registry) SetEnumValueAvailability(t Tag, v uint32, a Availability) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.enumAvailability == nil {
+		r.enumAvailability = map[Tag]map[uint32]Availability{}
+	}
+
+	if r.enumAvailability[t] == nil {
+		r.enumAvailability[t] = map[uint32]Availability{}
+	}
+
+	r.enumAvailability[t][v] = a
+}
+
+// EnumValueAvailability returns the versions of the spec which define an enum or
+// bitmask value of the enum registered for a tag.
+func (r *Registry) EnumValueAvailability(t Tag, v uint32) Availability {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.enumAvailability[t][v]
+}
+
+// RegisterSince calls register, and records every tag and enum value it adds to the
+// registry as introduced in version v.  Enum values which are dropped because register
+// replaces a tag's enum with one which no longer contains them are recorded as
+// deprecated in version v.
+//
+//	registry.RegisterSince(ttlv.Version{Major: 2}, func(r *ttlv.Registry) {
+//	    r.RegisterTag(TagProtectionLevel, "Protection Level")
+//	})
+func (r *Registry) RegisterSince(v Version, register func(r *Registry)) {
+	tagsBefore := r.tagSet()
+	enumsBefore := map[Tag]map[uint32]bool{}
+
+	for t, e := range r.enumsCopy() {
+		enumsBefore[t] = valueSet(e)
+	}
+
+	register(r)
+
+	for t := range r.tagSet() {
+		if !tagsBefore[t] {
+			r.SetTagAvailability(Tag(t), Availability{Introduced: v})
+		}
+	}
+
+	for t, e := range r.enumsCopy() {
+		before, ok := enumsBefore[t]
+		if !ok {
+			// the tag had no enum before.  Either the tag itself is new, or
+			// it was redefined as an enum, in which case the change is in the tag's
+			// type, not in its values.
+			continue
+		}
+
+		after := valueSet(e)
+
+		for ev := range after {
+			if !before[ev] {
+				r.SetEnumValueAvailability(t, ev, Availability{Introduced: v})
+			}
+		}
+
+		for ev := range before {
+			if !after[ev] {
+				a := r.EnumValueAvailability(t, ev)
+				a.Deprecated = v
+				r.SetEnumValueAvailability(t, ev, a)
+			}
+		}
+	}
+}
+
+// tagSet returns the set of registered tags.
+func (r *Registry) tagSet() map[uint32]bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return valueSet(&r.tags)
+}
+
+func valueSet(e EnumMap) map[uint32]bool {
+	values := e.Values()
+
+	set := make(map[uint32]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+
+	return set
+}
+
+// ForVersion returns a view of the registry scoped to a single version of the KMIP spec.
+func (r *Registry) ForVersion(v Version) *View {
+	return &View{registry: r, version: v}
+}
+
+// View is a read-only view of a Registry which only contains the tags and enum values
+// defined in a single version of the KMIP spec.  Encoders and Decoders with a View
+// reject values which the version doesn't define.
+//
+// Tags and enum values which are not registered at all are not checked, so vendor
+// extensions pass through a View.
+type View struct {
+	registry *Registry
+	version  Version
+}
+
+// Version returns the version of the spec the View is scoped to.
+func (v *View) Version() Version {
+	return v.version
+}
+
+// Registry returns the underlying Registry.
+func (v *View) Registry() *Registry {
+	return v.registry
+}
+
+// TagAvailable returns true if the tag is defined in the view's version.
+func (v *View) TagAvailable(t Tag) bool {
+	return v.registry.TagAvailability(t).In(v.version)
+}
+
+// EnumValueAvailable returns true if the enum or bitmask value of the enum registered
+// for the tag is defined in the view's version.
+func (v *View) EnumValueAvailable(t Tag, value uint32) bool {
+	return v.registry.EnumValueAvailability(t, value).In(v.version)
+}
+
+// Tags returns the tags defined in the view's version.
+func (v *View) Tags() EnumMap {
+	return &versionedEnum{EnumMap: v.registry.Tags(), available: func(value uint32) bool {
+		return v.TagAvailable(Tag(value))
+	}}
+}
+
+// EnumForTag returns the enum registered for a tag, restricted to the values defined
+// in the view's version.  Returns nil if no enum is registered for the tag, or the tag
+// itself is not defined in the view's version.
+func (v *View) EnumForTag(t Tag) EnumMap {
+	e := v.registry.EnumForTag(t)
+	if e == nil || !v.TagAvailable(t) {
+		return nil
+	}
+
+	return &versionedEnum{EnumMap: e, available: func(value uint32) bool {
+		return v.EnumValueAvailable(t, value)
+	}}
+}
+
+// NewEncoder returns an Encoder which rejects values not defined in the view's version.
+func (v *View) NewEncoder(w io.Writer) *Encoder {
+	e := NewEncoder(w)
+	e.View = v
+
+	return e
+}
+
+// NewDecoder returns a Decoder which rejects values not defined in the view's version.
+func (v *View) NewDecoder(r io.Reader) *Decoder {
+	d := NewDecoder(r)
+	d.View = v
+
+	return d
+}
+
+// Validate checks that every tag, enum value, and bitmask value in t is defined in
+// the view's version.  Returns an error with cause ErrNotInVersion for the first value
+// which isn't.  Enum values of AttributeValues are checked against the enum of the tag
+// named by the preceding AttributeName.
+func (v *View) Validate(t TTLV) error {
+	for ; len(t) > 0; t = t.Next() {
+		if err := t.Valid(); err != nil {
+			return err
+		}
+
+		if err := v.validate(t, t.Tag()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate checks a single, valid value.  enumTag is the tag whose enum
+// the value is checked against.
+func (v *View) validate(t TTLV, enumTag Tag) error {
+	var n uint32
+
+	switch t.Type() {
+	case TypeEnumeration:
+		n = uint32(t.ValueEnumeration())
+	case TypeInteger:
+		n = uint32(t.ValueInteger())
+	default:
+	}
+
+	if err := v.validateValue(t.Tag(), t.Type(), enumTag, n); err != nil {
+		return err
+	}
+
+	if t.Type() != TypeStructure {
+		return nil
+	}
+
+	var attrTag Tag
+
+	for c := t.ValueStructure(); len(c) > 0; c = c.Next() {
+		// as with the JSON and XML encodings, use the attribute name to map the
+		// attribute value to an enum
+		if c.Tag() == tagAttributeName && c.Type() == TypeTextString {
+			attrTag, _ = v.registry.ParseTag(kmiputil.NormalizeName(c.ValueTextString()))
+		}
+
+		if err := v.validate(c, enumTagFor(c.Tag(), attrTag)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateValue checks the tag of a value with type typ, and its value n, if it is an
+// Enumeration, or an Integer which enumTag registers as a bitmask.  The values of
+// Structures aren't checked.
+func (v *View) validateValue(tag Tag, typ Type, enumTag Tag, n uint32) error {
+	if !v.TagAvailable(tag) {
+		return merry.Here(ErrNotInVersion).Appendf("tag %s is not defined in %s", tag, v.version)
+	}
+
+	switch typ {
+	case TypeEnumeration:
+		if !v.EnumValueAvailable(enumTag, n) {
+			return merry.Here(ErrNotInVersion).Appendf("%s value %s is not defined in %s", tag, v.registry.FormatEnum(enumTag, n), v.version)
+		}
+	case TypeInteger:
+		if !v.registry.IsBitmask(enumTag) {
+			break
+		}
+
+		for bit := uint32(1); bit != 0 && bit <= n; bit <<= 1 {
+			if n&bit != 0 && !v.EnumValueAvailable(enumTag, bit) {
+				return merry.Here(ErrNotInVersion).Appendf("%s value %s is not defined in %s", tag, v.registry.FormatInt(enumTag, int32(bit)), v.version)
+			}
+		}
+	default:
+	}
+
+	return nil
+}
+
+// versionedEnum filters an EnumMap to the values available in a version.
+type versionedEnum struct {
+	EnumMap
+	available func(v uint32) bool
+}
+
+func (e *versionedEnum) Name(v uint32) (string, bool) {
+	if !e.available(v) {
+		return "", false
+	}
+
+	return e.EnumMap.Name(v)
+}
+
+func (e *versionedEnum) CanonicalName(v uint32) (string, bool) {
+	if !e.available(v) {
+		return "", false
+	}
+
+	return e.EnumMap.CanonicalName(v)
+}
+
+func (e *versionedEnum) Value(name string) (uint32, bool) {
+	v, ok := e.EnumMap.Value(name)
+	if !ok || !e.available(v) {
+		return 0, false
+	}
+
+	return v, true
+}
+
+func (e *versionedEnum) Values() []uint32 {
+	values := e.EnumMap.Values()
+	filtered := values[:0]
+
+	for _, v := range values {
+		if e.available(v) {
+			filtered = append(filtered, v)
+		}
+	}
+
+	return filtered
+}
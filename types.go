@@ -9,6 +9,7 @@ type Nonce struct {
 	NonceValue []byte
 }
 
+//ttlvgen:generate
 type ProtocolVersion struct {
 	ProtocolVersionMajor int
 	ProtocolVersionMinor int
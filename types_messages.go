@@ -8,11 +8,13 @@ import (
 
 // 7.1
 
+//ttlvgen:generate
 type RequestMessage struct {
 	RequestHeader RequestHeader
 	BatchItem     []RequestBatchItem
 }
 
+//ttlvgen:generate
 type ResponseMessage struct {
 	ResponseHeader ResponseHeader
 	BatchItem      []ResponseBatchItem
@@ -20,6 +22,7 @@ type ResponseMessage struct {
 
 // 7.2
 
+//ttlvgen:generate
 type RequestHeader struct {
 	ProtocolVersion              ProtocolVersion
 	MaximumResponseSize          int    `ttlv:",omitempty"`
@@ -35,6 +38,7 @@ type RequestHeader struct {
 	BatchCount                   int
 }
 
+//ttlvgen:generate
 type RequestBatchItem struct {
 	Operation         kmip14.Operation
 	UniqueBatchItemID []byte `ttlv:",omitempty"`
@@ -42,6 +46,7 @@ type RequestBatchItem struct {
 	MessageExtension  *MessageExtension `ttlv:",omitempty"`
 }
 
+//ttlvgen:generate
 type ResponseHeader struct {
 	ProtocolVersion        ProtocolVersion
 	TimeStamp              time.Time
@@ -52,6 +57,7 @@ type ResponseHeader struct {
 	BatchCount             int
 }
 
+//ttlvgen:generate
 type ResponseBatchItem struct {
 	Operation                    kmip14.Operation `ttlv:",omitempty"`
 	UniqueBatchItemID            []byte           `ttlv:",omitempty"`